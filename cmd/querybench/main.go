@@ -0,0 +1,406 @@
+// Команда querybench сравнивает планы запросов списка песен до и после перехода на динамический WHERE.
+//
+// Песни генерируются в отдельной схеме (по умолчанию querybench) в таблице, повторяющей
+// структуру и индексы таблиц песен и исполнителей из public, поэтому перед запуском к базе должны быть применены миграции.
+// Для каждого сценария фильтрации выполняется EXPLAIN ANALYZE запроса страницы и количества:
+//   - legacy-generic - прежний запрос с условиями для всех полей фильтра и общим планом, как у подготовленного оператора;
+//   - legacy-custom  - прежний запрос с планом под конкретные параметры;
+//   - builder        - запрос, собранный только из заполненных полей фильтра.
+//
+// С флагом -format markdown сводка "до и после" выводится в виде таблиц Markdown.
+//
+// Пример: go run ./cmd/querybench -rows 1000000
+package main
+
+import (
+	"context"
+	"crypto/md5"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ZnNr/songs-library/config"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository/database"
+	"github.com/joho/godotenv"
+	"github.com/lib/pq"
+)
+
+// Прежние запросы, проверяющие в SQL заполненность каждого поля фильтра, сохраненные для сравнения.
+const (
+	legacyReleaseDateEndExpr = `(s.release_date + CASE s.release_date_precision
+			WHEN 'year' THEN INTERVAL '1 year'
+			WHEN 'month' THEN INTERVAL '1 month'
+			ELSE INTERVAL '1 day' END - INTERVAL '1 day')`
+
+	legacyWhere = `
+		WHERE s.deleted_at IS NULL
+		AND ($1 = '' OR a.name ILIKE '%' || $1 || '%')
+		AND ($2 = '' OR s.song_name ILIKE '%' || $2 || '%')
+		AND ($3::timestamp IS NULL OR ` + legacyReleaseDateEndExpr + ` >= $3)
+		AND ($4::timestamp IS NULL OR s.release_date <= $4)
+		AND ($5 = '' OR s.text ILIKE '%' || $5 || '%')
+		AND ($6 = '' OR s.link ILIKE '%' || $6 || '%')`
+
+	legacySongsQuery = `
+		SELECT s.id, s.artist_id, a.name, s.song_name, s.release_date, s.release_date_precision, s.text, s.link,
+			s.created_at, s.updated_at, s.version
+		FROM songs s JOIN artists a ON a.id = s.artist_id` + legacyWhere + `
+		ORDER BY s.created_at DESC
+		LIMIT $7 OFFSET $8`
+
+	legacyCountQuery = `SELECT COUNT(*) FROM songs s JOIN artists a ON a.id = s.artist_id` + legacyWhere
+
+	legacyParamTypes = `text, text, timestamp, timestamp, text, text`
+)
+
+// seedArtists - количество исполнителей, между которыми распределяются песни.
+const seedArtists = 50000
+
+// seedArtistsQuery создает исполнителей Group 0 ... Group N-1 с идентификаторами 1 ... N.
+const seedArtistsQuery = `
+	INSERT INTO artists (id, name)
+	SELECT i + 1, 'Group ' || i
+	FROM generate_series(0, $1::integer - 1) AS i
+	ON CONFLICT DO NOTHING`
+
+// seedQuery заполняет таблицу песнями: 50 000 исполнителей, даты выпуска за 1960-2022 годы
+// и уникальные названия на основе md5, чтобы фильтр по подстроке был селективным.
+const seedQuery = `
+	INSERT INTO songs (id, artist_id, song_name, release_date, release_date_precision, text, link, created_at, updated_at)
+	SELECT i,
+		(i % $3::integer) + 1,
+		'Song ' || md5(i::text),
+		DATE '1960-01-01' + (i % 23000),
+		CASE WHEN i % 10 = 0 THEN 'year' WHEN i % 10 = 1 THEN 'month' ELSE 'day' END,
+		md5(i::text) || ' ' || md5((i + 1)::text),
+		'https://example.com/songs/' || i,
+		TIMESTAMP '2020-01-01' + i * INTERVAL '1 minute',
+		TIMESTAMP '2020-01-01' + i * INTERVAL '1 minute'
+	FROM generate_series($1::integer + 1, $2::integer) AS i`
+
+// scenario - сценарий фильтрации списка песен.
+type scenario struct {
+	name   string
+	filter models.SongFilter
+}
+
+// modes - сравниваемые варианты запроса в порядке вывода.
+var modes = []string{"legacy-generic", "legacy-custom", "builder"}
+
+// result - планы запроса kind сценария во всех режимах.
+type result struct {
+	scenario string
+	kind     string
+	plans    map[string]plan
+}
+
+// plan - результат EXPLAIN ANALYZE одного запроса.
+type plan struct {
+	planning  float64
+	execution float64
+	nodes     string
+}
+
+func main() {
+	rows := flag.Int("rows", 1000000, "number of songs to seed")
+	schema := flag.String("schema", "querybench", "schema for the seeded songs table")
+	drop := flag.Bool("drop", false, "drop the benchmark schema after the run")
+	format := flag.String("format", "table", "output format: table or markdown")
+	flag.Parse()
+
+	if *format != "table" && *format != "markdown" {
+		log.Fatalf("unknown format %q", *format)
+	}
+	if err := run(*rows, *schema, *format, *drop); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(rows int, schema, format string, drop bool) error {
+	// Файл .env необязателен: переменные окружения могут быть заданы явно
+	_ = godotenv.Load()
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	db, err := sql.Open("postgres", cfg.GetDBConnString())
+	if err != nil {
+		return fmt.Errorf("failed to open database connection: %w", err)
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	// search_path, подготовленные операторы и plan_cache_mode действуют в пределах соединения
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close()
+
+	if err := seed(ctx, conn, schema, rows); err != nil {
+		return err
+	}
+	if drop {
+		defer func() {
+			if _, err := conn.ExecContext(ctx, "DROP SCHEMA "+pq.QuoteIdentifier(schema)+" CASCADE"); err != nil {
+				log.Printf("failed to drop schema %s: %v", schema, err)
+			}
+		}()
+	}
+
+	var results []result
+	for _, sc := range scenarios() {
+		for _, kind := range []string{"page", "count"} {
+			plans, err := explainScenario(ctx, conn, sc, kind)
+			if err != nil {
+				return fmt.Errorf("scenario %s (%s): %w", sc.name, kind, err)
+			}
+			results = append(results, result{scenario: sc.name, kind: kind, plans: plans})
+		}
+	}
+
+	if format == "markdown" {
+		var version string
+		if err := conn.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+			return fmt.Errorf("failed to read server version: %w", err)
+		}
+		printMarkdown(results, version, rows)
+		return nil
+	}
+	return printTable(results)
+}
+
+// printTable выводит планы всех запросов в виде таблицы.
+func printTable(results []result) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SCENARIO\tQUERY\tMODE\tPLANNING, ms\tEXECUTION, ms\tPLAN")
+	for _, res := range results {
+		for _, mode := range modes {
+			p := res.plans[mode]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%.2f\t%s\n", res.scenario, res.kind, mode, p.planning, p.execution, p.nodes)
+		}
+	}
+	return w.Flush()
+}
+
+// printMarkdown выводит сводку "до и после": время выполнения каждого режима,
+// ускорение builder относительно прежнего общего плана и узлы планов.
+func printMarkdown(results []result, version string, rows int) {
+	fmt.Println("# Планы запросов списка песен")
+	fmt.Println()
+	fmt.Printf("PostgreSQL %s, %d песен, %s. Получено командой `go run ./cmd/querybench -rows %d -format markdown`.\n",
+		version, rows, time.Now().Format("2006-01-02"), rows)
+	fmt.Println()
+	fmt.Println("Время выполнения (Execution Time) в миллисекундах; ускорение - legacy-generic / builder.")
+	fmt.Println()
+	fmt.Println("| Сценарий | Запрос | legacy-generic | legacy-custom | builder | Ускорение |")
+	fmt.Println("|---|---|---:|---:|---:|---:|")
+	for _, res := range results {
+		generic, builder := res.plans["legacy-generic"].execution, res.plans["builder"].execution
+		speedup := "-"
+		if builder > 0 {
+			speedup = fmt.Sprintf("%.1fx", generic/builder)
+		}
+		fmt.Printf("| %s | %s | %.2f | %.2f | %.2f | %s |\n",
+			res.scenario, res.kind, generic, res.plans["legacy-custom"].execution, builder, speedup)
+	}
+	fmt.Println()
+	fmt.Println("## Планы")
+	fmt.Println()
+	fmt.Println("| Сценарий | Запрос | Режим | План |")
+	fmt.Println("|---|---|---|---|")
+	for _, res := range results {
+		for _, mode := range modes {
+			fmt.Printf("| %s | %s | %s | %s |\n", res.scenario, res.kind, mode, res.plans[mode].nodes)
+		}
+	}
+}
+
+// seed создает схему с таблицами песен и исполнителей по образцу public и дополняет songs до rows строк.
+func seed(ctx context.Context, conn *sql.Conn, schema string, rows int) error {
+	ident := pq.QuoteIdentifier(schema)
+	statements := []string{
+		"CREATE SCHEMA IF NOT EXISTS " + ident,
+		// Идентификаторы задаются явно, чтобы не расходовать последовательности public.songs и public.artists
+		"CREATE TABLE IF NOT EXISTS " + ident + ".artists (LIKE public.artists INCLUDING ALL)",
+		"CREATE TABLE IF NOT EXISTS " + ident + ".artist_aliases (LIKE public.artist_aliases INCLUDING ALL)",
+		"CREATE TABLE IF NOT EXISTS " + ident + ".songs (LIKE public.songs INCLUDING ALL)",
+		"SET search_path TO " + ident + ", public",
+	}
+	for _, stmt := range statements {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to prepare schema: %w", err)
+		}
+	}
+
+	if _, err := conn.ExecContext(ctx, seedArtistsQuery, seedArtists); err != nil {
+		return fmt.Errorf("failed to seed artists: %w", err)
+	}
+
+	var existing int
+	if err := conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(id), 0) FROM songs").Scan(&existing); err != nil {
+		return fmt.Errorf("failed to count seeded songs: %w", err)
+	}
+	if existing >= rows {
+		log.Printf("Using %d existing songs in schema %s", existing, schema)
+		return nil
+	}
+
+	start := time.Now()
+	const batch = 100000
+	for from := existing; from < rows; from += batch {
+		to := min(from+batch, rows)
+		if _, err := conn.ExecContext(ctx, seedQuery, from, to, seedArtists); err != nil {
+			return fmt.Errorf("failed to seed songs: %w", err)
+		}
+		log.Printf("Seeded %d/%d songs", to, rows)
+	}
+	if _, err := conn.ExecContext(ctx, "ANALYZE artists, songs"); err != nil {
+		return fmt.Errorf("failed to analyze songs: %w", err)
+	}
+	log.Printf("Seeded %d songs in %s", rows-existing, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// scenarios возвращает типичные сценарии фильтрации списка песен.
+func scenarios() []scenario {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+	hash := md5.Sum([]byte("777"))
+	songName := hex.EncodeToString(hash[:])[:10]
+
+	return []scenario{
+		{name: "no-filter", filter: models.SongFilter{}},
+		{name: "group", filter: models.SongFilter{GroupName: "Group 4242"}},
+		{name: "song", filter: models.SongFilter{SongName: songName}},
+		{name: "release-year", filter: models.SongFilter{FromDate: &from, ToDate: &to}},
+		{name: "group+year", filter: models.SongFilter{GroupName: "Group 4242", FromDate: &from, ToDate: &to}},
+		{name: "text", filter: models.SongFilter{Text: songName}},
+	}
+}
+
+// explainScenario выполняет EXPLAIN ANALYZE запроса kind (page или count) во всех режимах.
+func explainScenario(ctx context.Context, conn *sql.Conn, sc scenario, kind string) (map[string]plan, error) {
+	filter := sc.filter
+	filter.Page, filter.PageSize = 1, 10
+
+	legacyQuery, builderQuery := legacySongsQuery, ""
+	legacyTypes := legacyParamTypes + ", integer, integer"
+	legacyArgs := []interface{}{filter.GroupName, filter.SongName, filter.FromDate, filter.ToDate, filter.Text, filter.Link}
+	var builderArgs []interface{}
+	if kind == "page" {
+		legacyArgs = append(legacyArgs, filter.PageSize, 0)
+		builderQuery, builderArgs = database.SongsQuery(&filter, filter.PageSize, 0)
+	} else {
+		legacyQuery, legacyTypes = legacyCountQuery, legacyParamTypes
+		builderQuery, builderArgs = database.CountSongsQuery(&filter)
+	}
+
+	results := make(map[string]plan, len(modes))
+	var err error
+
+	// Общий план подготовленного оператора не зависит от значений параметров
+	if _, err = conn.ExecContext(ctx, "SET plan_cache_mode = force_generic_plan"); err != nil {
+		return nil, err
+	}
+	if _, err = conn.ExecContext(ctx, "PREPARE legacy ("+legacyTypes+") AS "+legacyQuery); err != nil {
+		return nil, err
+	}
+	results["legacy-generic"], err = explain(ctx, conn, "EXECUTE legacy("+literals(legacyArgs)+")")
+	if _, deallocErr := conn.ExecContext(ctx, "DEALLOCATE legacy"); err == nil {
+		err = deallocErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err = conn.ExecContext(ctx, "RESET plan_cache_mode"); err != nil {
+		return nil, err
+	}
+
+	if results["legacy-custom"], err = explain(ctx, conn, legacyQuery, legacyArgs...); err != nil {
+		return nil, err
+	}
+	if results["builder"], err = explain(ctx, conn, builderQuery, builderArgs...); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// explain выполняет EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) и извлекает время и узлы плана.
+func explain(ctx context.Context, conn *sql.Conn, query string, args ...interface{}) (plan, error) {
+	var raw []byte
+	if err := conn.QueryRowContext(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query, args...).Scan(&raw); err != nil {
+		return plan{}, err
+	}
+
+	var result []struct {
+		Plan          planNode `json:"Plan"`
+		PlanningTime  float64  `json:"Planning Time"`
+		ExecutionTime float64  `json:"Execution Time"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return plan{}, fmt.Errorf("failed to parse plan: %w", err)
+	}
+	if len(result) == 0 {
+		return plan{}, fmt.Errorf("empty plan")
+	}
+
+	var nodes []string
+	result[0].Plan.collect(&nodes)
+	return plan{
+		planning:  result[0].PlanningTime,
+		execution: result[0].ExecutionTime,
+		nodes:     strings.Join(nodes, " > "),
+	}, nil
+}
+
+// planNode - узел плана запроса в формате EXPLAIN JSON.
+type planNode struct {
+	NodeType  string     `json:"Node Type"`
+	IndexName string     `json:"Index Name"`
+	Plans     []planNode `json:"Plans"`
+}
+
+// collect перечисляет узлы плана в порядке обхода в глубину, указывая используемые индексы.
+func (n planNode) collect(nodes *[]string) {
+	name := n.NodeType
+	if n.IndexName != "" {
+		name += " (" + n.IndexName + ")"
+	}
+	*nodes = append(*nodes, name)
+	for _, child := range n.Plans {
+		child.collect(nodes)
+	}
+}
+
+// literals форматирует параметры как SQL-литералы для EXECUTE.
+func literals(args []interface{}) string {
+	values := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case string:
+			values[i] = pq.QuoteLiteral(v)
+		case int:
+			values[i] = strconv.Itoa(v)
+		case *time.Time:
+			if v == nil {
+				values[i] = "NULL"
+			} else {
+				values[i] = pq.QuoteLiteral(v.Format(time.RFC3339))
+			}
+		default:
+			values[i] = "NULL"
+		}
+	}
+	return strings.Join(values, ", ")
+}
@@ -2,7 +2,14 @@ package config
 
 import (
 	"fmt"
+	"net/netip"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/repository"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // Config содержит конфигурацию приложения, включая настройки базы данных и сервера.
@@ -13,6 +20,55 @@ type Config struct {
 	DBPassword string // Пароль базы данных
 	DBName     string // Имя базы данных
 	ServerPort string // Порт сервера приложения
+
+	DBReplicaDSNs              []string      // Строки подключения к репликам для чтения
+	ReplicaHealthCheckInterval time.Duration // Периодичность проверки доступности реплик
+
+	DBTxIsolation repository.IsolationLevel // Уровень изоляции транзакций репозитория по умолчанию
+
+	RequireIfMatch bool // Требовать заголовок If-Match при изменении и удалении песен
+
+	AdminPrincipals []string          // Пользователи, которым доступны административные маршруты /api/v1/admin
+	TrustedProxies  []netip.Prefix    // Адреса прокси, которым разрешено передавать пользователя в X-Forwarded-User
+	BasicAuthUsers  map[string]string // Пользователи Basic-аутентификации и bcrypt-хэши их паролей
+
+	TrashRetention     time.Duration // Срок хранения песен в корзине до окончательного удаления
+	TrashPurgeInterval time.Duration // Периодичность очистки корзины
+
+	TracingExporter     string // Экспортер трассировок: none, stdout или otlp
+	TracingOTLPEndpoint string // Адрес коллектора OTLP/HTTP (host:port)
+	TracingOTLPInsecure bool   // Отправлять трассировки в коллектор без TLS
+
+	HealthCheckTimeout       time.Duration // Ограничение времени проверок готовности
+	EnrichmentAPIURL         string        // Адрес внешнего API обогащения для проверки доступности (необязательный)
+	EnrichmentHealthCacheTTL time.Duration // Время кэширования результата проверки API обогащения
+	ShutdownDrainDelay       time.Duration // Пауза между отказом проверки готовности и остановкой HTTP сервера
+
+	RequestTimeout time.Duration            // Дедлайн обработки запроса по умолчанию (0 - без ограничения)
+	RouteTimeouts  map[string]time.Duration // Дедлайны отдельных маршрутов по ключу "METHOD /шаблон/маршрута"
+	MaxBodyBytes   int64                    // Максимальный размер тела запроса в байтах (0 - без ограничения)
+
+	CORSAllowedOrigins   []string      // Источники, которым разрешены кросс-доменные запросы ("*" - любые)
+	CORSAllowedMethods   []string      // Методы, разрешенные для кросс-доменных запросов
+	CORSAllowedHeaders   []string      // Заголовки, разрешенные в кросс-доменных запросах
+	CORSExposedHeaders   []string      // Заголовки ответа, доступные браузерному клиенту
+	CORSAllowCredentials bool          // Разрешить передачу учетных данных в кросс-доменных запросах
+	CORSMaxAge           time.Duration // Время кэширования ответа на предварительный запрос
+
+	HSTSMaxAge   time.Duration // Срок действия Strict-Transport-Security (0 - отключено)
+	FrameOptions string        // Значение заголовка X-Frame-Options
+
+	CompressionEnabled bool              // Сжимать ответы gzip или br по заголовку Accept-Encoding
+	CompressionMinSize int               // Минимальный размер ответа в байтах для сжатия
+	CacheControl       string            // Заголовок Cache-Control для GET запросов по умолчанию
+	CacheControlRoutes map[string]string // Заголовок Cache-Control отдельных маршрутов по ключу "METHOD /шаблон/маршрута"
+
+	RepositoryCacheEnabled      bool          // Кэшировать чтение песен в памяти
+	RepositoryCacheTTL          time.Duration // Время жизни элемента кэша
+	RepositoryCacheMaxSongs     int           // Максимальное количество песен в кэше
+	RepositoryCacheMaxLists     int           // Максимальное количество страниц списка песен в кэше
+	RepositoryCacheSingleFlight bool          // Объединять одновременные загрузки одного ключа
+	RepositoryCacheLoadTimeout  time.Duration // Ограничение времени общей загрузки в режиме single-flight
 }
 
 // Load загружает конфигурацию из переменных окружения
@@ -31,14 +87,117 @@ func Load() (*Config, error) {
 
 // LoadConfig инициализирует конфигурацию из переменных окружения с значениями по умолчанию.
 func LoadConfig() (*Config, error) {
-	return &Config{
+	config := &Config{
 		DBHost:     getEnv("DB_HOST", "localhost"),
 		DBPort:     getEnv("DB_PORT", "5432"),
 		DBUser:     getEnv("DB_USER", "postgres"),
 		DBPassword: getEnv("DB_PASSWORD", "postgres"),
 		DBName:     getEnv("DB_NAME", "songs-library"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
-	}, nil
+
+		DBReplicaDSNs: getEnvList("DB_REPLICA_DSNS", nil),
+
+		AdminPrincipals: getEnvList("ADMIN_PRINCIPALS", nil),
+
+		TracingExporter:     getEnv("TRACING_EXPORTER", "none"),
+		TracingOTLPEndpoint: getEnv("TRACING_OTLP_ENDPOINT", "localhost:4318"),
+
+		EnrichmentAPIURL: getEnv("ENRICHMENT_API_URL", ""),
+
+		CORSAllowedOrigins: getEnvList("CORS_ALLOWED_ORIGINS", nil),
+		CORSAllowedMethods: getEnvList("CORS_ALLOWED_METHODS",
+			[]string{"GET", "POST", "PUT", "PATCH", "DELETE"}),
+		CORSAllowedHeaders: getEnvList("CORS_ALLOWED_HEADERS",
+			[]string{"Content-Type", "Authorization", "If-Match", "If-None-Match", "X-Request-ID", "X-Read-Your-Writes"}),
+		CORSExposedHeaders: getEnvList("CORS_EXPOSED_HEADERS",
+			[]string{"ETag", "Location", "X-Request-ID"}),
+
+		FrameOptions: strings.ToUpper(getEnv("FRAME_OPTIONS", "DENY")),
+
+		CacheControl: getEnv("CACHE_CONTROL", "no-cache"),
+	}
+
+	var err error
+	if config.RequireIfMatch, err = getEnvBool("REQUIRE_IF_MATCH", false); err != nil {
+		return nil, err
+	}
+	if config.ReplicaHealthCheckInterval, err = getEnvDuration("REPLICA_HEALTH_CHECK_INTERVAL", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if config.DBTxIsolation, err = repository.ParseIsolationLevel(getEnv("DB_TX_ISOLATION", "read_committed")); err != nil {
+		return nil, fmt.Errorf("invalid DB_TX_ISOLATION: %w", err)
+	}
+	if config.TrashRetention, err = getEnvDuration("TRASH_RETENTION", 30*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if config.TrashPurgeInterval, err = getEnvDuration("TRASH_PURGE_INTERVAL", time.Hour); err != nil {
+		return nil, err
+	}
+	if config.TracingOTLPInsecure, err = getEnvBool("TRACING_OTLP_INSECURE", false); err != nil {
+		return nil, err
+	}
+	if config.HealthCheckTimeout, err = getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second); err != nil {
+		return nil, err
+	}
+	if config.EnrichmentHealthCacheTTL, err = getEnvDuration("ENRICHMENT_HEALTH_CACHE_TTL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if config.ShutdownDrainDelay, err = getEnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second); err != nil {
+		return nil, err
+	}
+	if config.RequestTimeout, err = getEnvDuration("REQUEST_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if config.RouteTimeouts, err = parseRouteTimeouts(getEnv("ROUTE_TIMEOUTS", "")); err != nil {
+		return nil, err
+	}
+	if config.MaxBodyBytes, err = getEnvInt64("MAX_BODY_BYTES", 1<<20); err != nil {
+		return nil, err
+	}
+	if config.CORSAllowCredentials, err = getEnvBool("CORS_ALLOW_CREDENTIALS", false); err != nil {
+		return nil, err
+	}
+	if config.CORSMaxAge, err = getEnvDuration("CORS_MAX_AGE", 10*time.Minute); err != nil {
+		return nil, err
+	}
+	if config.HSTSMaxAge, err = getEnvDuration("HSTS_MAX_AGE", 365*24*time.Hour); err != nil {
+		return nil, err
+	}
+	if config.CompressionEnabled, err = getEnvBool("COMPRESSION_ENABLED", true); err != nil {
+		return nil, err
+	}
+	if config.CompressionMinSize, err = getEnvInt("COMPRESSION_MIN_SIZE", 1024); err != nil {
+		return nil, err
+	}
+	if config.RepositoryCacheEnabled, err = getEnvBool("REPOSITORY_CACHE_ENABLED", true); err != nil {
+		return nil, err
+	}
+	if config.RepositoryCacheTTL, err = getEnvDuration("REPOSITORY_CACHE_TTL", 30*time.Second); err != nil {
+		return nil, err
+	}
+	if config.RepositoryCacheMaxSongs, err = getEnvInt("REPOSITORY_CACHE_MAX_SONGS", 1000); err != nil {
+		return nil, err
+	}
+	if config.RepositoryCacheMaxLists, err = getEnvInt("REPOSITORY_CACHE_MAX_LISTS", 100); err != nil {
+		return nil, err
+	}
+	if config.RepositoryCacheSingleFlight, err = getEnvBool("REPOSITORY_CACHE_SINGLE_FLIGHT", true); err != nil {
+		return nil, err
+	}
+	if config.RepositoryCacheLoadTimeout, err = getEnvDuration("REPOSITORY_CACHE_LOAD_TIMEOUT", 10*time.Second); err != nil {
+		return nil, err
+	}
+	if config.TrustedProxies, err = parseTrustedProxies(getEnvList("TRUSTED_PROXIES", nil)); err != nil {
+		return nil, err
+	}
+	if config.BasicAuthUsers, err = parseBasicAuthUsers(getEnvList("BASIC_AUTH_USERS", nil)); err != nil {
+		return nil, err
+	}
+	if config.CacheControlRoutes, err = parseRouteMap("CACHE_CONTROL_ROUTES", getEnv("CACHE_CONTROL_ROUTES", ""), ";"); err != nil {
+		return nil, err
+	}
+
+	return config, nil
 }
 
 // GetDBConnString формирует строку подключения к базе данных.
@@ -61,6 +220,148 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvBool возвращает логическое значение из переменной окружения или значение по умолчанию.
+func getEnvBool(key string, defaultValue bool) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid boolean in %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvDuration возвращает длительность из переменной окружения или значение по умолчанию.
+func getEnvDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration in %s: %w", key, err)
+	}
+	return duration, nil
+}
+
+// getEnvList возвращает список значений, перечисленных через запятую, или значение по умолчанию.
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// getEnvInt возвращает целое число из переменной окружения или значение по умолчанию.
+func getEnvInt(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer in %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// getEnvInt64 возвращает целое число из переменной окружения или значение по умолчанию.
+func getEnvInt64(key string, defaultValue int64) (int64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid integer in %s: %w", key, err)
+	}
+	return parsed, nil
+}
+
+// parseRouteTimeouts разбирает дедлайны маршрутов в формате
+// "GET /api/v1/songs=5s,DELETE /api/v1/trash/{id}=30s".
+func parseRouteTimeouts(value string) (map[string]time.Duration, error) {
+	routes, err := parseRouteMap("ROUTE_TIMEOUTS", value, ",")
+	if err != nil {
+		return nil, err
+	}
+
+	timeouts := make(map[string]time.Duration, len(routes))
+	for route, rawTimeout := range routes {
+		timeout, err := time.ParseDuration(rawTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration for route %q in ROUTE_TIMEOUTS: %w", route, err)
+		}
+		timeouts[route] = timeout
+	}
+	return timeouts, nil
+}
+
+// parseTrustedProxies разбирает адреса доверенных прокси: IP-адреса или подсети в нотации CIDR.
+func parseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	proxies := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		if addr, err := netip.ParseAddr(value); err == nil {
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address %q in TRUSTED_PROXIES: %w", value, err)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+// parseBasicAuthUsers разбирает пользователей Basic-аутентификации в формате "user:bcrypt-хэш".
+func parseBasicAuthUsers(values []string) (map[string]string, error) {
+	users := make(map[string]string, len(values))
+	for _, value := range values {
+		// bcrypt-хэш сам содержит "$", но не ":", поэтому имя отделяется по первому двоеточию
+		user, hash, ok := strings.Cut(value, ":")
+		if !ok || user == "" {
+			return nil, fmt.Errorf("invalid entry %q in BASIC_AUTH_USERS, expected \"user:bcrypt-hash\"", value)
+		}
+		if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+			return nil, fmt.Errorf("invalid password hash for user %q in BASIC_AUTH_USERS: %w", user, err)
+		}
+		users[user] = hash
+	}
+	return users, nil
+}
+
+// parseRouteMap разбирает значения для маршрутов в формате "METHOD /path=value",
+// перечисленные через separator. Ключ результата - "METHOD /path" с методом в верхнем регистре.
+func parseRouteMap(key, value, separator string) (map[string]string, error) {
+	routes := make(map[string]string)
+	if strings.TrimSpace(value) == "" {
+		return routes, nil
+	}
+
+	for _, entry := range strings.Split(value, separator) {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		route, routeValue, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPath || method == "" || strings.TrimSpace(path) == "" {
+			return nil, fmt.Errorf("invalid route entry %q in %s, expected \"METHOD /path=value\"", entry, key)
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = strings.TrimSpace(routeValue)
+	}
+	return routes, nil
+}
+
 // Validate проверяет, что важные параметры конфигурации заполнены.
 func (c *Config) Validate() error {
 	if c.DBHost == "" {
@@ -78,5 +379,72 @@ func (c *Config) Validate() error {
 	if c.ServerPort == "" {
 		return fmt.Errorf("ServerPort cannot be empty")
 	}
+	if len(c.DBReplicaDSNs) > 0 && c.ReplicaHealthCheckInterval <= 0 {
+		return fmt.Errorf("ReplicaHealthCheckInterval must be positive")
+	}
+	if c.TrashRetention <= 0 {
+		return fmt.Errorf("TrashRetention must be positive")
+	}
+	if c.TrashPurgeInterval <= 0 {
+		return fmt.Errorf("TrashPurgeInterval must be positive")
+	}
+	if c.HealthCheckTimeout <= 0 {
+		return fmt.Errorf("HealthCheckTimeout must be positive")
+	}
+	if c.ShutdownDrainDelay < 0 {
+		return fmt.Errorf("ShutdownDrainDelay cannot be negative")
+	}
+	if c.RequestTimeout < 0 {
+		return fmt.Errorf("RequestTimeout cannot be negative")
+	}
+	for route, timeout := range c.RouteTimeouts {
+		if timeout < 0 {
+			return fmt.Errorf("timeout for route %q cannot be negative", route)
+		}
+	}
+	if c.MaxBodyBytes < 0 {
+		return fmt.Errorf("MaxBodyBytes cannot be negative")
+	}
+	if c.CORSAllowCredentials {
+		for _, origin := range c.CORSAllowedOrigins {
+			if origin == "*" {
+				return fmt.Errorf("CORSAllowedOrigins cannot contain \"*\" when CORSAllowCredentials is enabled")
+			}
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		return fmt.Errorf("CORSMaxAge cannot be negative")
+	}
+	if c.HSTSMaxAge < 0 {
+		return fmt.Errorf("HSTSMaxAge cannot be negative")
+	}
+	if c.CompressionMinSize < 0 {
+		return fmt.Errorf("CompressionMinSize cannot be negative")
+	}
+	if c.RepositoryCacheEnabled {
+		if c.RepositoryCacheTTL <= 0 {
+			return fmt.Errorf("RepositoryCacheTTL must be positive")
+		}
+		if c.RepositoryCacheMaxSongs < 0 || c.RepositoryCacheMaxLists < 0 {
+			return fmt.Errorf("repository cache sizes cannot be negative")
+		}
+		if c.RepositoryCacheSingleFlight && c.RepositoryCacheLoadTimeout <= 0 {
+			return fmt.Errorf("RepositoryCacheLoadTimeout must be positive")
+		}
+	}
+	switch c.FrameOptions {
+	case "DENY", "SAMEORIGIN":
+	default:
+		return fmt.Errorf("FrameOptions must be DENY or SAMEORIGIN")
+	}
+	switch c.TracingExporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.TracingOTLPEndpoint == "" {
+			return fmt.Errorf("TracingOTLPEndpoint cannot be empty when TracingExporter is otlp")
+		}
+	default:
+		return fmt.Errorf("TracingExporter must be one of none, stdout, otlp")
+	}
 	return nil
 }
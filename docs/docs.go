@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/songs": {
-            "get": {
-                "description": "Get list of songs with optional filtering and pagination",
+        "/admin/artists/merge": {
+            "post": {
+                "description": "Atomically move all songs, including songs in the trash, and aliases of the source artist to the target artist.\nThe source artist is deleted and its name becomes an alias of the target. Requires an authenticated user listed in ADMIN_PRINCIPALS",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,55 +25,107 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "admin"
                 ],
-                "summary": "Get songs with filtering and pagination",
+                "summary": "Merge artists",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Group name",
-                        "name": "group_name",
-                        "in": "query"
+                        "description": "Artists to merge",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MergeArtistsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "Song name",
-                        "name": "song_name",
-                        "in": "query"
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "From date (format: 2006-01-02)",
-                        "name": "from_date",
-                        "in": "query"
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
                     },
-                    {
-                        "type": "string",
-                        "description": "To date (format: 2006-01-02)",
-                        "name": "to_date",
-                        "in": "query"
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/albums": {
+            "get": {
+                "description": "Get list of albums with track counts, optionally filtered by artist and a title substring",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Get albums",
+                "parameters": [
                     {
-                        "type": "string",
-                        "description": "Text content",
-                        "name": "text",
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artist_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Link",
-                        "name": "link",
+                        "description": "Album title substring",
+                        "name": "search",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page number",
+                        "description": "Page number (default 1)",
                         "name": "page",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "Page size",
+                        "description": "Page size from 1 to 100 (default 10)",
                         "name": "page_size",
                         "in": "query"
                     }
@@ -82,13 +134,37 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SongsResponse"
+                            "$ref": "#/definitions/models.AlbumsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
                         }
                     }
                 }
             },
             "post": {
-                "description": "Create a new song with information from external API",
+                "description": "Create a new album. Titles are unique per artist case-insensitively",
                 "consumes": [
                     "application/json"
                 ],
@@ -96,17 +172,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Create new song",
+                "summary": "Create album",
                 "parameters": [
                     {
-                        "description": "Song information",
-                        "name": "song",
+                        "description": "Album information",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SongRequest"
+                            "$ref": "#/definitions/models.AlbumRequest"
                         }
                     }
                 ],
@@ -114,15 +190,93 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
                         }
                     }
                 }
             }
         },
-        "/songs/{id}": {
+        "/albums/{id}": {
+            "get": {
+                "description": "Get album by ID with tracks in order. Songs in the trash are omitted, their track numbers are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Get album",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
             "put": {
-                "description": "Update existing song information",
+                "description": "Fully replace album information: omitted optional fields are cleared. Tracks are not changed",
                 "consumes": [
                     "application/json"
                 ],
@@ -130,24 +284,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Update song",
+                "summary": "Replace album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
+                        "description": "Album ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Song information",
-                        "name": "song",
+                        "description": "Album information",
+                        "name": "album",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SongRequest"
+                            "$ref": "#/definitions/models.AlbumRequest"
                         }
                     }
                 ],
@@ -155,13 +309,49 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Song"
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
                         }
                     }
                 }
             },
             "delete": {
-                "description": "Delete a song by ID",
+                "description": "Delete an album and its track listing. The songs themselves are kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -169,13 +359,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Delete song",
+                "summary": "Delete album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
+                        "description": "Album ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -184,13 +374,31 @@ const docTemplate = `{
                 "responses": {
                     "204": {
                         "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
                     }
                 }
             }
         },
-        "/songs/{id}/lyrics": {
-            "get": {
-                "description": "Get song lyrics with pagination by verses",
+        "/albums/{id}/tracks": {
+            "post": {
+                "description": "Add a song to the album as track track_number, shifting tracks with this and greater numbers.\nIf track_number is omitted, the song becomes the last track",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,46 +406,3093 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Get song lyrics",
+                "summary": "Add album track",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
+                        "description": "Album ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "description": "Track information",
+                        "name": "track",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AlbumTrackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/albums/{id}/tracks/{song_id}": {
+            "put": {
+                "description": "Move a song of the album to track track_number, shifting tracks with this and greater numbers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Move album track",
+                "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "Page size",
-                        "name": "page_size",
-                        "in": "query"
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New track number",
+                        "name": "track",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AlbumTrackPositionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.LyricsResponse"
+                            "$ref": "#/definitions/models.Album"
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "models.LyricsResponse": {
-            "type": "object",
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a song from the album. Numbers of the other tracks are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Remove album track",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists": {
+            "get": {
+                "description": "Get list of artists with aliases and song counts, optionally filtered by a name or alias substring",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get artists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artist name or alias substring",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size from 1 to 100 (default 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtistsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new artist. Names are unique case-insensitively",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Create artist",
+                "parameters": [
+                    {
+                        "description": "Artist information",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}": {
+            "get": {
+                "description": "Get artist by ID with aliases and the number of songs",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Rename an artist. All songs of the artist get the new group name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Rename artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Artist information",
+                        "name": "artist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an artist without songs. Artists with songs, including songs in the trash, result in 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Delete artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/aliases": {
+            "post": {
+                "description": "Add an alternative spelling of the artist name. Songs created or filtered by the alias resolve to the artist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Add artist alias",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alias information",
+                        "name": "alias",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ArtistAliasRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/aliases/{alias_id}": {
+            "delete": {
+                "description": "Delete an alias of the artist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Delete artist alias",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Alias ID",
+                        "name": "alias_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{id}/songs": {
+            "get": {
+                "description": "Get songs of an artist with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get artist songs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size from 1 to 100 (default 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists": {
+            "get": {
+                "description": "Get playlists of the authenticated user ordered by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get own playlists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size from 1 to 100 (default 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a playlist owned by the authenticated user. Unlisted playlists get a share token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Create playlist",
+                "parameters": [
+                    {
+                        "description": "Playlist information",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/import": {
+            "post": {
+                "description": "Create a playlist of the authenticated user from an M3U8, XSPF or JSPF file. The format is taken from\nthe format query parameter or the Content-Type header. Tracks are matched to library songs by artist\n(or artist alias) and title, exactly first and then by trigram similarity; fuzzy matches are flagged.\nTracks without a matching song are reported in unmatched and not added. The file title is used as the\nplaylist name unless name is given",
+                "consumes": [
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "application/jspf+json",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Import playlist",
+                "parameters": [
+                    {
+                        "enum": [
+                            "m3u8",
+                            "xspf",
+                            "jspf"
+                        ],
+                        "type": "string",
+                        "description": "File format, overrides Content-Type",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Playlist name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "private",
+                            "unlisted",
+                            "public"
+                        ],
+                        "type": "string",
+                        "description": "Playlist visibility, private by default",
+                        "name": "visibility",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Playlist file",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}": {
+            "get": {
+                "description": "Get playlist by ID with a page of its entries in order. Other users can see public playlists\nand unlisted playlists with the share token. Entries whose songs are in the trash keep their\npositions and are returned as unavailable without song data",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Get playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of an unlisted playlist",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size from 1 to 100 (default 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Rename a playlist or change its visibility. The share token is kept while the playlist stays\nunlisted and revoked when the visibility changes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Update playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Playlist information",
+                        "name": "playlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a playlist of the authenticated user. The songs themselves are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Delete playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}.{format}": {
+            "get": {
+                "description": "Export a playlist as an M3U8, XSPF or JSPF file. Track locations are song links; songs in the trash\nare skipped, and M3U8 also skips songs without a link. Access rules are the same as for GET /playlists/{id}.\nAn unsupported format is rejected with 400 listing the supported formats",
+                "produces": [
+                    "audio/x-mpegurl",
+                    "application/xspf+xml",
+                    "application/jspf+json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Export playlist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "m3u8",
+                            "xspf",
+                            "jspf"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Share token of an unlisted playlist",
+                        "name": "token",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/songs": {
+            "post": {
+                "description": "Add a song to the playlist at the given position. If position is omitted or exceeds the number\nof entries, the song is appended. The same song may be added several times",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Add playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Entry information",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/playlists/{id}/songs/{entry_id}": {
+            "put": {
+                "description": "Move a playlist entry to the given position. Only the moved entry changes its sort key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Move playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Playlist entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New position",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistPositionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PlaylistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove an entry from the playlist. Positions of the following entries move up by one",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "playlists"
+                ],
+                "summary": "Remove playlist entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Playlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Playlist entry ID",
+                        "name": "entry_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs": {
+            "get": {
+                "description": "Get list of songs with optional filtering and pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get songs with filtering and pagination",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Group name",
+                        "name": "group_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Song name",
+                        "name": "song_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "album",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date (formats: 2006-01-02, 02.01.2006, 2006-01, 2006); partial dates start at the beginning of the period",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (formats: 2006-01-02, 02.01.2006, 2006-01, 2006); partial dates end at the end of the period",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text content",
+                        "name": "text",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Link",
+                        "name": "link",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size from 1 to 100 (default 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "exact",
+                            "estimate",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "Total count mode: exact (default), estimate (planner statistics) or none (only has_next)",
+                        "name": "count",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of a cached response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the page"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Latest modification time of songs on the page"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new song with information from external API. With album_id the song is added to the album\nas track track_number, shifting later tracks, or as the last track if track_number is omitted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Create new song",
+                "parameters": [
+                    {
+                        "description": "Song information",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}": {
+            "get": {
+                "description": "Get a song by ID. The ETag header contains the song version",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of a cached response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Song modification time"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Fully replace existing song information: omitted optional fields are cleared. A stale If-Match version results in 412",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Replace song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected song ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Song information",
+                        "name": "song",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SongRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Move a song to the trash by ID. A stale If-Match version results in 412",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected song ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Partially update a song using JSON Merge Patch (RFC 7396, null clears a field) or JSON Patch (RFC 6902).\nThe patch is applied to a document with the fields group, song, release_date, text and link.",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Patch song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected song ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Merge patch document or array of JSON Patch operations",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SongDocument"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/diff": {
+            "get": {
+                "description": "Get a line-level unified diff of the lyrics and field-level metadata changes between two revisions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Diff song revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Source revision (default: revision preceding 'to')",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Target revision (default: latest revision)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/lyrics": {
+            "get": {
+                "description": "Get song lyrics with pagination by verses",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of a cached response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.LyricsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Song modification time"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/restore": {
+            "post": {
+                "description": "Restore a deleted song from the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revert": {
+            "post": {
+                "description": "Restore a song to a previous revision, saving it as a new revision",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Revert song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to restore",
+                        "name": "rev",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expected song ETag",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song version"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "428": {
+                        "description": "Precondition Required",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/{id}/revisions": {
+            "get": {
+                "description": "Get stored revision history of a song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SongRevision"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash": {
+            "get": {
+                "description": "Get list of deleted songs with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Get trash",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size from 1 to 100 (default 10)",
+                        "name": "page_size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date of a cached response",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SongsResponse"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Entity tag of the page"
+                            },
+                            "Last-Modified": {
+                                "type": "string",
+                                "description": "Latest modification time of songs on the page"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/trash/{id}": {
+            "delete": {
+                "description": "Permanently delete a song from the trash",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Purge song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.Problem"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "errors.ErrorType": {
+            "type": "string",
+            "enum": [
+                "NOT_FOUND",
+                "UNAUTHORIZED",
+                "FORBIDDEN",
+                "BAD_REQUEST",
+                "INTERNAL",
+                "VALIDATION",
+                "ALREADY_EXISTS",
+                "CONFLICT",
+                "PRECONDITION_FAILED",
+                "PRECONDITION_REQUIRED",
+                "UNSUPPORTED_MEDIA_TYPE",
+                "PAYLOAD_TOO_LARGE",
+                "TIMEOUT",
+                "CANCELED",
+                "UNAVAILABLE"
+            ],
+            "x-enum-varnames": [
+                "NotFound",
+                "Unauthorized",
+                "Forbidden",
+                "BadRequest",
+                "Internal",
+                "Validation",
+                "AlreadyExists",
+                "Conflict",
+                "PreconditionFailed",
+                "PreconditionRequired",
+                "UnsupportedMediaType",
+                "PayloadTooLarge",
+                "Timeout",
+                "Canceled",
+                "Unavailable"
+            ]
+        },
+        "errors.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Название поля",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "Описание нарушения",
+                    "type": "string"
+                }
+            }
+        },
+        "errors.Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Стабильный машиночитаемый код ошибки",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/errors.ErrorType"
+                        }
+                    ],
+                    "example": "NOT_FOUND"
+                },
+                "detail": {
+                    "description": "Описание конкретного случая",
+                    "type": "string",
+                    "example": "song not found"
+                },
+                "errors": {
+                    "description": "Ошибки отдельных полей",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/errors.FieldError"
+                    }
+                },
+                "instance": {
+                    "description": "URI запроса, вызвавшего ошибку",
+                    "type": "string",
+                    "example": "/api/v1/songs/42"
+                },
+                "status": {
+                    "description": "HTTP-код ответа",
+                    "type": "integer",
+                    "example": 404
+                },
+                "title": {
+                    "description": "Краткое описание типа проблемы",
+                    "type": "string",
+                    "example": "Not Found"
+                },
+                "type": {
+                    "description": "URI типа проблемы",
+                    "type": "string",
+                    "example": "urn:songs-library:problem:not-found"
+                }
+            }
+        },
+        "models.Album": {
+            "type": "object",
+            "properties": {
+                "artist_id": {
+                    "description": "Идентификатор исполнителя",
+                    "type": "integer"
+                },
+                "cover_url": {
+                    "description": "Ссылка на обложку альбома",
+                    "type": "string"
+                },
+                "created_at": {
+                    "description": "Дата и время создания записи",
+                    "type": "string"
+                },
+                "group_name": {
+                    "description": "Каноническое имя исполнителя",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Уникальный идентификатор альбома",
+                    "type": "integer"
+                },
+                "release_date": {
+                    "description": "Дата выпуска альбома с учетом точности",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Название альбома",
+                    "type": "string"
+                },
+                "track_count": {
+                    "description": "Количество треков, не находящихся в корзине",
+                    "type": "integer"
+                },
+                "tracks": {
+                    "description": "Треки по порядку, только при получении альбома по идентификатору",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AlbumTrack"
+                    }
+                },
+                "updated_at": {
+                    "description": "Дата и время последнего обновления записи",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlbumRequest": {
+            "type": "object",
+            "required": [
+                "artist_id",
+                "title"
+            ],
+            "properties": {
+                "artist_id": {
+                    "description": "Идентификатор исполнителя, обязательное поле",
+                    "type": "integer"
+                },
+                "cover_url": {
+                    "description": "Ссылка на обложку, необязательное поле",
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "Дата выпуска: YYYY-MM-DD, DD.MM.YYYY, YYYY-MM или YYYY, необязательное поле",
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
+                "title": {
+                    "description": "Название альбома, обязательное поле",
+                    "type": "string"
+                }
+            }
+        },
+        "models.AlbumTrack": {
+            "type": "object",
+            "properties": {
+                "song": {
+                    "description": "Песня",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    ]
+                },
+                "track_number": {
+                    "description": "Номер трека в альбоме",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AlbumTrackPositionRequest": {
+            "type": "object",
+            "required": [
+                "track_number"
+            ],
+            "properties": {
+                "track_number": {
+                    "description": "Новый номер трека, обязательное поле",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AlbumTrackRequest": {
+            "type": "object",
+            "required": [
+                "song_id"
+            ],
+            "properties": {
+                "song_id": {
+                    "description": "Идентификатор песни, обязательное поле",
+                    "type": "integer"
+                },
+                "track_number": {
+                    "description": "Номер трека; если не указан, песня добавляется в конец альбома",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AlbumsResponse": {
+            "type": "object",
+            "properties": {
+                "albums": {
+                    "description": "Список альбомов без треков",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Album"
+                    }
+                },
+                "page": {
+                    "description": "Номер текущей страницы",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "Количество элементов на странице",
+                    "type": "integer"
+                },
+                "total_items": {
+                    "description": "Общее количество альбомов",
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "description": "Общее количество страниц",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Artist": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Другие написания имени исполнителя",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ArtistAlias"
+                    }
+                },
+                "created_at": {
+                    "description": "Дата и время создания записи",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Уникальный идентификатор исполнителя",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Каноническое имя исполнителя",
+                    "type": "string"
+                },
+                "song_count": {
+                    "description": "Количество песен исполнителя, не находящихся в корзине",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Дата и время последнего обновления записи",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArtistAlias": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Дата и время создания записи",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Уникальный идентификатор псевдонима",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Псевдоним, уникальный без учета регистра",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArtistAliasRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Псевдоним исполнителя, обязательное поле",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArtistRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Имя исполнителя, обязательное поле",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArtistsResponse": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "description": "Список исполнителей",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Artist"
+                    }
+                },
+                "page": {
+                    "description": "Номер текущей страницы",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "Количество элементов на странице",
+                    "type": "integer"
+                },
+                "total_items": {
+                    "description": "Общее количество исполнителей",
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "description": "Общее количество страниц",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "Название поля",
+                    "type": "string"
+                },
+                "from": {
+                    "description": "Значение в исходной ревизии",
+                    "type": "string"
+                },
+                "to": {
+                    "description": "Значение в целевой ревизии",
+                    "type": "string"
+                }
+            }
+        },
+        "models.LyricsResponse": {
+            "type": "object",
+            "properties": {
+                "current_page": {
+                    "description": "Номер текущей страницы",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "Количество элементов на странице",
+                    "type": "integer"
+                },
+                "text": {
+                    "description": "Текст песни или куплетов",
+                    "type": "string"
+                },
+                "total_pages": {
+                    "description": "Общее количество страниц",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.MergeArtistsRequest": {
+            "type": "object",
+            "required": [
+                "source_id",
+                "target_id"
+            ],
+            "properties": {
+                "source_id": {
+                    "description": "Исполнитель, который удаляется после переноса песен",
+                    "type": "integer"
+                },
+                "target_id": {
+                    "description": "Исполнитель, которому переходят песни и псевдонимы",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Playlist": {
+            "type": "object",
             "properties": {
-                "current_page": {
+                "created_at": {
+                    "description": "Дата и время создания записи",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Уникальный идентификатор плейлиста",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Название плейлиста",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Пользователь, создавший плейлист",
+                    "type": "string"
+                },
+                "share_token": {
+                    "description": "Токен ссылки на плейлист, доступный по ссылке; виден только владельцу",
+                    "type": "string"
+                },
+                "song_count": {
+                    "description": "Количество элементов плейлиста, включая песни в корзине",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Дата и время последнего обновления записи",
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Видимость плейлиста",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PlaylistVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PlaylistEntry": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "description": "Дата и время добавления песни в плейлист",
+                    "type": "string"
+                },
+                "available": {
+                    "description": "Песня не находится в корзине",
+                    "type": "boolean"
+                },
+                "id": {
+                    "description": "Идентификатор элемента плейлиста",
+                    "type": "integer"
+                },
+                "position": {
+                    "description": "Порядковый номер элемента в плейлисте, начиная с 1",
+                    "type": "integer"
+                },
+                "song": {
+                    "description": "Песня, если она доступна",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Song"
+                        }
+                    ]
+                },
+                "song_id": {
+                    "description": "Идентификатор песни",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlaylistEntryRequest": {
+            "type": "object",
+            "required": [
+                "song_id"
+            ],
+            "properties": {
+                "position": {
+                    "description": "Порядковый номер, на который вставляется песня; если не указан, песня добавляется в конец",
+                    "type": "integer"
+                },
+                "song_id": {
+                    "description": "Идентификатор песни, обязательное поле",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlaylistImportMatch": {
+            "type": "object",
+            "properties": {
+                "creator": {
+                    "description": "Исполнитель из файла",
+                    "type": "string"
+                },
+                "entry_id": {
+                    "description": "Идентификатор элемента плейлиста",
+                    "type": "integer"
+                },
+                "fuzzy": {
+                    "description": "Песня найдена по нечеткому совпадению, результат стоит проверить",
+                    "type": "boolean"
+                },
+                "index": {
+                    "description": "Порядковый номер трека в файле, начиная с 1",
+                    "type": "integer"
+                },
+                "song_id": {
+                    "description": "Идентификатор найденной песни",
+                    "type": "integer"
+                },
+                "title": {
+                    "description": "Название из файла",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaylistImportMiss": {
+            "type": "object",
+            "properties": {
+                "creator": {
+                    "description": "Исполнитель из файла",
+                    "type": "string"
+                },
+                "index": {
+                    "description": "Порядковый номер трека в файле, начиная с 1",
+                    "type": "integer"
+                },
+                "location": {
+                    "description": "Ссылка из файла",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "Причина: missing_metadata - в файле нет исполнителя или названия, not_found - песня не найдена",
+                    "type": "string"
+                },
+                "title": {
+                    "description": "Название из файла",
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlaylistImportResponse": {
+            "type": "object",
+            "properties": {
+                "matched": {
+                    "description": "Треки файла, добавленные в плейлист",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistImportMatch"
+                    }
+                },
+                "playlist": {
+                    "description": "Созданный плейлист",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Playlist"
+                        }
+                    ]
+                },
+                "unmatched": {
+                    "description": "Треки файла, для которых не нашлось песни",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistImportMiss"
+                    }
+                }
+            }
+        },
+        "models.PlaylistPositionRequest": {
+            "type": "object",
+            "required": [
+                "position"
+            ],
+            "properties": {
+                "position": {
+                    "description": "Новый порядковый номер элемента, начиная с 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlaylistRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "description": "Название плейлиста, обязательное поле",
+                    "type": "string"
+                },
+                "visibility": {
+                    "description": "Видимость, по умолчанию private",
+                    "enum": [
+                        "private",
+                        "unlisted",
+                        "public"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PlaylistVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PlaylistResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Дата и время создания записи",
+                    "type": "string"
+                },
+                "entries": {
+                    "description": "Элементы плейлиста по порядку",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlaylistEntry"
+                    }
+                },
+                "id": {
+                    "description": "Уникальный идентификатор плейлиста",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Название плейлиста",
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "Пользователь, создавший плейлист",
+                    "type": "string"
+                },
+                "page": {
                     "description": "Номер текущей страницы",
                     "type": "integer"
                 },
@@ -245,10 +3500,76 @@ const docTemplate = `{
                     "description": "Количество элементов на странице",
                     "type": "integer"
                 },
-                "text": {
-                    "description": "Текст песни или куплетов",
+                "share_token": {
+                    "description": "Токен ссылки на плейлист, доступный по ссылке; виден только владельцу",
+                    "type": "string"
+                },
+                "song_count": {
+                    "description": "Количество элементов плейлиста, включая песни в корзине",
+                    "type": "integer"
+                },
+                "total_items": {
+                    "description": "Общее количество элементов",
+                    "type": "integer"
+                },
+                "total_pages": {
+                    "description": "Общее количество страниц",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "description": "Дата и время последнего обновления записи",
                     "type": "string"
                 },
+                "visibility": {
+                    "description": "Видимость плейлиста",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.PlaylistVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "models.PlaylistVisibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "unlisted",
+                "public"
+            ],
+            "x-enum-comments": {
+                "VisibilityPrivate": "Только владелец",
+                "VisibilityPublic": "Все пользователи",
+                "VisibilityUnlisted": "Владелец и все, у кого есть ссылка с токеном"
+            },
+            "x-enum-varnames": [
+                "VisibilityPrivate",
+                "VisibilityUnlisted",
+                "VisibilityPublic"
+            ]
+        },
+        "models.PlaylistsResponse": {
+            "type": "object",
+            "properties": {
+                "page": {
+                    "description": "Номер текущей страницы",
+                    "type": "integer"
+                },
+                "page_size": {
+                    "description": "Количество элементов на странице",
+                    "type": "integer"
+                },
+                "playlists": {
+                    "description": "Список плейлистов",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Playlist"
+                    }
+                },
+                "total_items": {
+                    "description": "Общее количество плейлистов",
+                    "type": "integer"
+                },
                 "total_pages": {
                     "description": "Общее количество страниц",
                     "type": "integer"
@@ -258,12 +3579,20 @@ const docTemplate = `{
         "models.Song": {
             "type": "object",
             "properties": {
+                "artist_id": {
+                    "description": "Идентификатор исполнителя",
+                    "type": "integer"
+                },
                 "created_at": {
                     "description": "Дата и время создания записи",
                     "type": "string"
                 },
+                "deleted_at": {
+                    "description": "Дата и время перемещения в корзину",
+                    "type": "string"
+                },
                 "group_name": {
-                    "description": "Название группы или исполнителя",
+                    "description": "Каноническое имя исполнителя",
                     "type": "string"
                 },
                 "id": {
@@ -275,7 +3604,7 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "release_date": {
-                    "description": "Дата выпуска песни",
+                    "description": "Дата выпуска песни с учетом точности",
                     "type": "string"
                 },
                 "song_name": {
@@ -289,6 +3618,63 @@ const docTemplate = `{
                 "updated_at": {
                     "description": "Дата и время последнего обновления записи",
                     "type": "string"
+                },
+                "version": {
+                    "description": "Версия записи для оптимистичной блокировки",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongDiff": {
+            "type": "object",
+            "properties": {
+                "fields": {
+                    "description": "Изменения полей метаданных",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FieldChange"
+                    }
+                },
+                "from": {
+                    "description": "Номер исходной ревизии",
+                    "type": "integer"
+                },
+                "lyrics_diff": {
+                    "description": "Построчный unified diff текста песни",
+                    "type": "string"
+                },
+                "song_id": {
+                    "description": "Идентификатор песни",
+                    "type": "integer"
+                },
+                "to": {
+                    "description": "Номер целевой ревизии",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongDocument": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "Название группы",
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Ссылка на песню",
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "Дата выпуска в любом поддерживаемом формате, null - дата не указана",
+                    "type": "string"
+                },
+                "song": {
+                    "description": "Название песни",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Текст песни",
+                    "type": "string"
                 }
             }
         },
@@ -299,6 +3685,10 @@ const docTemplate = `{
                 "song"
             ],
             "properties": {
+                "album_id": {
+                    "description": "Альбом, в который добавляется песня, только при создании",
+                    "type": "integer"
+                },
                 "group": {
                     "description": "Название группы, обязательное поле",
                     "type": "string"
@@ -307,6 +3697,11 @@ const docTemplate = `{
                     "description": "Ссылка на песню, необязательное поле",
                     "type": "string"
                 },
+                "release_date": {
+                    "description": "Дата выпуска: YYYY-MM-DD, DD.MM.YYYY, YYYY-MM или YYYY, необязательное поле",
+                    "type": "string",
+                    "example": "16.07.2006"
+                },
                 "song": {
                     "description": "Название песни, обязательное поле",
                     "type": "string"
@@ -314,12 +3709,57 @@ const docTemplate = `{
                 "text": {
                     "description": "Текст песни, необязательное поле",
                     "type": "string"
+                },
+                "track_number": {
+                    "description": "Номер трека в альбоме; если не указан, песня добавляется в конец",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SongRevision": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "description": "Дата и время создания ревизии",
+                    "type": "string"
+                },
+                "group_name": {
+                    "description": "Название группы или исполнителя",
+                    "type": "string"
+                },
+                "link": {
+                    "description": "Ссылка на песню",
+                    "type": "string"
+                },
+                "release_date": {
+                    "description": "Дата выпуска песни с учетом точности",
+                    "type": "string"
+                },
+                "revision": {
+                    "description": "Порядковый номер ревизии",
+                    "type": "integer"
+                },
+                "song_id": {
+                    "description": "Идентификатор песни",
+                    "type": "integer"
+                },
+                "song_name": {
+                    "description": "Название песни",
+                    "type": "string"
+                },
+                "text": {
+                    "description": "Текст песни",
+                    "type": "string"
                 }
             }
         },
         "models.SongsResponse": {
             "type": "object",
             "properties": {
+                "has_next": {
+                    "description": "Существует следующая страница",
+                    "type": "boolean"
+                },
                 "page": {
                     "description": "Номер текущей страницы",
                     "type": "integer"
@@ -339,6 +3779,10 @@ const docTemplate = `{
                     "description": "Общее количество песен",
                     "type": "integer"
                 },
+                "total_items_exact": {
+                    "description": "Общее количество песен подсчитано точно, а не оценено",
+                    "type": "boolean"
+                },
                 "total_pages": {
                     "description": "Общее количество страниц",
                     "type": "integer"
@@ -3,26 +3,121 @@ package router
 import (
 	"net/http"
 
+	"github.com/ZnNr/songs-library/config"
 	"github.com/ZnNr/songs-library/internal/handlers"
+	"github.com/ZnNr/songs-library/internal/health"
+	"github.com/ZnNr/songs-library/internal/metrics"
 	"github.com/ZnNr/songs-library/internal/middleware"
+	"github.com/ZnNr/songs-library/internal/tracing"
 	"github.com/gorilla/mux"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 	"go.uber.org/zap"
 )
 
 // NewRouter создает новый маршрутизатор и регистрирует маршруты.
-func NewRouter(handler *handlers.SongHandler, logger *zap.Logger) *mux.Router {
+func NewRouter(cfg *config.Config, handler *handlers.SongHandler, artistHandler *handlers.ArtistHandler, albumHandler *handlers.AlbumHandler, playlistHandler *handlers.PlaylistHandler, m *metrics.Metrics, checker *health.Checker, logger *zap.Logger) *mux.Router {
 	r := mux.NewRouter()
 
-	// Добавляем миддлвары для логирования
+	// Добавляем миддлвары для трассировки, идентификации запросов и логирования.
+	// Спан запроса создается первым, чтобы trace_id попадал в логгер запроса.
+	r.Use(otelmux.Middleware(tracing.ServiceName))
+	r.Use(middleware.RequestIDMiddleware(logger))
 	r.Use(middleware.LoggingMiddleware(logger))
+	r.Use(middleware.MetricsMiddleware(m))
+
+	// Заголовки безопасности и CORS. Swagger UI исключен из строгой политики CSP,
+	// так как загружает скрипты и стили
+	r.Use(middleware.SecurityHeadersMiddleware(middleware.SecurityHeadersOptions{
+		HSTSMaxAge:   cfg.HSTSMaxAge,
+		FrameOptions: cfg.FrameOptions,
+		UIPathPrefix: "/swagger/",
+	}))
+	r.Use(middleware.CORSMiddleware(middleware.CORSOptions{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowedHeaders:   cfg.CORSAllowedHeaders,
+		ExposedHeaders:   cfg.CORSExposedHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	}))
+
+	// Сжатие ответов и заголовок Cache-Control
+	if cfg.CompressionEnabled {
+		r.Use(middleware.CompressionMiddleware(cfg.CompressionMinSize))
+	}
+	r.Use(middleware.CacheControlMiddleware(cfg.CacheControl, cfg.CacheControlRoutes))
+
+	// Защитные миддлвары: перехват паники, дедлайн запроса и ограничение размера тела
+	r.Use(middleware.RecoveryMiddleware(logger))
+	r.Use(middleware.TimeoutMiddleware(cfg.RequestTimeout, cfg.RouteTimeouts))
+	r.Use(middleware.BodyLimitMiddleware(cfg.MaxBodyBytes))
+
+	// Аутентификация: пользователь от доверенного прокси или с проверенным паролем Basic-аутентификации
+	r.Use(middleware.AuthMiddleware(middleware.AuthOptions{
+		TrustedProxies: cfg.TrustedProxies,
+		BasicAuthUsers: cfg.BasicAuthUsers,
+	}, logger))
+
+	// Изменяющие запросы и запросы с X-Read-Your-Writes читают с основной базы данных
+	r.Use(middleware.ReadYourWritesMiddleware)
 
 	api := r.PathPrefix("/api/v1").Subrouter()
 	api.HandleFunc("/songs", handler.GetSongs).Methods(http.MethodGet)
+	api.HandleFunc("/songs/{id}", handler.GetSong).Methods(http.MethodGet)
 	api.HandleFunc("/songs/{id}/lyrics", handler.GetLyrics).Methods(http.MethodGet)
 	api.HandleFunc("/songs", handler.CreateSong).Methods(http.MethodPost)
 	api.HandleFunc("/songs/{id}", handler.UpdateSong).Methods(http.MethodPut)
+	api.HandleFunc("/songs/{id}", handler.PatchSong).Methods(http.MethodPatch)
 	api.HandleFunc("/songs/{id}", handler.DeleteSong).Methods(http.MethodDelete)
+	api.HandleFunc("/songs/{id}/revisions", handler.GetRevisions).Methods(http.MethodGet)
+	api.HandleFunc("/songs/{id}/diff", handler.DiffRevisions).Methods(http.MethodGet)
+	api.HandleFunc("/songs/{id}/revert", handler.RevertSong).Methods(http.MethodPost)
+	api.HandleFunc("/songs/{id}/restore", handler.RestoreSong).Methods(http.MethodPost)
+	api.HandleFunc("/trash", handler.GetTrash).Methods(http.MethodGet)
+	api.HandleFunc("/trash/{id}", handler.PurgeSong).Methods(http.MethodDelete)
+	api.HandleFunc("/artists", artistHandler.GetArtists).Methods(http.MethodGet)
+	api.HandleFunc("/artists/{id}", artistHandler.GetArtist).Methods(http.MethodGet)
+	api.HandleFunc("/artists/{id}/songs", artistHandler.GetArtistSongs).Methods(http.MethodGet)
+	api.HandleFunc("/artists", artistHandler.CreateArtist).Methods(http.MethodPost)
+	api.HandleFunc("/artists/{id}", artistHandler.UpdateArtist).Methods(http.MethodPut)
+	api.HandleFunc("/artists/{id}", artistHandler.DeleteArtist).Methods(http.MethodDelete)
+	api.HandleFunc("/artists/{id}/aliases", artistHandler.AddArtistAlias).Methods(http.MethodPost)
+	api.HandleFunc("/artists/{id}/aliases/{alias_id}", artistHandler.DeleteArtistAlias).Methods(http.MethodDelete)
+	api.HandleFunc("/albums", albumHandler.GetAlbums).Methods(http.MethodGet)
+	api.HandleFunc("/albums/{id}", albumHandler.GetAlbum).Methods(http.MethodGet)
+	api.HandleFunc("/albums", albumHandler.CreateAlbum).Methods(http.MethodPost)
+	api.HandleFunc("/albums/{id}", albumHandler.UpdateAlbum).Methods(http.MethodPut)
+	api.HandleFunc("/albums/{id}", albumHandler.DeleteAlbum).Methods(http.MethodDelete)
+	api.HandleFunc("/albums/{id}/tracks", albumHandler.AddAlbumTrack).Methods(http.MethodPost)
+	api.HandleFunc("/albums/{id}/tracks/{song_id}", albumHandler.MoveAlbumTrack).Methods(http.MethodPut)
+	api.HandleFunc("/albums/{id}/tracks/{song_id}", albumHandler.RemoveAlbumTrack).Methods(http.MethodDelete)
+	api.HandleFunc("/playlists", playlistHandler.GetPlaylists).Methods(http.MethodGet)
+	// Экспорт регистрируется раньше GET /playlists/{id}, который иначе принял бы "42.m3u8" за идентификатор
+	api.HandleFunc("/playlists/{id:[0-9]+}.{format:m3u8|xspf|jspf}", playlistHandler.ExportPlaylist).Methods(http.MethodGet)
+	api.HandleFunc("/playlists/import", playlistHandler.ImportPlaylist).Methods(http.MethodPost)
+	api.HandleFunc("/playlists/{id}", playlistHandler.GetPlaylist).Methods(http.MethodGet)
+	api.HandleFunc("/playlists", playlistHandler.CreatePlaylist).Methods(http.MethodPost)
+	api.HandleFunc("/playlists/{id}", playlistHandler.UpdatePlaylist).Methods(http.MethodPut)
+	api.HandleFunc("/playlists/{id}", playlistHandler.DeletePlaylist).Methods(http.MethodDelete)
+	api.HandleFunc("/playlists/{id}/songs", playlistHandler.AddPlaylistEntry).Methods(http.MethodPost)
+	api.HandleFunc("/playlists/{id}/songs/{entry_id}", playlistHandler.MovePlaylistEntry).Methods(http.MethodPut)
+	api.HandleFunc("/playlists/{id}/songs/{entry_id}", playlistHandler.RemovePlaylistEntry).Methods(http.MethodDelete)
+
+	// Административные маршруты доступны только пользователям из ADMIN_PRINCIPALS
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.AdminMiddleware(cfg.AdminPrincipals, logger))
+	admin.HandleFunc("/artists/merge", artistHandler.MergeArtists).Methods(http.MethodPost)
+	// Маршрут для запросов OPTIONS, чтобы миддлвары, включая CORS, выполнялись для предварительных запросов
+	api.PathPrefix("/").Methods(http.MethodOptions).Handler(middleware.PreflightHandler(cfg.CORSAllowedMethods))
+
+	// Проверки состояния для оркестратора
+	r.HandleFunc("/healthz", checker.Live).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", checker.Ready).Methods(http.MethodGet)
+	r.HandleFunc("/startupz", checker.Startup).Methods(http.MethodGet)
+
+	// Метрики Prometheus
+	r.Handle("/metrics", m.Handler()).Methods(http.MethodGet)
 
 	// Swagger
 	r.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
@@ -18,8 +18,14 @@ import (
 	"github.com/ZnNr/songs-library/config"
 	"github.com/ZnNr/songs-library/internal/controllers/router"
 	"github.com/ZnNr/songs-library/internal/handlers"
+	"github.com/ZnNr/songs-library/internal/health"
+	"github.com/ZnNr/songs-library/internal/metrics"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"github.com/ZnNr/songs-library/internal/repository/cache"
 	"github.com/ZnNr/songs-library/internal/repository/database"
+	"github.com/ZnNr/songs-library/internal/repository/instrumented"
 	"github.com/ZnNr/songs-library/internal/service"
+	"github.com/ZnNr/songs-library/internal/tracing"
 )
 
 const schema = "migrations/000001_init_schema.up.sql"
@@ -29,7 +35,17 @@ type App struct {
 	config     *config.Config
 	logger     *zap.Logger
 	db         *sql.DB
+	replicaDBs []*sql.DB
+	replicas   *database.ReplicaSet
 	httpServer *http.Server
+	purger     *service.TrashPurger
+	metrics    *metrics.Metrics
+	health     *health.Checker
+
+	// schemaVersion - версия схемы после применения миграций, ожидаемая проверкой готовности
+	schemaVersion uint
+
+	shutdownTracing func(context.Context) error
 }
 
 // New конструктор нового экземпляра приложения
@@ -42,6 +58,10 @@ func New(cfg *config.Config, logger *zap.Logger) *App {
 
 // Initialize инициализирует компоненты приложения
 func (a *App) Initialize() error {
+	if err := a.initTracing(); err != nil {
+		return fmt.Errorf("failed to initialize tracing: %w", err)
+	}
+
 	if err := a.initDatabase(); err != nil {
 		return fmt.Errorf("failed to initialize database: %w", err)
 	}
@@ -57,6 +77,20 @@ func (a *App) Initialize() error {
 	return nil
 }
 
+// initTracing настраивает экспорт трассировок OpenTelemetry
+func (a *App) initTracing() error {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     a.config.TracingExporter,
+		OTLPEndpoint: a.config.TracingOTLPEndpoint,
+		OTLPInsecure: a.config.TracingOTLPInsecure,
+	})
+	if err != nil {
+		return err
+	}
+	a.shutdownTracing = shutdown
+	return nil
+}
+
 // initDatabase инициализирует подключение к базе данных
 func (a *App) initDatabase() error {
 	connStr := a.config.GetDBConnString()
@@ -65,6 +99,19 @@ func (a *App) initDatabase() error {
 		return fmt.Errorf("failed to open database connection: %w", err)
 	}
 	a.db = db
+
+	// Реплики для чтения открываются лениво: недоступная при запуске реплика
+	// будет исключена из распределения проверкой доступности
+	for i, dsn := range a.config.DBReplicaDSNs {
+		replicaDB, err := sql.Open("postgres", dsn)
+		if err != nil {
+			return fmt.Errorf("failed to open replica %d connection: %w", i+1, err)
+		}
+		a.replicaDBs = append(a.replicaDBs, replicaDB)
+	}
+	if len(a.replicaDBs) > 0 {
+		a.replicas = database.NewReplicaSet(a.replicaDBs, a.logger, a.config.ReplicaHealthCheckInterval)
+	}
 	return nil
 }
 
@@ -89,19 +136,73 @@ func (a *App) runMigrations() error {
 		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
-	a.logger.Info("Migrations applied successfully")
+	version, _, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	a.schemaVersion = version
+
+	a.logger.Info("Migrations applied successfully", zap.Uint("version", version))
 	return nil
 }
 
 // initHTTPServer инициализирует HTTP сервер
 func (a *App) initHTTPServer() error {
+	// Метрики пула соединений, размера таблицы и длительности запросов к репозиторию
+	a.metrics = metrics.New()
+	a.metrics.RegisterDB(a.db, a.config.DBName)
+	for i, replicaDB := range a.replicaDBs {
+		a.metrics.RegisterDB(replicaDB, fmt.Sprintf("%s-replica-%d", a.config.DBName, i+1))
+	}
+	a.metrics.RegisterTableSize(a.db, a.logger)
+
 	// Инициализируем репозиторий, сервис и обработчики
-	repo := database.NewPostgresSongRepository(a.db)
+	db := database.NewPostgresSongRepository(a.db, a.replicas, a.config.DBTxIsolation, a.logger)
+	var repo repository.SongRepository = instrumented.NewSongRepository(db, a.metrics)
+	var artists repository.ArtistRepository = instrumented.NewArtistRepository(db, a.metrics)
+	var albums repository.AlbumRepository = instrumented.NewAlbumRepository(db, a.metrics)
+	playlists := instrumented.NewPlaylistRepository(db.Playlists(), a.metrics)
+	if a.config.RepositoryCacheEnabled {
+		cached := cache.NewSongRepository(repo, cache.Config{
+			TTL:          a.config.RepositoryCacheTTL,
+			MaxSongs:     a.config.RepositoryCacheMaxSongs,
+			MaxLists:     a.config.RepositoryCacheMaxLists,
+			SingleFlight: a.config.RepositoryCacheSingleFlight,
+			LoadTimeout:  a.config.RepositoryCacheLoadTimeout,
+		})
+		a.metrics.RegisterCache(func() []metrics.CacheStats {
+			var stats []metrics.CacheStats
+			for _, s := range cached.Stats() {
+				stats = append(stats, metrics.CacheStats(s))
+			}
+			return stats
+		})
+		repo = cached
+		artists = cache.NewArtistRepository(artists, cached)
+		albums = cache.NewAlbumRepository(albums, cached)
+	}
 	svc := service.NewSongService(repo, a.logger)
-	songHandler := handlers.NewSongHandler(svc, a.logger) // Исправлено на songHandler
+	songHandler := handlers.NewSongHandler(svc, a.logger, a.config.RequireIfMatch) // Исправлено на songHandler
+	artistHandler := handlers.NewArtistHandler(service.NewArtistService(artists, repo, a.logger), a.logger)
+	albumHandler := handlers.NewAlbumHandler(service.NewAlbumService(albums, a.logger), a.logger)
+	playlistHandler := handlers.NewPlaylistHandler(service.NewPlaylistService(playlists, a.logger), a.logger)
+
+	// Фоновая очистка корзины
+	a.purger = service.NewTrashPurger(repo, a.logger, a.config.TrashRetention, a.config.TrashPurgeInterval)
+
+	// Проверки жизнеспособности и готовности
+	a.health = health.New(a.logger, a.config.HealthCheckTimeout)
+	a.health.AddCheck("database", health.DatabaseCheck(a.db))
+	a.health.AddCheck("migrations", health.MigrationsCheck(a.db, a.schemaVersion))
+	if a.config.EnrichmentAPIURL != "" {
+		a.health.AddOptionalCheck("enrichment_api", health.CachedHTTPCheck(
+			&http.Client{Timeout: a.config.HealthCheckTimeout},
+			a.config.EnrichmentAPIURL,
+			a.config.EnrichmentHealthCacheTTL))
+	}
 
 	// Создаем роутер
-	r := router.NewRouter(songHandler, a.logger) // Изменён импорт вызова NewRouter
+	r := router.NewRouter(a.config, songHandler, artistHandler, albumHandler, playlistHandler, a.metrics, a.health, a.logger) // Изменён импорт вызова NewRouter
 
 	// Создаем HTTP сервер
 	a.httpServer = &http.Server{
@@ -116,6 +217,12 @@ func (a *App) initHTTPServer() error {
 
 // Run запуск приложения
 func (a *App) Run() error {
+	a.purger.Start()
+	if a.replicas != nil {
+		a.replicas.Start()
+	}
+	a.health.SetStarted()
+
 	a.logger.Info("Starting server", zap.String("port", a.config.ServerPort))
 	if err := a.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("failed to start server: %w", err)
@@ -127,13 +234,40 @@ func (a *App) Run() error {
 func (a *App) Shutdown(ctx context.Context) error {
 	a.logger.Info("Shutting down server...")
 
+	// Сначала снимаем готовность, чтобы балансировщик перестал направлять новые запросы,
+	// и только затем останавливаем HTTP сервер
+	a.health.SetShuttingDown()
+	select {
+	case <-time.After(a.config.ShutdownDrainDelay):
+	case <-ctx.Done():
+	}
+
 	if err := a.httpServer.Shutdown(ctx); err != nil {
 		return fmt.Errorf("failed to shutdown server: %w", err)
 	}
 
+	if err := a.purger.Stop(ctx); err != nil {
+		return fmt.Errorf("failed to stop trash purger: %w", err)
+	}
+
+	if a.replicas != nil {
+		if err := a.replicas.Stop(ctx); err != nil {
+			return fmt.Errorf("failed to stop replica health checks: %w", err)
+		}
+	}
+
 	if err := a.db.Close(); err != nil {
 		return fmt.Errorf("failed to close database connection: %w", err)
 	}
+	for _, replicaDB := range a.replicaDBs {
+		if err := replicaDB.Close(); err != nil {
+			return fmt.Errorf("failed to close replica connection: %w", err)
+		}
+	}
+
+	if err := a.shutdownTracing(ctx); err != nil {
+		return fmt.Errorf("failed to flush traces: %w", err)
+	}
 
 	return nil
 }
@@ -0,0 +1,167 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext количество строк контекста вокруг изменений по умолчанию.
+const DefaultContext = 3
+
+// Типы операций построчного сравнения.
+const (
+	opEqual  = ' '
+	opDelete = '-'
+	opInsert = '+'
+)
+
+// operation представляет одну строку результата сравнения.
+type operation struct {
+	kind byte
+	line string
+}
+
+// Unified формирует построчный unified diff между текстами a и b.
+// Возвращает пустую строку, если тексты совпадают.
+func Unified(a, b, fromLabel, toLabel string, context int) string {
+	ops := compare(splitLines(a), splitLines(b))
+	hunks := buildHunks(ops, context)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromLabel, toLabel)
+	for _, h := range hunks {
+		h.writeTo(&sb, ops)
+	}
+	return sb.String()
+}
+
+// splitLines разбивает текст на строки, пустой текст не содержит строк.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// compare строит последовательность операций на основе наибольшей общей подпоследовательности строк.
+func compare(a, b []string) []operation {
+	// lcs[i][j] - длина наибольшей общей подпоследовательности для a[i:] и b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]operation, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, operation{kind: opEqual, line: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, operation{kind: opDelete, line: a[i]})
+			i++
+		default:
+			ops = append(ops, operation{kind: opInsert, line: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, operation{kind: opDelete, line: a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, operation{kind: opInsert, line: b[j]})
+	}
+	return ops
+}
+
+// hunk описывает непрерывный участок изменений вместе с контекстом.
+type hunk struct {
+	start, end int // Границы участка в списке операций [start, end)
+	fromLine   int // Номер первой строки участка в исходном тексте (с нуля)
+	toLine     int // Номер первой строки участка в целевом тексте (с нуля)
+	fromN, toN int // Количество строк участка в исходном и целевом тексте
+}
+
+// buildHunks группирует изменения в участки, объединяя пересекающиеся контексты.
+func buildHunks(ops []operation, context int) []hunk {
+	if context < 0 {
+		context = 0
+	}
+
+	var hunks []hunk
+	for i, op := range ops {
+		if op.kind == opEqual {
+			continue
+		}
+		start := max(0, i-context)
+		end := min(len(ops), i+context+1)
+		if n := len(hunks); n > 0 && start <= hunks[n-1].end {
+			hunks[n-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunk{start: start, end: end})
+	}
+
+	// Вычисляем номера строк и размеры участков
+	fromLine, toLine, pos := 0, 0, 0
+	for k := range hunks {
+		h := &hunks[k]
+		for ; pos < h.start; pos++ {
+			fromLine, toLine = advance(ops[pos].kind, fromLine, toLine)
+		}
+		h.fromLine, h.toLine = fromLine, toLine
+		for ; pos < h.end; pos++ {
+			fromLine, toLine = advance(ops[pos].kind, fromLine, toLine)
+		}
+		h.fromN, h.toN = fromLine-h.fromLine, toLine-h.toLine
+	}
+	return hunks
+}
+
+// advance сдвигает счетчики строк в соответствии с типом операции.
+func advance(kind byte, fromLine, toLine int) (int, int) {
+	switch kind {
+	case opEqual:
+		return fromLine + 1, toLine + 1
+	case opDelete:
+		return fromLine + 1, toLine
+	default:
+		return fromLine, toLine + 1
+	}
+}
+
+// writeTo записывает участок в формате unified diff.
+func (h hunk) writeTo(sb *strings.Builder, ops []operation) {
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", formatRange(h.fromLine, h.fromN), formatRange(h.toLine, h.toN))
+	for _, op := range ops[h.start:h.end] {
+		sb.WriteByte(op.kind)
+		sb.WriteString(op.line)
+		sb.WriteByte('\n')
+	}
+}
+
+// formatRange форматирует диапазон строк участка по правилам unified diff.
+func formatRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, count)
+	}
+}
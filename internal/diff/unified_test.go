@@ -0,0 +1,150 @@
+package diff
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	numbers := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n"
+
+	tests := []struct {
+		name    string
+		a, b    string
+		context int
+		want    string
+	}{
+		{name: "identical texts", a: numbers, b: numbers, context: 3, want: ""},
+		{name: "trailing newline is not a change", a: "a\nb", b: "a\nb\n", context: 3, want: ""},
+		{
+			name: "changed line with context", a: numbers, b: strings.Replace(numbers, "5\n", "five\n", 1), context: 3,
+			want: "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "insert into empty text", a: "", b: "x\ny\n", context: 3,
+			want: "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+x\n+y\n",
+		},
+		{
+			name: "delete single line", a: "x\n", b: "", context: 3,
+			want: "--- a\n+++ b\n@@ -1 +0,0 @@\n-x\n",
+		},
+		{
+			name: "distant changes form separate hunks", a: numbers, b: strings.NewReplacer("2\n", "two\n", "9\n", "nine\n").Replace(numbers), context: 1,
+			want: "--- a\n+++ b\n@@ -1,3 +1,3 @@\n 1\n-2\n+two\n 3\n@@ -8,3 +8,3 @@\n 8\n-9\n+nine\n 10\n",
+		},
+		{
+			name: "changes within twice the context share a hunk", a: numbers, b: strings.NewReplacer("3\n", "three\n", "5\n", "five\n").Replace(numbers), context: 1,
+			want: "--- a\n+++ b\n@@ -2,5 +2,5 @@\n 2\n-3\n+three\n 4\n-5\n+five\n 6\n",
+		},
+		{
+			name: "zero context", a: numbers, b: strings.Replace(numbers, "7\n", "", 1), context: 0,
+			want: "--- a\n+++ b\n@@ -7 +6,0 @@\n-7\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Unified(tt.a, tt.b, "a", "b", tt.context); got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestUnifiedApplies проверяет на случайных текстах, что diff корректен: применение его
+// участков к исходному тексту дает целевой, а строки контекста совпадают с исходными.
+func TestUnifiedApplies(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	randomText := func() string {
+		lines := make([]string, rng.Intn(30))
+		for i := range lines {
+			lines[i] = strconv.Itoa(rng.Intn(8))
+		}
+		if len(lines) == 0 {
+			return ""
+		}
+		return strings.Join(lines, "\n") + "\n"
+	}
+
+	for i := 0; i < 500; i++ {
+		a, b, context := randomText(), randomText(), rng.Intn(4)
+		patch := Unified(a, b, "a", "b", context)
+		got, err := apply(a, patch)
+		if err != nil {
+			t.Fatalf("apply diff of %q and %q: %v\n%s", a, b, err, patch)
+		}
+		if got != b {
+			t.Fatalf("apply diff of %q and %q = %q\n%s", a, b, got, patch)
+		}
+	}
+}
+
+// apply применяет unified diff к тексту, проверяя заголовки участков и строки контекста.
+func apply(text, patch string) (string, error) {
+	if patch == "" {
+		return text, nil
+	}
+	src := splitLines(text)
+	var out []string
+	pos := 0
+
+	lines := strings.Split(strings.TrimSuffix(patch, "\n"), "\n")[2:]
+	for len(lines) > 0 {
+		var fromStart, fromN, toStart, toN int
+		if _, err := fmt.Sscanf(normalizeHeader(lines[0]), "@@ -%d,%d +%d,%d @@", &fromStart, &fromN, &toStart, &toN); err != nil {
+			return "", fmt.Errorf("bad hunk header %q: %w", lines[0], err)
+		}
+		lines = lines[1:]
+
+		// Для пустого диапазона в заголовке указывается строка перед ним
+		first := fromStart - 1
+		if fromN == 0 {
+			first = fromStart
+		}
+		out = append(out, src[pos:first]...)
+		pos = first
+
+		gotFrom, gotTo := 0, 0
+		for len(lines) > 0 && !strings.HasPrefix(lines[0], "@@") {
+			line := lines[0]
+			lines = lines[1:]
+			switch line[0] {
+			case opEqual, opDelete:
+				if pos >= len(src) || src[pos] != line[1:] {
+					return "", fmt.Errorf("line %d does not match %q", pos+1, line)
+				}
+				if line[0] == opEqual {
+					out = append(out, line[1:])
+					gotTo++
+				}
+				pos++
+				gotFrom++
+			case opInsert:
+				out = append(out, line[1:])
+				gotTo++
+			}
+		}
+		if gotFrom != fromN || gotTo != toN {
+			return "", fmt.Errorf("hunk sizes %d,%d do not match header %d,%d", gotFrom, gotTo, fromN, toN)
+		}
+	}
+	out = append(out, src[pos:]...)
+	if len(out) == 0 {
+		return "", nil
+	}
+	return strings.Join(out, "\n") + "\n", nil
+}
+
+// normalizeHeader дописывает опущенное количество строк (",1") в диапазоны заголовка участка.
+func normalizeHeader(header string) string {
+	fields := strings.Fields(header)
+	for i := 1; i <= 2 && i < len(fields); i++ {
+		if !strings.Contains(fields[i], ",") {
+			fields[i] += ",1"
+		}
+	}
+	return strings.Join(fields, " ")
+}
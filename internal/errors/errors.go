@@ -7,27 +7,54 @@ type ErrorType string
 
 // Определение различных типов ошибок.
 const (
-	NotFound      ErrorType = "NOT_FOUND"
-	BadRequest    ErrorType = "BAD_REQUEST"
-	Internal      ErrorType = "INTERNAL"
-	Validation    ErrorType = "VALIDATION"
-	AlreadyExists ErrorType = "ALREADY_EXISTS"
+	NotFound             ErrorType = "NOT_FOUND"
+	Unauthorized         ErrorType = "UNAUTHORIZED"
+	Forbidden            ErrorType = "FORBIDDEN"
+	BadRequest           ErrorType = "BAD_REQUEST"
+	Internal             ErrorType = "INTERNAL"
+	Validation           ErrorType = "VALIDATION"
+	AlreadyExists        ErrorType = "ALREADY_EXISTS"
+	Conflict             ErrorType = "CONFLICT"
+	PreconditionFailed   ErrorType = "PRECONDITION_FAILED"
+	PreconditionRequired ErrorType = "PRECONDITION_REQUIRED"
+	UnsupportedMediaType ErrorType = "UNSUPPORTED_MEDIA_TYPE"
+	PayloadTooLarge      ErrorType = "PAYLOAD_TOO_LARGE"
+	Timeout              ErrorType = "TIMEOUT"
+	Canceled             ErrorType = "CANCELED"
+	Unavailable          ErrorType = "UNAVAILABLE"
 )
 
 // StatusCode - мапа с кодами статуса для каждого типа ошибки.
 var StatusCode = map[ErrorType]int{
-	NotFound:      404,
-	BadRequest:    400,
-	Internal:      500,
-	Validation:    422,
-	AlreadyExists: 409,
+	NotFound:             404,
+	Unauthorized:         401,
+	Forbidden:            403,
+	BadRequest:           400,
+	Internal:             500,
+	Validation:           422,
+	AlreadyExists:        409,
+	Conflict:             409,
+	PreconditionFailed:   412,
+	PreconditionRequired: 428,
+	UnsupportedMediaType: 415,
+	PayloadTooLarge:      413,
+	Timeout:              504,
+	Canceled:             499, // Client Closed Request: клиент отменил запрос, ответ ему уже не нужен
+	Unavailable:          503,
+}
+
+// FieldError - ошибка валидации отдельного поля запроса.
+type FieldError struct {
+	Field   string `json:"field"`   // Название поля
+	Message string `json:"message"` // Описание нарушения
 }
 
 // Error - структура, представляющая ошибку с дополнительной информацией.
 type Error struct {
-	Type    ErrorType // Тип ошибки
-	Message string    // Сообщение об ошибке
-	Err     error     // Вложенная ошибка, если есть
+	Type    ErrorType    // Тип ошибки
+	Message string       // Сообщение об ошибке
+	Err     error        // Вложенная ошибка, если есть
+	Fields  []FieldError // Ошибки отдельных полей, если есть
 }
 
 // Error - метод для реализации интерфейса error.
@@ -38,6 +65,11 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// Unwrap - метод возвращает вложенную ошибку.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
 // Status - метод возвращает код статуса для ошибки.
 func (e *Error) Status() int {
 	if code, exists := StatusCode[e.Type]; exists {
@@ -62,6 +94,16 @@ func NewNotFound(message string, err error) *Error {
 	return NewError(NotFound, message, err)
 }
 
+// NewUnauthorized создает ошибку типа Unauthorized.
+func NewUnauthorized(message string, err error) *Error {
+	return NewError(Unauthorized, message, err)
+}
+
+// NewForbidden создает ошибку типа Forbidden.
+func NewForbidden(message string, err error) *Error {
+	return NewError(Forbidden, message, err)
+}
+
 // NewBadRequest создает ошибку типа BadRequest.
 func NewBadRequest(message string, err error) *Error {
 	return NewError(BadRequest, message, err)
@@ -77,7 +119,54 @@ func NewValidation(message string, err error) *Error {
 	return NewError(Validation, message, err)
 }
 
+// NewValidationFields создает ошибку типа Validation со списком ошибок отдельных полей.
+func NewValidationFields(message string, fields []FieldError) *Error {
+	err := NewError(Validation, message, nil)
+	err.Fields = fields
+	return err
+}
+
 // NewAlreadyExists создает ошибку типа AlreadyExists.
 func NewAlreadyExists(message string, err error) *Error {
 	return NewError(AlreadyExists, message, err)
 }
+
+// NewConflict создает ошибку типа Conflict.
+func NewConflict(message string, err error) *Error {
+	return NewError(Conflict, message, err)
+}
+
+// NewPreconditionFailed создает ошибку типа PreconditionFailed.
+func NewPreconditionFailed(message string, err error) *Error {
+	return NewError(PreconditionFailed, message, err)
+}
+
+// NewPreconditionRequired создает ошибку типа PreconditionRequired.
+func NewPreconditionRequired(message string, err error) *Error {
+	return NewError(PreconditionRequired, message, err)
+}
+
+// NewUnsupportedMediaType создает ошибку типа UnsupportedMediaType.
+func NewUnsupportedMediaType(message string, err error) *Error {
+	return NewError(UnsupportedMediaType, message, err)
+}
+
+// NewPayloadTooLarge создает ошибку типа PayloadTooLarge.
+func NewPayloadTooLarge(message string, err error) *Error {
+	return NewError(PayloadTooLarge, message, err)
+}
+
+// NewTimeout создает ошибку типа Timeout.
+func NewTimeout(message string, err error) *Error {
+	return NewError(Timeout, message, err)
+}
+
+// NewCanceled создает ошибку типа Canceled.
+func NewCanceled(message string, err error) *Error {
+	return NewError(Canceled, message, err)
+}
+
+// NewUnavailable создает ошибку типа Unavailable.
+func NewUnavailable(message string, err error) *Error {
+	return NewError(Unavailable, message, err)
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"net/http"
+	"strings"
+)
+
+// ProblemContentType - тип содержимого ответа об ошибке (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// problemTypePrefix - префикс URI типа проблемы, за которым следует код ошибки.
+const problemTypePrefix = "urn:songs-library:problem:"
+
+// Problem - тело ответа об ошибке в формате RFC 7807 (problem details).
+type Problem struct {
+	Type     string       `json:"type" example:"urn:songs-library:problem:not-found"` // URI типа проблемы
+	Title    string       `json:"title" example:"Not Found"`                          // Краткое описание типа проблемы
+	Status   int          `json:"status" example:"404"`                               // HTTP-код ответа
+	Detail   string       `json:"detail,omitempty" example:"song not found"`          // Описание конкретного случая
+	Instance string       `json:"instance,omitempty" example:"/api/v1/songs/42"`      // URI запроса, вызвавшего ошибку
+	Code     ErrorType    `json:"code" example:"NOT_FOUND"`                           // Стабильный машиночитаемый код ошибки
+	Errors   []FieldError `json:"errors,omitempty"`                                   // Ошибки отдельных полей
+}
+
+// NewProblem формирует описание проблемы для ошибки.
+// Обернутые ошибки разворачиваются до *Error; остальные ошибки представляются
+// как внутренние без раскрытия деталей.
+func NewProblem(err error, instance string) *Problem {
+	var appErr *Error
+	if !stderrors.As(err, &appErr) {
+		appErr = NewInternal("Internal server error", err)
+	}
+
+	status := appErr.Status()
+	title := http.StatusText(status)
+	if status == StatusCode[Canceled] {
+		title = "Client Closed Request"
+	}
+	return &Problem{
+		Type:     problemTypePrefix + strings.ReplaceAll(strings.ToLower(string(appErr.Type)), "_", "-"),
+		Title:    title,
+		Status:   status,
+		Detail:   appErr.Message,
+		Instance: instance,
+		Code:     appErr.Type,
+		Errors:   appErr.Fields,
+	}
+}
+
+// WriteProblem отправляет описание проблемы в ответе.
+func WriteProblem(w http.ResponseWriter, problem *Problem) error {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(problem.Status)
+	return json.NewEncoder(w).Encode(problem)
+}
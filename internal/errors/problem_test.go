@@ -0,0 +1,37 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   ErrorType
+		wantDetail string
+	}{
+		{name: "application error", err: NewNotFound("song not found", nil), wantStatus: http.StatusNotFound, wantCode: NotFound, wantDetail: "song not found"},
+		{name: "wrapped application error", err: fmt.Errorf("get song: %w", NewNotFound("song not found", nil)), wantStatus: http.StatusNotFound, wantCode: NotFound, wantDetail: "song not found"},
+		{name: "joined application error", err: stderrors.Join(stderrors.New("cleanup failed"), NewAlreadyExists("name taken", nil)), wantStatus: http.StatusConflict, wantCode: AlreadyExists, wantDetail: "name taken"},
+		{name: "canceled", err: NewCanceled("request canceled", nil), wantStatus: 499, wantCode: Canceled, wantDetail: "request canceled"},
+		{name: "plain error", err: stderrors.New("connection reset: secret details"), wantStatus: http.StatusInternalServerError, wantCode: Internal, wantDetail: "Internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := NewProblem(tt.err, "/api/v1/songs/1")
+			if problem.Title == "" {
+				t.Error("empty title")
+			}
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode || problem.Detail != tt.wantDetail {
+				t.Errorf("problem = %d %s %q, want %d %s %q",
+					problem.Status, problem.Code, problem.Detail, tt.wantStatus, tt.wantCode, tt.wantDetail)
+			}
+		})
+	}
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type AlbumHandler struct {
+	responder
+	service *service.AlbumService
+}
+
+func NewAlbumHandler(service *service.AlbumService, logger *zap.Logger) *AlbumHandler {
+	return &AlbumHandler{
+		responder: responder{logger: logger},
+		service:   service,
+	}
+}
+
+// @Summary Get albums
+// @Description Get list of albums with track counts, optionally filtered by artist and a title substring
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param artist_id query int false "Artist ID"
+// @Param search query string false "Album title substring"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Success 200 {object} models.AlbumsResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums [get]
+func (h *AlbumHandler) GetAlbums(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetAlbums request")
+
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	filter := &models.AlbumFilter{
+		Search:   r.URL.Query().Get("search"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if artistIDStr := r.URL.Query().Get("artist_id"); artistIDStr != "" {
+		if filter.ArtistID, err = strconv.Atoi(artistIDStr); err != nil {
+			h.handleError(w, r, errors.NewBadRequest("Invalid artist ID", err))
+			return
+		}
+	}
+
+	response, err := h.service.GetAlbums(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// @Summary Get album
+// @Description Get album by ID with tracks in order. Songs in the trash are omitted, their track numbers are kept
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums/{id} [get]
+func (h *AlbumHandler) GetAlbum(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetAlbum request")
+
+	id, err := albumID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	album, err := h.service.GetAlbum(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, album)
+}
+
+// @Summary Create album
+// @Description Create a new album. Titles are unique per artist case-insensitively
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param album body models.AlbumRequest true "Album information"
+// @Success 201 {object} models.Album
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums [post]
+func (h *AlbumHandler) CreateAlbum(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling CreateAlbum request")
+
+	var req models.AlbumRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	album, err := h.service.CreateAlbum(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, album)
+}
+
+// @Summary Replace album
+// @Description Fully replace album information: omitted optional fields are cleared. Tracks are not changed
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param album body models.AlbumRequest true "Album information"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums/{id} [put]
+func (h *AlbumHandler) UpdateAlbum(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling UpdateAlbum request")
+
+	id, err := albumID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.AlbumRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	album, err := h.service.UpdateAlbum(r.Context(), id, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, album)
+}
+
+// @Summary Delete album
+// @Description Delete an album and its track listing. The songs themselves are kept
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums/{id} [delete]
+func (h *AlbumHandler) DeleteAlbum(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling DeleteAlbum request")
+
+	id, err := albumID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeleteAlbum(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Add album track
+// @Description Add a song to the album as track track_number, shifting tracks with this and greater numbers.
+// @Description If track_number is omitted, the song becomes the last track
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param track body models.AlbumTrackRequest true "Track information"
+// @Success 201 {object} models.Album
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums/{id}/tracks [post]
+func (h *AlbumHandler) AddAlbumTrack(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling AddAlbumTrack request")
+
+	id, err := albumID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.AlbumTrackRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	album, err := h.service.AddAlbumTrack(r.Context(), id, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, album)
+}
+
+// @Summary Move album track
+// @Description Move a song of the album to track track_number, shifting tracks with this and greater numbers
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param song_id path int true "Song ID"
+// @Param track body models.AlbumTrackPositionRequest true "New track number"
+// @Success 200 {object} models.Album
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums/{id}/tracks/{song_id} [put]
+func (h *AlbumHandler) MoveAlbumTrack(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling MoveAlbumTrack request")
+
+	id, songID, err := albumTrackIDs(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.AlbumTrackPositionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	album, err := h.service.MoveAlbumTrack(r.Context(), id, songID, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, album)
+}
+
+// @Summary Remove album track
+// @Description Remove a song from the album. Numbers of the other tracks are kept
+// @Tags albums
+// @Accept json
+// @Produce json
+// @Param id path int true "Album ID"
+// @Param song_id path int true "Song ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /albums/{id}/tracks/{song_id} [delete]
+func (h *AlbumHandler) RemoveAlbumTrack(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling RemoveAlbumTrack request")
+
+	id, songID, err := albumTrackIDs(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.RemoveAlbumTrack(r.Context(), id, songID); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// albumID разбирает идентификатор альбома из пути запроса.
+func albumID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return 0, errors.NewBadRequest("Invalid album ID", err)
+	}
+	return id, nil
+}
+
+// albumTrackIDs разбирает идентификаторы альбома и песни из пути запроса.
+func albumTrackIDs(r *http.Request) (int, int, error) {
+	id, err := albumID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	songID, err := strconv.Atoi(mux.Vars(r)["song_id"])
+	if err != nil {
+		return 0, 0, errors.NewBadRequest("Invalid song ID", err)
+	}
+	return id, songID, nil
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+type ArtistHandler struct {
+	responder
+	service *service.ArtistService
+}
+
+func NewArtistHandler(service *service.ArtistService, logger *zap.Logger) *ArtistHandler {
+	return &ArtistHandler{
+		responder: responder{logger: logger},
+		service:   service,
+	}
+}
+
+// @Summary Get artists
+// @Description Get list of artists with aliases and song counts, optionally filtered by a name or alias substring
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param search query string false "Artist name or alias substring"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Success 200 {object} models.ArtistsResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists [get]
+func (h *ArtistHandler) GetArtists(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetArtists request")
+
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	filter := &models.ArtistFilter{
+		Search:   r.URL.Query().Get("search"),
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	response, err := h.service.GetArtists(r.Context(), filter)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// @Summary Get artist
+// @Description Get artist by ID with aliases and the number of songs
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Success 200 {object} models.Artist
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists/{id} [get]
+func (h *ArtistHandler) GetArtist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetArtist request")
+
+	id, err := artistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	artist, err := h.service.GetArtist(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, artist)
+}
+
+// @Summary Create artist
+// @Description Create a new artist. Names are unique case-insensitively
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param artist body models.ArtistRequest true "Artist information"
+// @Success 201 {object} models.Artist
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists [post]
+func (h *ArtistHandler) CreateArtist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling CreateArtist request")
+
+	var req models.ArtistRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	artist, err := h.service.CreateArtist(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, artist)
+}
+
+// @Summary Rename artist
+// @Description Rename an artist. All songs of the artist get the new group name
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Param artist body models.ArtistRequest true "Artist information"
+// @Success 200 {object} models.Artist
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists/{id} [put]
+func (h *ArtistHandler) UpdateArtist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling UpdateArtist request")
+
+	id, err := artistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.ArtistRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	artist, err := h.service.UpdateArtist(r.Context(), id, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, artist)
+}
+
+// @Summary Delete artist
+// @Description Delete an artist without songs. Artists with songs, including songs in the trash, result in 409
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists/{id} [delete]
+func (h *ArtistHandler) DeleteArtist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling DeleteArtist request")
+
+	id, err := artistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeleteArtist(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Add artist alias
+// @Description Add an alternative spelling of the artist name. Songs created or filtered by the alias resolve to the artist
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Param alias body models.ArtistAliasRequest true "Alias information"
+// @Success 201 {object} models.Artist
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists/{id}/aliases [post]
+func (h *ArtistHandler) AddArtistAlias(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling AddArtistAlias request")
+
+	id, err := artistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.ArtistAliasRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	artist, err := h.service.AddArtistAlias(r.Context(), id, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, artist)
+}
+
+// @Summary Delete artist alias
+// @Description Delete an alias of the artist
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Param alias_id path int true "Alias ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists/{id}/aliases/{alias_id} [delete]
+func (h *ArtistHandler) DeleteArtistAlias(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling DeleteArtistAlias request")
+
+	id, err := artistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	aliasID, err := strconv.Atoi(mux.Vars(r)["alias_id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid alias ID", err))
+		return
+	}
+
+	if err := h.service.DeleteArtistAlias(r.Context(), id, aliasID); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Merge artists
+// @Description Atomically move all songs, including songs in the trash, and aliases of the source artist to the target artist.
+// @Description The source artist is deleted and its name becomes an alias of the target. Requires an authenticated user listed in ADMIN_PRINCIPALS
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param merge body models.MergeArtistsRequest true "Artists to merge"
+// @Success 200 {object} models.Artist
+// @Failure 400 {object} errors.Problem
+// @Failure 403 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /admin/artists/merge [post]
+func (h *ArtistHandler) MergeArtists(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling MergeArtists request")
+
+	var req models.MergeArtistsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	artist, err := h.service.MergeArtists(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, artist)
+}
+
+// @Summary Get artist songs
+// @Description Get songs of an artist with pagination
+// @Tags artists
+// @Accept json
+// @Produce json
+// @Param id path int true "Artist ID"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Success 200 {object} models.SongsResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /artists/{id}/songs [get]
+func (h *ArtistHandler) GetArtistSongs(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetArtistSongs request")
+
+	id, err := artistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response, err := h.service.GetArtistSongs(r.Context(), id, page, pageSize)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// artistID разбирает идентификатор исполнителя из пути запроса.
+func artistID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return 0, errors.NewBadRequest("Invalid artist ID", err)
+	}
+	return id, nil
+}
+
+// defaultPageSize - размер страницы, если параметр page_size не указан.
+const defaultPageSize = 10
+
+// parsePagination разбирает необязательные параметры page и page_size. Для отсутствующих параметров
+// возвращаются первая страница и размер по умолчанию.
+func parsePagination(r *http.Request) (page, pageSize int, err error) {
+	page, pageSize = 1, defaultPageSize
+	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
+		if page, err = strconv.Atoi(pageStr); err != nil {
+			return 0, 0, errors.NewBadRequest("Invalid page number", err)
+		}
+	}
+	if pageSizeStr := r.URL.Query().Get("page_size"); pageSizeStr != "" {
+		if pageSize, err = strconv.Atoi(pageSizeStr); err != nil {
+			return 0, 0, errors.NewBadRequest("Invalid page size", err)
+		}
+	}
+	return page, pageSize, nil
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+)
+
+// decodeJSON строго разбирает тело запроса в v: неизвестные поля, лишние данные после объекта
+// и превышение лимита размера тела считаются ошибкой с понятным клиенту описанием.
+func decodeJSON(r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return bodyError(err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		if err != nil {
+			return bodyError(err)
+		}
+		return errors.NewBadRequest("Invalid request body: unexpected data after JSON object", nil)
+	}
+	return nil
+}
+
+// readBody читает тело запроса целиком с учетом лимита размера.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, bodyError(err)
+	}
+	return body, nil
+}
+
+// bodyError преобразует ошибку чтения или разбора тела запроса в ошибку приложения.
+func bodyError(err error) error {
+	var (
+		maxBytesErr  *http.MaxBytesError
+		syntaxErr    *json.SyntaxError
+		typeErr      *json.UnmarshalTypeError
+		appErr       *errors.Error
+		unknownField = "json: unknown field "
+	)
+
+	switch {
+	case stderrors.As(err, &appErr):
+		return appErr
+	case stderrors.As(err, &maxBytesErr):
+		return errors.NewPayloadTooLarge(
+			fmt.Sprintf("Request body must not be larger than %d bytes", maxBytesErr.Limit), err)
+	case stderrors.Is(err, io.EOF):
+		return errors.NewBadRequest("Request body must not be empty", err)
+	case stderrors.Is(err, io.ErrUnexpectedEOF):
+		return errors.NewBadRequest("Invalid request body: unexpected end of JSON", err)
+	case stderrors.As(err, &syntaxErr):
+		return errors.NewBadRequest(
+			fmt.Sprintf("Invalid request body: malformed JSON at position %d", syntaxErr.Offset), err)
+	case stderrors.As(err, &typeErr):
+		if typeErr.Field != "" {
+			return errors.NewBadRequest(
+				fmt.Sprintf("Invalid request body: field %q must be of type %s", typeErr.Field, typeErr.Type), err)
+		}
+		return errors.NewBadRequest(
+			fmt.Sprintf("Invalid request body: expected JSON of type %s", typeErr.Type), err)
+	case strings.HasPrefix(err.Error(), unknownField):
+		return errors.NewBadRequest(
+			fmt.Sprintf("Invalid request body: unknown field %s", strings.TrimPrefix(err.Error(), unknownField)), err)
+	default:
+		return errors.NewBadRequest("Invalid request body", err)
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+)
+
+// songETag формирует ETag песни на основе ее версии.
+func songETag(song *models.Song) string {
+	return fmt.Sprintf(`"%d"`, song.Version)
+}
+
+// lyricsETag формирует ETag страницы текста песни на основе версии песни.
+func lyricsETag(response *models.LyricsResponse) string {
+	return fmt.Sprintf(`"%d"`, response.Version)
+}
+
+// songsETag формирует ETag страницы списка песен по идентификаторам, версиям и времени изменения песен,
+// а также параметрам пагинации: любое изменение, добавление или удаление песни на странице меняет ETag.
+func songsETag(response *models.SongsResponse) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%d:%d:%s:%s:%t:%t", response.Page, response.PageSize,
+		intKey(response.TotalPages), intKey(response.TotalItems), response.TotalItemsExact, response.HasNext)
+	for _, song := range response.Songs {
+		fmt.Fprintf(hash, ";%d:%d:%d", song.ID, song.Version, song.UpdatedAt.UnixNano())
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// intKey форматирует необязательное число для ETag.
+func intKey(value *int) string {
+	if value == nil {
+		return "-"
+	}
+	return strconv.Itoa(*value)
+}
+
+// songsLastModified возвращает время последнего изменения песен на странице списка.
+// Для песен в корзине учитывается и время удаления: перемещение в корзину не меняет updated_at.
+func songsLastModified(response *models.SongsResponse) time.Time {
+	var lastModified time.Time
+	for _, song := range response.Songs {
+		if song.UpdatedAt.After(lastModified) {
+			lastModified = song.UpdatedAt
+		}
+		if song.DeletedAt != nil && song.DeletedAt.After(lastModified) {
+			lastModified = *song.DeletedAt
+		}
+	}
+	return lastModified
+}
+
+// checkNotModified устанавливает валидаторы ETag и Last-Modified и проверяет условные заголовки запроса.
+// Если представление не изменилось, отправляет 304 Not Modified и возвращает true.
+// If-None-Match имеет приоритет над If-Modified-Since (RFC 9110, раздел 13.2.2).
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		if !etagMatches(ifNoneMatch, etag) {
+			return false
+		}
+	} else {
+		ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(ifModifiedSince) {
+			return false
+		}
+	}
+
+	// Тело и заголовки содержимого в ответе 304 не передаются
+	w.Header().Del("Content-Type")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches проверяет, содержит ли заголовок If-None-Match указанный ETag.
+// Используется слабое сравнение: префикс W/ игнорируется.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIfMatch извлекает ожидаемую версию песни из заголовка If-Match.
+// Возвращает 0, если заголовок отсутствует или равен "*".
+func (h *SongHandler) parseIfMatch(r *http.Request) (int, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" {
+		if h.requireIfMatch {
+			return 0, errors.NewPreconditionRequired("If-Match header is required", nil)
+		}
+		return 0, nil
+	}
+	if header == "*" {
+		return 0, nil
+	}
+
+	// Слабые ETag не допускаются в If-Match, поддерживается ровно одно значение
+	if strings.HasPrefix(header, "W/") || strings.Contains(header, ",") {
+		return 0, errors.NewBadRequest("If-Match must contain a single strong entity tag", nil)
+	}
+
+	version, err := strconv.Atoi(strings.Trim(header, `"`))
+	if err != nil || version < 1 {
+		return 0, errors.NewBadRequest("Invalid If-Match header", err)
+	}
+	return version, nil
+}
+
+// respondWithSong отправляет песню в ответе вместе с ее ETag и временем последнего изменения.
+func (h *SongHandler) respondWithSong(w http.ResponseWriter, status int, song *models.Song) {
+	w.Header().Set("ETag", songETag(song))
+	if !song.UpdatedAt.IsZero() {
+		w.Header().Set("Last-Modified", song.UpdatedAt.UTC().Format(http.TimeFormat))
+	}
+	h.respondWithJSON(w, status, song)
+}
@@ -1,47 +1,32 @@
 package handlers
 
 import (
-	"encoding/json"
+	"mime"
 	"net/http"
 	"strconv"
-	"time"
 
 	"github.com/ZnNr/songs-library/internal/errors"
 	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/patch"
 	"github.com/ZnNr/songs-library/internal/service"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
 type SongHandler struct {
-	service *service.SongService
-	logger  *zap.Logger
+	responder
+	service        *service.SongService
+	requireIfMatch bool // Требовать заголовок If-Match при изменении и удалении песен
 }
 
-func NewSongHandler(service *service.SongService, logger *zap.Logger) *SongHandler {
+func NewSongHandler(service *service.SongService, logger *zap.Logger, requireIfMatch bool) *SongHandler {
 	return &SongHandler{
-		service: service,
-		logger:  logger,
+		responder:      responder{logger: logger},
+		service:        service,
+		requireIfMatch: requireIfMatch,
 	}
 }
 
-func (h *SongHandler) handleError(w http.ResponseWriter, err error) {
-	var status int
-	var message string
-
-	if appErr, ok := err.(*errors.Error); ok {
-		status = appErr.Status()
-		message = appErr.Message
-	} else {
-		status = http.StatusInternalServerError
-		message = "Internal server error"
-	}
-
-	h.logger.Error("Request error", zap.Error(err), zap.Int("status", status), zap.String("message", message))
-
-	http.Error(w, message, status)
-}
-
 // @Summary Get songs with filtering and pagination
 // @Description Get list of songs with optional filtering and pagination
 // @Tags songs
@@ -49,29 +34,43 @@ func (h *SongHandler) handleError(w http.ResponseWriter, err error) {
 // @Produce json
 // @Param group_name query string false "Group name"
 // @Param song_name query string false "Song name"
-// @Param from_date query string false "From date (format: 2006-01-02)"
-// @Param to_date query string false "To date (format: 2006-01-02)"
+// @Param album query int false "Album ID"
+// @Param from_date query string false "From date (formats: 2006-01-02, 02.01.2006, 2006-01, 2006); partial dates start at the beginning of the period"
+// @Param to_date query string false "To date (formats: 2006-01-02, 02.01.2006, 2006-01, 2006); partial dates end at the end of the period"
 // @Param text query string false "Text content"
 // @Param link query string false "Link"
-// @Param page query int false "Page number"
-// @Param page_size query int false "Page size"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Param count query string false "Total count mode: exact (default), estimate (planner statistics) or none (only has_next)" Enums(exact, estimate, none)
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Param If-Modified-Since header string false "Date of a cached response"
 // @Success 200 {object} models.SongsResponse
+// @Header 200 {string} ETag "Entity tag of the page"
+// @Header 200 {string} Last-Modified "Latest modification time of songs on the page"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
 // @Router /songs [get]
 func (h *SongHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
-	h.logger.Debug("Handling GetSongs request")
+	h.log(r).Debug("Handling GetSongs request")
 
 	filter := &models.SongFilter{
 		GroupName: r.URL.Query().Get("group_name"),
 		SongName:  r.URL.Query().Get("song_name"),
 		Text:      r.URL.Query().Get("text"),
 		Link:      r.URL.Query().Get("link"),
+		Count:     models.CountMode(r.URL.Query().Get("count")),
+		Page:      1,
+		PageSize:  defaultPageSize,
 	}
 
 	if pageStr := r.URL.Query().Get("page"); pageStr != "" {
 		if page, err := strconv.Atoi(pageStr); err == nil {
 			filter.Page = page
 		} else {
-			h.handleError(w, errors.NewBadRequest("Invalid page number", err))
+			h.handleError(w, r, errors.NewBadRequest("Invalid page number", err))
 			return
 		}
 	}
@@ -79,44 +78,48 @@ func (h *SongHandler) GetSongs(w http.ResponseWriter, r *http.Request) {
 		if pageSize, err := strconv.Atoi(pageSizeStr); err == nil {
 			filter.PageSize = pageSize
 		} else {
-			h.handleError(w, errors.NewBadRequest("Invalid page size", err))
+			h.handleError(w, r, errors.NewBadRequest("Invalid page size", err))
+			return
+		}
+	}
+
+	if albumStr := r.URL.Query().Get("album"); albumStr != "" {
+		if albumID, err := strconv.Atoi(albumStr); err == nil {
+			filter.AlbumID = albumID
+		} else {
+			h.handleError(w, r, errors.NewBadRequest("Invalid album ID", err))
 			return
 		}
 	}
 
 	if fromDateStr := r.URL.Query().Get("from_date"); fromDateStr != "" {
-		if fromDate, err := time.Parse("2006-01-02", fromDateStr); err == nil {
-			filter.FromDate = &fromDate
+		if fromDate, err := models.ParseReleaseDate(fromDateStr); err == nil {
+			filter.FromDate = &fromDate.Time
 		} else {
-			h.handleError(w, errors.NewBadRequest("Invalid from_date format", err))
+			h.handleError(w, r, errors.NewBadRequest("Invalid from_date format", err))
 			return
 		}
 	}
 	if toDateStr := r.URL.Query().Get("to_date"); toDateStr != "" {
-		if toDate, err := time.Parse("2006-01-02", toDateStr); err == nil {
-			filter.ToDate = &toDate
+		if toDate, err := models.ParseReleaseDate(toDateStr); err == nil {
+			end := toDate.End()
+			filter.ToDate = &end
 		} else {
-			h.handleError(w, errors.NewBadRequest("Invalid to_date format", err))
+			h.handleError(w, r, errors.NewBadRequest("Invalid to_date format", err))
 			return
 		}
 	}
 
 	response, err := h.service.GetSongs(r.Context(), filter)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusOK, response)
-}
-
-func (h *SongHandler) respondWithJSON(w http.ResponseWriter, status int, payload interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	if err := json.NewEncoder(w).Encode(payload); err != nil {
-		h.handleError(w, errors.NewValidation("json encode error", err))
+	if checkNotModified(w, r, songsETag(response), songsLastModified(response)) {
+		return
 	}
+	h.respondWithJSON(w, http.StatusOK, response)
 }
 
 // @Summary Get song lyrics
@@ -127,15 +130,23 @@ func (h *SongHandler) respondWithJSON(w http.ResponseWriter, status int, payload
 // @Param id path int true "Song ID"
 // @Param page query int false "Page number"
 // @Param page_size query int false "Page size"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Param If-Modified-Since header string false "Date of a cached response"
 // @Success 200 {object} models.LyricsResponse
+// @Header 200 {string} ETag "Song version"
+// @Header 200 {string} Last-Modified "Song modification time"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
 // @Router /songs/{id}/lyrics [get]
 func (h *SongHandler) GetLyrics(w http.ResponseWriter, r *http.Request) {
-	h.logger.Debug("Handling GetLyrics request")
+	h.log(r).Debug("Handling GetLyrics request")
 
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.handleError(w, errors.NewBadRequest("Invalid song ID", err))
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
 		return
 	}
 
@@ -144,7 +155,7 @@ func (h *SongHandler) GetLyrics(w http.ResponseWriter, r *http.Request) {
 		if parsedPage, err := strconv.Atoi(pageStr); err == nil {
 			page = parsedPage
 		} else {
-			h.handleError(w, errors.NewBadRequest("Invalid page query", err))
+			h.handleError(w, r, errors.NewBadRequest("Invalid page query", err))
 			return
 		}
 	}
@@ -152,100 +163,463 @@ func (h *SongHandler) GetLyrics(w http.ResponseWriter, r *http.Request) {
 		if parsedPageSize, err := strconv.Atoi(pageSizeStr); err == nil {
 			pageSize = parsedPageSize
 		} else {
-			h.handleError(w, errors.NewBadRequest("Invalid page size query", err))
+			h.handleError(w, r, errors.NewBadRequest("Invalid page size query", err))
 			return
 		}
 	}
 
 	response, err := h.service.GetLyrics(r.Context(), id, page, pageSize)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
+	if checkNotModified(w, r, lyricsETag(response), response.UpdatedAt) {
+		return
+	}
 	h.respondWithJSON(w, http.StatusOK, response)
 }
 
+// @Summary Get song
+// @Description Get a song by ID. The ETag header contains the song version
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Param If-Modified-Since header string false "Date of a cached response"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Song version"
+// @Header 200 {string} Last-Modified "Song modification time"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /songs/{id} [get]
+func (h *SongHandler) GetSong(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetSong request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	song, err := h.service.GetSong(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if checkNotModified(w, r, songETag(song), song.UpdatedAt) {
+		return
+	}
+	h.respondWithSong(w, http.StatusOK, song)
+}
+
 // @Summary Create new song
-// @Description Create a new song with information from external API
+// @Description Create a new song with information from external API. With album_id the song is added to the album
+// @Description as track track_number, shifting later tracks, or as the last track if track_number is omitted
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param song body models.SongRequest true "Song information"
 // @Success 201 {object} models.Song
+// @Header 201 {string} ETag "Song version"
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
 // @Router /songs [post]
 func (h *SongHandler) CreateSong(w http.ResponseWriter, r *http.Request) {
-	h.logger.Debug("Handling CreateSong request")
+	h.log(r).Debug("Handling CreateSong request")
 
 	var req models.SongRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.handleError(w, errors.NewBadRequest("Invalid request body", err))
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
 	song, err := h.service.CreateSong(r.Context(), &req)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusCreated, song)
+	h.respondWithSong(w, http.StatusCreated, song)
 }
 
-// @Summary Update song
-// @Description Update existing song information
+// @Summary Replace song
+// @Description Fully replace existing song information: omitted optional fields are cleared. A stale If-Match version results in 412
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "Expected song ETag"
 // @Param song body models.SongRequest true "Song information"
 // @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Song version"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 412 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 428 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
 // @Router /songs/{id} [put]
 func (h *SongHandler) UpdateSong(w http.ResponseWriter, r *http.Request) {
-	h.logger.Debug("Handling UpdateSong request")
+	h.log(r).Debug("Handling UpdateSong request")
 
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.handleError(w, errors.NewBadRequest("Invalid song ID", err))
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	version, err := h.parseIfMatch(r)
+	if err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
 	var req models.SongRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.handleError(w, errors.NewBadRequest("Invalid request body", err))
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	song, err := h.service.UpdateSong(r.Context(), id, &req, version)
+	if err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
-	song, err := h.service.UpdateSong(r.Context(), id, &req)
+	h.respondWithSong(w, http.StatusOK, song)
+}
+
+// @Summary Patch song
+// @Description Partially update a song using JSON Merge Patch (RFC 7396, null clears a field) or JSON Patch (RFC 6902).
+// @Description The patch is applied to a document with the fields group, song, release_date, text and link.
+// @Tags songs
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param If-Match header string false "Expected song ETag"
+// @Param patch body models.SongDocument true "Merge patch document or array of JSON Patch operations"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Song version"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 412 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 415 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 428 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /songs/{id} [patch]
+func (h *SongHandler) PatchSong(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling PatchSong request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	version, err := h.parseIfMatch(r)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	h.respondWithJSON(w, http.StatusOK, song)
+	patchType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		h.handleError(w, r, errors.NewUnsupportedMediaType("Invalid Content-Type header", err))
+		return
+	}
+	// Обычный JSON трактуется как merge patch
+	if patchType == "application/json" {
+		patchType = patch.MergePatchType
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	song, err := h.service.PatchSong(r.Context(), id, patchType, body, version)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithSong(w, http.StatusOK, song)
 }
 
 // @Summary Delete song
-// @Description Delete a song by ID
+// @Description Move a song to the trash by ID. A stale If-Match version results in 412
 // @Tags songs
 // @Accept json
 // @Produce json
 // @Param id path int true "Song ID"
+// @Param If-Match header string false "Expected song ETag"
 // @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 412 {object} errors.Problem
+// @Failure 428 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
 // @Router /songs/{id} [delete]
 func (h *SongHandler) DeleteSong(w http.ResponseWriter, r *http.Request) {
-	h.logger.Debug("Handling DeleteSong request")
+	h.log(r).Debug("Handling DeleteSong request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	version, err := h.parseIfMatch(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeleteSong(r.Context(), id, version); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Get song revisions
+// @Description Get stored revision history of a song
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {array} models.SongRevision
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /songs/{id}/revisions [get]
+func (h *SongHandler) GetRevisions(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetRevisions request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, revisions)
+}
+
+// @Summary Diff song revisions
+// @Description Get a line-level unified diff of the lyrics and field-level metadata changes between two revisions
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param from query int false "Source revision (default: revision preceding 'to')"
+// @Param to query int false "Target revision (default: latest revision)"
+// @Success 200 {object} models.SongDiff
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /songs/{id}/diff [get]
+func (h *SongHandler) DiffRevisions(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling DiffRevisions request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	var from, to int
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		if from, err = strconv.Atoi(fromStr); err != nil || from < 1 {
+			h.handleError(w, r, errors.NewBadRequest("Invalid from revision", err))
+			return
+		}
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		if to, err = strconv.Atoi(toStr); err != nil || to < 1 {
+			h.handleError(w, r, errors.NewBadRequest("Invalid to revision", err))
+			return
+		}
+	}
+
+	response, err := h.service.DiffRevisions(r.Context(), id, from, to)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// @Summary Revert song
+// @Description Restore a song to a previous revision, saving it as a new revision
+// @Tags songs
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Param rev query int true "Revision to restore"
+// @Param If-Match header string false "Expected song ETag"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Song version"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 412 {object} errors.Problem
+// @Failure 428 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /songs/{id}/revert [post]
+func (h *SongHandler) RevertSong(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling RevertSong request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	rev, err := strconv.Atoi(r.URL.Query().Get("rev"))
+	if err != nil || rev < 1 {
+		h.handleError(w, r, errors.NewBadRequest("Invalid revision", err))
+		return
+	}
+
+	version, err := h.parseIfMatch(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	song, err := h.service.RevertSong(r.Context(), id, rev, version)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithSong(w, http.StatusOK, song)
+}
+
+// @Summary Get trash
+// @Description Get list of deleted songs with pagination
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Param If-None-Match header string false "ETag of a cached response"
+// @Param If-Modified-Since header string false "Date of a cached response"
+// @Success 200 {object} models.SongsResponse
+// @Header 200 {string} ETag "Entity tag of the page"
+// @Header 200 {string} Last-Modified "Latest modification time of songs on the page"
+// @Success 304 "Not Modified"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /trash [get]
+func (h *SongHandler) GetTrash(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetTrash request")
+
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response, err := h.service.GetTrash(r.Context(), page, pageSize)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if checkNotModified(w, r, songsETag(response), songsLastModified(response)) {
+		return
+	}
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// @Summary Restore song
+// @Description Restore a deleted song from the trash
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 200 {object} models.Song
+// @Header 200 {string} ETag "Song version"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /songs/{id}/restore [post]
+func (h *SongHandler) RestoreSong(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling RestoreSong request")
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
+		return
+	}
+
+	song, err := h.service.RestoreSong(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithSong(w, http.StatusOK, song)
+}
+
+// @Summary Purge song
+// @Description Permanently delete a song from the trash
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param id path int true "Song ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /trash/{id} [delete]
+func (h *SongHandler) PurgeSong(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling PurgeSong request")
 
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		h.handleError(w, errors.NewBadRequest("Invalid song ID", err))
+		h.handleError(w, r, errors.NewBadRequest("Invalid song ID", err))
 		return
 	}
 
-	if err := h.service.DeleteSong(r.Context(), id); err != nil {
-		h.handleError(w, err)
+	if err := h.service.PurgeSong(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"github.com/ZnNr/songs-library/internal/service"
+	"go.uber.org/zap"
+)
+
+// trashRepoStub возвращает одну и ту же страницу корзины.
+type trashRepoStub struct {
+	repository.SongRepository
+	response *models.SongsResponse
+}
+
+func (r *trashRepoStub) GetDeletedSongs(ctx context.Context, page, pageSize int) (*models.SongsResponse, error) {
+	response := *r.response
+	return &response, nil
+}
+
+func TestGetTrashConditional(t *testing.T) {
+	updated := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	deleted := updated.Add(time.Hour)
+	total := 1
+	repo := &trashRepoStub{response: &models.SongsResponse{
+		Songs:      []models.Song{{ID: 1, GroupName: "Muse", Version: 2, UpdatedAt: updated, DeletedAt: &deleted}},
+		Page:       1,
+		PageSize:   10,
+		TotalPages: &total,
+		TotalItems: &total,
+	}}
+	handler := NewSongHandler(service.NewSongService(repo, zap.NewNop()), zap.NewNop(), false)
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/trash", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		handler.GetTrash(rec, req)
+		return rec
+	}
+
+	first := get("", "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("status = %d, ETag = %q; want 200 with an ETag", first.Code, etag)
+	}
+	if lastModified := first.Header().Get("Last-Modified"); lastModified != deleted.Format(http.TimeFormat) {
+		t.Errorf("Last-Modified = %q, want the deletion time %q", lastModified, deleted.Format(http.TimeFormat))
+	}
+
+	if rec := get("If-None-Match", etag); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("If-None-Match: status = %d, body %d bytes; want 304 without a body", rec.Code, rec.Body.Len())
+	}
+	if rec := get("If-Modified-Since", deleted.Format(http.TimeFormat)); rec.Code != http.StatusNotModified {
+		t.Errorf("If-Modified-Since: status = %d, want 304", rec.Code)
+	}
+	// Песня удалена позже, чем изменена, поэтому дата изменения не подтверждает кэш
+	if rec := get("If-Modified-Since", updated.Format(http.TimeFormat)); rec.Code != http.StatusOK {
+		t.Errorf("stale If-Modified-Since: status = %d, want 200", rec.Code)
+	}
+	if rec := get("If-None-Match", `"stale"`); rec.Code != http.StatusOK {
+		t.Errorf("stale If-None-Match: status = %d, want 200", rec.Code)
+	}
+}
+
+func TestMergeArtistIntoItself(t *testing.T) {
+	// Репозиторий не должен вызываться: запрос отклоняется валидацией
+	handler := NewArtistHandler(service.NewArtistService(nil, nil, zap.NewNop()), zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/artists/merge", strings.NewReader(`{"source_id": 7, "target_id": 7}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.MergeArtists(rec, req)
+
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("status = %d, want 422; body %s", rec.Code, rec.Body)
+	}
+}
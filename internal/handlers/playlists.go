@@ -0,0 +1,429 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/playlistfmt"
+	"github.com/ZnNr/songs-library/internal/service"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// playlistCacheControl - заголовок Cache-Control ответов с плейлистами. Содержимое зависит от пользователя
+// и токена ссылки, поэтому ответы не должны сохраняться в общих кэшах.
+const playlistCacheControl = "private, no-cache"
+
+type PlaylistHandler struct {
+	responder
+	service *service.PlaylistService
+}
+
+func NewPlaylistHandler(service *service.PlaylistService, logger *zap.Logger) *PlaylistHandler {
+	return &PlaylistHandler{
+		responder: responder{logger: logger},
+		service:   service,
+	}
+}
+
+// @Summary Get own playlists
+// @Description Get playlists of the authenticated user ordered by name
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Success 200 {object} models.PlaylistsResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists [get]
+func (h *PlaylistHandler) GetPlaylists(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetPlaylists request")
+
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response, err := h.service.GetPlaylists(r.Context(), page, pageSize)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", playlistCacheControl)
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// @Summary Get playlist
+// @Description Get playlist by ID with a page of its entries in order. Other users can see public playlists
+// @Description and unlisted playlists with the share token. Entries whose songs are in the trash keep their
+// @Description positions and are returned as unavailable without song data
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param token query string false "Share token of an unlisted playlist"
+// @Param page query int false "Page number (default 1)"
+// @Param page_size query int false "Page size from 1 to 100 (default 10)"
+// @Success 200 {object} models.PlaylistResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id} [get]
+func (h *PlaylistHandler) GetPlaylist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling GetPlaylist request")
+
+	id, err := playlistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	page, pageSize, err := parsePagination(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response, err := h.service.GetPlaylist(r.Context(), id, r.URL.Query().Get("token"), page, pageSize)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Cache-Control", playlistCacheControl)
+	h.respondWithJSON(w, http.StatusOK, response)
+}
+
+// @Summary Create playlist
+// @Description Create a playlist owned by the authenticated user. Unlisted playlists get a share token
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param playlist body models.PlaylistRequest true "Playlist information"
+// @Success 201 {object} models.Playlist
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists [post]
+func (h *PlaylistHandler) CreatePlaylist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling CreatePlaylist request")
+
+	var req models.PlaylistRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	playlist, err := h.service.CreatePlaylist(r.Context(), &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, playlist)
+}
+
+// @Summary Update playlist
+// @Description Rename a playlist or change its visibility. The share token is kept while the playlist stays
+// @Description unlisted and revoked when the visibility changes
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param playlist body models.PlaylistRequest true "Playlist information"
+// @Success 200 {object} models.Playlist
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id} [put]
+func (h *PlaylistHandler) UpdatePlaylist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling UpdatePlaylist request")
+
+	id, err := playlistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.PlaylistRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	playlist, err := h.service.UpdatePlaylist(r.Context(), id, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, playlist)
+}
+
+// @Summary Delete playlist
+// @Description Delete a playlist of the authenticated user. The songs themselves are kept
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id} [delete]
+func (h *PlaylistHandler) DeletePlaylist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling DeletePlaylist request")
+
+	id, err := playlistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.DeletePlaylist(r.Context(), id); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Add playlist entry
+// @Description Add a song to the playlist at the given position. If position is omitted or exceeds the number
+// @Description of entries, the song is appended. The same song may be added several times
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param entry body models.PlaylistEntryRequest true "Entry information"
+// @Success 201 {object} models.PlaylistEntry
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id}/songs [post]
+func (h *PlaylistHandler) AddPlaylistEntry(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling AddPlaylistEntry request")
+
+	id, err := playlistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.PlaylistEntryRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	entry, err := h.service.AddPlaylistEntry(r.Context(), id, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, entry)
+}
+
+// @Summary Move playlist entry
+// @Description Move a playlist entry to the given position. Only the moved entry changes its sort key
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param entry_id path int true "Playlist entry ID"
+// @Param entry body models.PlaylistPositionRequest true "New position"
+// @Success 200 {object} models.PlaylistEntry
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id}/songs/{entry_id} [put]
+func (h *PlaylistHandler) MovePlaylistEntry(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling MovePlaylistEntry request")
+
+	id, entryID, err := playlistEntryIDs(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	var req models.PlaylistPositionRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	entry, err := h.service.MovePlaylistEntry(r.Context(), id, entryID, &req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusOK, entry)
+}
+
+// @Summary Remove playlist entry
+// @Description Remove an entry from the playlist. Positions of the following entries move up by one
+// @Tags playlists
+// @Accept json
+// @Produce json
+// @Param id path int true "Playlist ID"
+// @Param entry_id path int true "Playlist entry ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id}/songs/{entry_id} [delete]
+func (h *PlaylistHandler) RemovePlaylistEntry(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling RemovePlaylistEntry request")
+
+	id, entryID, err := playlistEntryIDs(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	if err := h.service.RemovePlaylistEntry(r.Context(), id, entryID); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Export playlist
+// @Description Export a playlist as an M3U8, XSPF or JSPF file. Track locations are song links; songs in the trash
+// @Description are skipped, and M3U8 also skips songs without a link. Access rules are the same as for GET /playlists/{id}.
+// @Description An unsupported format is rejected with 400 listing the supported formats
+// @Tags playlists
+// @Produce audio/x-mpegurl,application/xspf+xml,application/jspf+json
+// @Param id path int true "Playlist ID"
+// @Param format path string true "File format" Enums(m3u8, xspf, jspf)
+// @Param token query string false "Share token of an unlisted playlist"
+// @Success 200 {file} file
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/{id}.{format} [get]
+func (h *PlaylistHandler) ExportPlaylist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling ExportPlaylist request")
+
+	id, err := playlistID(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	format := mux.Vars(r)["format"]
+	data, err := h.service.ExportPlaylist(r.Context(), id, r.URL.Query().Get("token"), format)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	// Формат уже проверен сервисом
+	contentType, _ := playlistfmt.ContentType(format)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="playlist-%d.%s"`, id, format))
+	w.Header().Set("Cache-Control", playlistCacheControl)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		h.log(r).Error("Failed to write playlist file", zap.Error(err))
+	}
+}
+
+// @Summary Import playlist
+// @Description Create a playlist of the authenticated user from an M3U8, XSPF or JSPF file. The format is taken from
+// @Description the format query parameter or the Content-Type header. Tracks are matched to library songs by artist
+// @Description (or artist alias) and title, exactly first and then by trigram similarity; fuzzy matches are flagged.
+// @Description Tracks without a matching song are reported in unmatched and not added. The file title is used as the
+// @Description playlist name unless name is given
+// @Tags playlists
+// @Accept audio/x-mpegurl,application/xspf+xml,application/jspf+json,json
+// @Produce json
+// @Param format query string false "File format, overrides Content-Type" Enums(m3u8, xspf, jspf)
+// @Param name query string false "Playlist name"
+// @Param visibility query string false "Playlist visibility, private by default" Enums(private, unlisted, public)
+// @Param file body string true "Playlist file"
+// @Success 201 {object} models.PlaylistImportResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 401 {object} errors.Problem
+// @Failure 413 {object} errors.Problem
+// @Failure 415 {object} errors.Problem
+// @Failure 422 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /playlists/import [post]
+func (h *PlaylistHandler) ImportPlaylist(w http.ResponseWriter, r *http.Request) {
+	h.log(r).Debug("Handling ImportPlaylist request")
+
+	query := r.URL.Query()
+	format := query.Get("format")
+	if format == "" {
+		var err error
+		if format, err = playlistfmt.FormatFromContentType(r.Header.Get("Content-Type")); err != nil {
+			h.handleError(w, r, errors.NewUnsupportedMediaType("Unsupported playlist Content-Type", err))
+			return
+		}
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	req := &models.PlaylistRequest{
+		Name:       query.Get("name"),
+		Visibility: models.PlaylistVisibility(query.Get("visibility")),
+	}
+	response, err := h.service.ImportPlaylist(r.Context(), format, body, req)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	h.respondWithJSON(w, http.StatusCreated, response)
+}
+
+// playlistID разбирает идентификатор плейлиста из пути запроса.
+func playlistID(r *http.Request) (int, error) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		return 0, errors.NewBadRequest("Invalid playlist ID", err)
+	}
+	return id, nil
+}
+
+// playlistEntryIDs разбирает идентификаторы плейлиста и его элемента из пути запроса.
+func playlistEntryIDs(r *http.Request) (int, int, error) {
+	id, err := playlistID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	entryID, err := strconv.Atoi(mux.Vars(r)["entry_id"])
+	if err != nil {
+		return 0, 0, errors.NewBadRequest("Invalid playlist entry ID", err)
+	}
+	return id, entryID, nil
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// responder содержит общие для обработчиков логирование и формирование ответов.
+type responder struct {
+	logger *zap.Logger
+}
+
+// log возвращает логгер запроса, обогащенный идентификатором запроса и маршрутом.
+func (h *responder) log(r *http.Request) *zap.Logger {
+	return logctx.From(r.Context(), h.logger)
+}
+
+// handleError отправляет ошибку в формате problem details (RFC 7807).
+func (h *responder) handleError(w http.ResponseWriter, r *http.Request, err error) {
+	problem := errors.NewProblem(err, r.URL.Path)
+	trace.SpanFromContext(r.Context()).RecordError(err)
+
+	// Отмена запроса клиентом - не ошибка сервиса
+	level := zap.ErrorLevel
+	if problem.Code == errors.Canceled {
+		level = zap.InfoLevel
+	}
+	h.log(r).Log(level, "Request error",
+		zap.Error(err),
+		zap.Int("status", problem.Status),
+		zap.String("code", string(problem.Code)),
+		zap.String("message", problem.Detail))
+
+	// Пользователь определяется по заголовку прокси или Basic-аутентификации
+	if problem.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Basic realm="songs-library"`)
+	}
+	if err := errors.WriteProblem(w, problem); err != nil {
+		h.log(r).Error("Failed to write error response", zap.Error(err))
+	}
+}
+
+// respondWithJSON отправляет ответ в формате JSON.
+func (h *responder) respondWithJSON(w http.ResponseWriter, status int, payload interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	// Заголовки уже отправлены, поэтому ошибку кодирования можно только залогировать
+	if err := json.NewEncoder(w).Encode(payload); err != nil {
+		h.logger.Error("Failed to encode response", zap.Error(err))
+	}
+}
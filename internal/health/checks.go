@@ -0,0 +1,81 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DatabaseCheck проверяет соединение с базой данных.
+func DatabaseCheck(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// MigrationsCheck проверяет, что схема базы данных не находится в "грязном" состоянии после
+// прерванной миграции и ее версия не ниже ожидаемой. При expected, равном 0, версия не сравнивается.
+func MigrationsCheck(db *sql.DB, expected uint) Check {
+	return func(ctx context.Context) error {
+		var (
+			version int64
+			dirty   bool
+		)
+		err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("no migrations applied")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read migration version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("migration %d is dirty", version)
+		}
+		if version < int64(expected) {
+			return fmt.Errorf("schema version %d is behind expected %d", version, expected)
+		}
+		return nil
+	}
+}
+
+// CachedHTTPCheck проверяет доступность внешнего HTTP-сервиса по адресу url.
+// Ответ со статусом ниже 500 считается доступностью. Результат кэшируется на ttl,
+// чтобы частые проверки готовности не нагружали внешний сервис.
+func CachedHTTPCheck(client *http.Client, url string, ttl time.Duration) Check {
+	var (
+		mu        sync.Mutex
+		checkedAt time.Time
+		lastErr   error
+	)
+
+	probe := func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode >= http.StatusInternalServerError {
+			return fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		return nil
+	}
+
+	return func(ctx context.Context) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if !checkedAt.IsZero() && time.Since(checkedAt) < ttl {
+			return lastErr
+		}
+		lastErr = probe(ctx)
+		checkedAt = time.Now()
+		return lastErr
+	}
+}
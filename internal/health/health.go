@@ -0,0 +1,149 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Статусы проверок и сервиса в целом.
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+	StatusDegraded    = "degraded"
+)
+
+// Check проверяет доступность зависимости и возвращает ошибку, если она недоступна.
+type Check func(ctx context.Context) error
+
+// check - зарегистрированная проверка готовности.
+type check struct {
+	name     string
+	fn       Check
+	critical bool
+}
+
+// CheckResult - результат одной проверки.
+type CheckResult struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Response - тело ответа эндпоинтов проверки состояния.
+type Response struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Checker отвечает на проверки жизнеспособности, готовности и завершения запуска приложения.
+type Checker struct {
+	logger       *zap.Logger
+	timeout      time.Duration
+	checks       []check
+	started      atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+// New создает Checker. timeout ограничивает время выполнения всех проверок готовности.
+func New(logger *zap.Logger, timeout time.Duration) *Checker {
+	return &Checker{logger: logger, timeout: timeout}
+}
+
+// AddCheck регистрирует обязательную проверку готовности: ее провал делает сервис неготовым.
+func (c *Checker) AddCheck(name string, fn Check) {
+	c.checks = append(c.checks, check{name: name, fn: fn, critical: true})
+}
+
+// AddOptionalCheck регистрирует необязательную проверку: ее провал отражается в ответе
+// статусом degraded, но не выводит сервис из балансировки.
+func (c *Checker) AddOptionalCheck(name string, fn Check) {
+	c.checks = append(c.checks, check{name: name, fn: fn})
+}
+
+// SetStarted отмечает, что запуск приложения завершен.
+func (c *Checker) SetStarted() {
+	c.started.Store(true)
+}
+
+// SetShuttingDown переводит проверку готовности в состояние отказа перед остановкой сервера.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Live обрабатывает /healthz: процесс жив и обслуживает запросы.
+func (c *Checker) Live(w http.ResponseWriter, r *http.Request) {
+	c.respond(w, http.StatusOK, Response{Status: StatusOK})
+}
+
+// Startup обрабатывает /startupz: миграции применены и фоновые задачи запущены.
+func (c *Checker) Startup(w http.ResponseWriter, r *http.Request) {
+	if !c.started.Load() {
+		c.respond(w, http.StatusServiceUnavailable, Response{Status: StatusUnavailable})
+		return
+	}
+	c.respond(w, http.StatusOK, Response{Status: StatusOK})
+}
+
+// Ready обрабатывает /readyz: выполняет проверки зависимостей параллельно.
+func (c *Checker) Ready(w http.ResponseWriter, r *http.Request) {
+	if c.shuttingDown.Load() {
+		c.respond(w, http.StatusServiceUnavailable, Response{
+			Status: StatusUnavailable,
+			Checks: map[string]CheckResult{"shutdown": {Status: StatusUnavailable, Error: "server is shutting down"}},
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), c.timeout)
+	defer cancel()
+
+	results := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func(i int, chk check) {
+			defer wg.Done()
+			results[i] = chk.fn(ctx)
+		}(i, chk)
+	}
+	wg.Wait()
+
+	response := Response{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+	status := http.StatusOK
+	for i, chk := range c.checks {
+		err := results[i]
+		if err == nil {
+			response.Checks[chk.name] = CheckResult{Status: StatusOK}
+			continue
+		}
+
+		c.logger.Warn("Readiness check failed", zap.String("check", chk.name), zap.Error(err))
+		if chk.critical {
+			response.Checks[chk.name] = CheckResult{Status: StatusUnavailable, Error: err.Error()}
+			response.Status = StatusUnavailable
+			status = http.StatusServiceUnavailable
+		} else {
+			response.Checks[chk.name] = CheckResult{Status: StatusDegraded, Error: err.Error()}
+			if response.Status == StatusOK {
+				response.Status = StatusDegraded
+			}
+		}
+	}
+
+	c.respond(w, status, response)
+}
+
+// respond записывает ответ проверки состояния. Ответы не кэшируются.
+func (c *Checker) respond(w http.ResponseWriter, status int, response Response) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		c.logger.Error("Failed to write health response", zap.Error(err))
+	}
+}
@@ -0,0 +1,65 @@
+package logctx
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// ctxKey - тип ключей контекста пакета, исключающий коллизии с другими пакетами.
+type ctxKey int
+
+const (
+	loggerKey ctxKey = iota
+	requestIDKey
+	principalKey
+	userKey
+)
+
+// WithLogger сохраняет логгер запроса в контексте.
+func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// From возвращает логгер запроса из контекста или fallback, если его нет.
+func From(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*zap.Logger); ok {
+		return logger
+	}
+	return fallback
+}
+
+// WithRequestID сохраняет идентификатор запроса в контексте.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID возвращает идентификатор запроса из контекста или пустую строку.
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+// WithPrincipal сохраняет заявленное имя пользователя, выполняющего запрос, в контексте.
+// Имя не проверяется и используется только в логах; для проверки прав используется User.
+func WithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// Principal возвращает заявленное имя пользователя, выполняющего запрос, или пустую строку.
+func Principal(ctx context.Context) string {
+	principal, _ := ctx.Value(principalKey).(string)
+	return principal
+}
+
+// WithUser сохраняет в контексте аутентифицированного пользователя: подтвержденного доверенным прокси
+// или прошедшего проверку пароля Basic-аутентификации.
+func WithUser(ctx context.Context, user string) context.Context {
+	return context.WithValue(ctx, userKey, user)
+}
+
+// User возвращает аутентифицированного пользователя или пустую строку для анонимного запроса.
+func User(ctx context.Context) string {
+	user, _ := ctx.Value(userKey).(string)
+	return user
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// CacheStats - статистика использования кэша.
+type CacheStats struct {
+	Name      string // Название кэша
+	Hits      uint64 // Количество попаданий
+	Misses    uint64 // Количество промахов
+	Evictions uint64 // Количество вытесненных элементов
+	Entries   int    // Текущее количество элементов
+}
+
+// RegisterCache регистрирует метрики кэшей, статистика которых возвращается функцией stats при каждом сборе.
+func (m *Metrics) RegisterCache(stats func() []CacheStats) {
+	m.registry.MustRegister(newCacheCollector(stats))
+}
+
+// cacheCollector собирает метрики попаданий, промахов и размера кэшей.
+type cacheCollector struct {
+	stats     func() []CacheStats
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	entries   *prometheus.Desc
+}
+
+func newCacheCollector(stats func() []CacheStats) *cacheCollector {
+	labels := []string{"cache"}
+	return &cacheCollector{
+		stats: stats,
+		hits: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "hits_total"),
+			"Total number of cache hits.", labels, nil),
+		misses: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "misses_total"),
+			"Total number of cache misses.", labels, nil),
+		evictions: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "evictions_total"),
+			"Total number of entries evicted because the cache was full.", labels, nil),
+		entries: prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", "entries"),
+			"Current number of cache entries.", labels, nil),
+	}
+}
+
+// Describe реализует prometheus.Collector.
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.evictions
+	ch <- c.entries
+}
+
+// Collect реализует prometheus.Collector.
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(s.Evictions), s.Name)
+		ch <- prometheus.MustNewConstMetric(c.entries, prometheus.GaugeValue, float64(s.Entries), s.Name)
+	}
+}
@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// tableSizeQuery возвращает размер таблицы songs на диске вместе с индексами и оценку количества строк
+// по статистике планировщика, чтобы сбор метрик не требовал полного сканирования таблицы.
+const tableSizeQuery = `
+	SELECT pg_total_relation_size(c.oid), GREATEST(c.reltuples, 0)::bigint
+	FROM pg_class c
+	WHERE c.oid = 'songs'::regclass`
+
+// tableSizeTimeout ограничивает время запроса размера таблицы при сборе метрик.
+const tableSizeTimeout = 2 * time.Second
+
+// tableSizeCollector собирает метрики размера таблицы songs.
+type tableSizeCollector struct {
+	db        *sql.DB
+	logger    *zap.Logger
+	sizeBytes *prometheus.Desc
+	rows      *prometheus.Desc
+}
+
+func newTableSizeCollector(db *sql.DB, logger *zap.Logger) *tableSizeCollector {
+	return &tableSizeCollector{
+		db:     db,
+		logger: logger,
+		sizeBytes: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "songs_table", "size_bytes"),
+			"Total size of the songs table including indexes and TOAST.",
+			nil, nil),
+		rows: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "songs_table", "rows_estimate"),
+			"Estimated number of rows in the songs table from planner statistics.",
+			nil, nil),
+	}
+}
+
+// Describe реализует prometheus.Collector.
+func (c *tableSizeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.sizeBytes
+	ch <- c.rows
+}
+
+// Collect реализует prometheus.Collector.
+func (c *tableSizeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), tableSizeTimeout)
+	defer cancel()
+
+	var sizeBytes, rows int64
+	if err := c.db.QueryRowContext(ctx, tableSizeQuery).Scan(&sizeBytes, &rows); err != nil {
+		c.logger.Warn("Failed to collect songs table size", zap.Error(err))
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.sizeBytes, prometheus.GaugeValue, float64(sizeBytes))
+	ch <- prometheus.MustNewConstMetric(c.rows, prometheus.GaugeValue, float64(rows))
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// AdminMiddleware пропускает к административным маршрутам только пользователей из списка admins.
+// Используется пользователь, аутентифицированный AuthMiddleware, а не заявленный в заголовках.
+// Если список пуст, административные маршруты недоступны никому.
+func AdminMiddleware(admins []string, logger *zap.Logger) func(http.Handler) http.Handler {
+	allowed := make(map[string]struct{}, len(admins))
+	for _, admin := range admins {
+		allowed[admin] = struct{}{}
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user := logctx.User(r.Context())
+			if _, ok := allowed[user]; !ok || user == "" {
+				logctx.From(r.Context(), logger).Warn("Admin access denied", zap.String("user", user))
+				problem := errors.NewProblem(errors.NewForbidden("admin access required", nil), r.URL.Path)
+				if err := errors.WriteProblem(w, problem); err != nil {
+					logctx.From(r.Context(), logger).Error("Failed to write error response", zap.Error(err))
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AuthOptions - настройки аутентификации запросов.
+type AuthOptions struct {
+	TrustedProxies []netip.Prefix    // Адреса прокси, которым разрешено передавать пользователя в X-Forwarded-User
+	BasicAuthUsers map[string]string // Пользователи Basic-аутентификации и bcrypt-хэши их паролей
+}
+
+// AuthMiddleware определяет аутентифицированного пользователя запроса и сохраняет его в контексте.
+// Заголовок X-Forwarded-User принимается только от доверенных прокси и удаляется из остальных запросов.
+// Учетные данные Basic-аутентификации проверяются по списку пользователей; неверные отклоняются с 401.
+// Запросы без учетных данных пропускаются анонимными, права проверяются в обработчиках и AdminMiddleware.
+func AuthMiddleware(opts AuthOptions, logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var user string
+			if trustedPeer(r.RemoteAddr, opts.TrustedProxies) {
+				user = r.Header.Get(PrincipalHeader)
+			} else {
+				r.Header.Del(PrincipalHeader)
+			}
+
+			if name, password, ok := r.BasicAuth(); ok && user == "" {
+				if !checkPassword(opts.BasicAuthUsers, name, password) {
+					logctx.From(r.Context(), logger).Warn("Authentication failed", zap.String("user", name))
+					w.Header().Set("WWW-Authenticate", `Basic realm="songs-library"`)
+					problem := errors.NewProblem(errors.NewUnauthorized("invalid credentials", nil), r.URL.Path)
+					if err := errors.WriteProblem(w, problem); err != nil {
+						logctx.From(r.Context(), logger).Error("Failed to write error response", zap.Error(err))
+					}
+					return
+				}
+				user = name
+			}
+
+			if user != "" {
+				ctx := logctx.WithUser(r.Context(), user)
+				ctx = logctx.WithLogger(ctx, logctx.From(ctx, logger).With(zap.String("user", user)))
+				r = r.WithContext(ctx)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// trustedPeer проверяет, что непосредственный отправитель запроса входит в список доверенных прокси.
+func trustedPeer(remoteAddr string, proxies []netip.Prefix) bool {
+	if len(proxies) == 0 {
+		return false
+	}
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, proxy := range proxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// dummyPasswordHash - bcrypt-хэш, с которым сравнивается пароль неизвестного пользователя,
+// чтобы время ответа не раскрывало существование имени.
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("songs-library"), bcrypt.DefaultCost)
+
+// checkPassword сравнивает пароль с bcrypt-хэшем пользователя.
+func checkPassword(users map[string]string, name, password string) bool {
+	hash, known := users[name]
+	if !known {
+		_ = bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := AuthOptions{
+		TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		BasicAuthUsers: map[string]string{"alice": string(hash)},
+	}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		header     string
+		basicUser  string
+		basicPass  string
+		wantStatus int
+		wantUser   string
+	}{
+		{name: "anonymous", remoteAddr: "192.0.2.1:1234", wantStatus: http.StatusOK},
+		{name: "header from trusted proxy", remoteAddr: "10.1.2.3:1234", header: "bob", wantStatus: http.StatusOK, wantUser: "bob"},
+		{name: "header from untrusted peer is dropped", remoteAddr: "192.0.2.1:1234", header: "admin", wantStatus: http.StatusOK},
+		{name: "mapped IPv4 proxy address", remoteAddr: "[::ffff:10.0.0.1]:1234", header: "bob", wantStatus: http.StatusOK, wantUser: "bob"},
+		{name: "valid basic credentials", remoteAddr: "192.0.2.1:1234", basicUser: "alice", basicPass: "secret", wantStatus: http.StatusOK, wantUser: "alice"},
+		{name: "wrong password", remoteAddr: "192.0.2.1:1234", basicUser: "alice", basicPass: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "unknown basic user", remoteAddr: "192.0.2.1:1234", basicUser: "admin", basicPass: "secret", wantStatus: http.StatusUnauthorized},
+		{name: "spoofed header with bad password", remoteAddr: "192.0.2.1:1234", header: "alice", basicUser: "alice", basicPass: "guess", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUser, gotHeader string
+			handler := AuthMiddleware(opts, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotUser = logctx.User(r.Context())
+				gotHeader = r.Header.Get(PrincipalHeader)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/playlists", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.header != "" {
+				req.Header.Set(PrincipalHeader, tt.header)
+			}
+			if tt.basicUser != "" {
+				req.SetBasicAuth(tt.basicUser, tt.basicPass)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized {
+				if rec.Header().Get("WWW-Authenticate") == "" {
+					t.Error("missing WWW-Authenticate header")
+				}
+				return
+			}
+			if gotUser != tt.wantUser {
+				t.Errorf("user = %q, want %q", gotUser, tt.wantUser)
+			}
+			if gotUser == "" && gotHeader != "" {
+				t.Errorf("%s = %q reached the handler from an untrusted peer", PrincipalHeader, gotHeader)
+			}
+		})
+	}
+}
+
+func TestAdminMiddlewareUsesAuthenticatedUser(t *testing.T) {
+	handler := AdminMiddleware([]string{"root"}, zap.NewNop())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name       string
+		ctx        func(r *http.Request) *http.Request
+		wantStatus int
+	}{
+		{name: "claimed principal only", ctx: func(r *http.Request) *http.Request {
+			return r.WithContext(logctx.WithPrincipal(r.Context(), "root"))
+		}, wantStatus: http.StatusForbidden},
+		{name: "authenticated admin", ctx: func(r *http.Request) *http.Request {
+			return r.WithContext(logctx.WithUser(r.Context(), "root"))
+		}, wantStatus: http.StatusOK},
+		{name: "authenticated non-admin", ctx: func(r *http.Request) *http.Request {
+			return r.WithContext(logctx.WithUser(r.Context(), "bob"))
+		}, wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, tt.ctx(httptest.NewRequest(http.MethodPost, "/api/v1/admin/artists/merge", nil)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
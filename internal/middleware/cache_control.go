@@ -0,0 +1,23 @@
+package middleware
+
+import "net/http"
+
+// CacheControlMiddleware устанавливает заголовок Cache-Control для ответов на GET и HEAD запросы.
+// Для маршрутов из routes, заданных в виде "METHOD /шаблон/маршрута", используется собственное значение,
+// для остальных - defaultValue. Обработчик может переопределить заголовок.
+func CacheControlMiddleware(defaultValue string, routes map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				value := defaultValue
+				if routeValue, ok := routes[r.Method+" "+routeTemplate(r)]; ok {
+					value = routeValue
+				}
+				if value != "" {
+					w.Header().Set("Cache-Control", value)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Поддерживаемые алгоритмы сжатия ответа.
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+)
+
+// compressibleTypes - типы содержимого, которые имеет смысл сжимать.
+var compressibleTypes = []string{
+	"application/json",
+	"application/problem+json",
+	"application/xml",
+	"application/javascript",
+	"text/",
+	"image/svg+xml",
+}
+
+// CompressionMiddleware сжимает ответы алгоритмом br или gzip в зависимости от заголовка Accept-Encoding.
+// Ответы короче minSize байт, ответы без тела и уже сжатые ответы передаются без изменений.
+// Сильный ETag сжатого ответа получает суффикс кодировки ("5" -> "5-gzip"), так как байты представлений
+// различаются; в If-Match и If-None-Match запроса суффиксы удаляются до передачи обработчику.
+func CompressionMiddleware(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			ifNoneMatch := r.Header.Get("If-None-Match")
+			for _, name := range []string{"If-Match", "If-None-Match"} {
+				if value := r.Header.Get(name); value != "" {
+					r.Header.Set(name, stripETagEncodings(value))
+				}
+			}
+
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize, ifNoneMatch: ifNoneMatch}
+			defer cw.Close()
+
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding выбирает алгоритм сжатия по заголовку Accept-Encoding с учетом q-значений.
+// При равных весах предпочитается br.
+func negotiateEncoding(header string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		switch name {
+		case encodingBrotli, encodingGzip:
+		case "*":
+			name = encodingBrotli
+		default:
+			continue
+		}
+		if q > bestQ || (q == bestQ && name == encodingBrotli) {
+			best, bestQ = name, q
+		}
+	}
+	return best
+}
+
+// encodedETag добавляет суффикс кодировки к сильному ETag. Слабые ETag не меняются:
+// слабое сравнение и так допускает различие представлений.
+func encodedETag(etag, encoding string) string {
+	if len(etag) < 2 || !strings.HasPrefix(etag, `"`) || !strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + encoding + `"`
+}
+
+// stripETagEncodings удаляет суффиксы кодировок из ETag условного заголовка запроса.
+func stripETagEncodings(header string) string {
+	tags := strings.Split(header, ",")
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		for _, encoding := range []string{encodingBrotli, encodingGzip} {
+			if trimmed, ok := strings.CutSuffix(tag, "-"+encoding+`"`); ok {
+				tag = trimmed + `"`
+				break
+			}
+		}
+		tags[i] = tag
+	}
+	return strings.Join(tags, ", ")
+}
+
+// compressWriter накапливает начало ответа до minSize байт и решает, сжимать ли его.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding    string
+	minSize     int
+	ifNoneMatch string // Исходный If-None-Match запроса с суффиксами кодировок
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	encoder     io.WriteCloser
+}
+
+// WriteHeader откладывает отправку статуса до решения о сжатии.
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	cw.status = code
+	cw.wroteHeader = true
+
+	// Ответы без тела отправляются сразу
+	if code == http.StatusNoContent || code == http.StatusNotModified || code < http.StatusOK {
+		// 304 подтверждает сжатое представление, если клиент прислал его ETag
+		if code == http.StatusNotModified {
+			etag := cw.Header().Get("ETag")
+			if encoded := encodedETag(etag, cw.encoding); encoded != etag && strings.Contains(cw.ifNoneMatch, encoded) {
+				cw.Header().Set("ETag", encoded)
+			}
+		}
+		cw.decided = true
+		cw.ResponseWriter.WriteHeader(code)
+	}
+}
+
+// Write буферизует данные до minSize байт, затем пишет их в кодировщик или напрямую.
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.encoder != nil {
+			return cw.encoder.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) < cw.minSize {
+		return len(b), nil
+	}
+	if err := cw.decide(true); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// decide отправляет заголовки и накопленные данные, включая сжатие, если оно уместно.
+func (cw *compressWriter) decide(large bool) error {
+	cw.decided = true
+	header := cw.Header()
+
+	if large && header.Get("Content-Encoding") == "" && compressible(header.Get("Content-Type")) {
+		header.Set("Content-Encoding", cw.encoding)
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" {
+			header.Set("ETag", encodedETag(etag, cw.encoding))
+		}
+		if cw.encoding == encodingBrotli {
+			cw.encoder = brotli.NewWriterLevel(cw.ResponseWriter, brotli.DefaultCompression)
+		} else {
+			cw.encoder = gzip.NewWriter(cw.ResponseWriter)
+		}
+	}
+
+	cw.ResponseWriter.WriteHeader(cw.status)
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	buf := cw.buf
+	cw.buf = nil
+	if cw.encoder != nil {
+		_, err := cw.encoder.Write(buf)
+		return err
+	}
+	_, err := cw.ResponseWriter.Write(buf)
+	return err
+}
+
+// Close завершает ответ: отправляет короткий ответ без сжатия или дописывает сжатый поток.
+func (cw *compressWriter) Close() error {
+	if !cw.wroteHeader {
+		return nil
+	}
+	if !cw.decided {
+		return cw.decide(false)
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Close()
+	}
+	return nil
+}
+
+// Flush отправляет накопленные данные клиенту, если это поддерживает исходный ResponseWriter.
+func (cw *compressWriter) Flush() {
+	if cw.wroteHeader && !cw.decided {
+		_ = cw.decide(len(cw.buf) >= cw.minSize)
+	}
+	if flusher, ok := cw.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	if flusher, ok := cw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack передает управление соединением, если это поддерживает исходный ResponseWriter.
+func (cw *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if hijacker, ok := cw.ResponseWriter.(http.Hijacker); ok {
+		return hijacker.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// compressible проверяет, имеет ли смысл сжимать содержимое указанного типа.
+func compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	for _, prefix := range compressibleTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCompressionMiddlewareETag(t *testing.T) {
+	body := strings.Repeat(`{"song":"Supermassive Black Hole"}`, 64)
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		ifNoneMatch    string
+		ifMatch        string
+		wantStatus     int
+		wantETag       string
+		wantEncoding   string
+		wantIfMatch    string
+	}{
+		{name: "identity", wantStatus: http.StatusOK, wantETag: `"5"`},
+		{name: "gzip", acceptEncoding: "gzip", wantStatus: http.StatusOK, wantETag: `"5-gzip"`, wantEncoding: "gzip"},
+		{name: "br", acceptEncoding: "gzip, br", wantStatus: http.StatusOK, wantETag: `"5-br"`, wantEncoding: "br"},
+		{name: "revalidate gzip", acceptEncoding: "gzip", ifNoneMatch: `"5-gzip"`, wantStatus: http.StatusNotModified, wantETag: `"5-gzip"`},
+		{name: "revalidate identity", ifNoneMatch: `"5"`, wantStatus: http.StatusNotModified, wantETag: `"5"`},
+		{name: "stale gzip", acceptEncoding: "gzip", ifNoneMatch: `"4-gzip"`, wantStatus: http.StatusOK, wantETag: `"5-gzip"`, wantEncoding: "gzip"},
+		{name: "if-match with encoding suffix", ifMatch: `"5-br"`, wantStatus: http.StatusOK, wantETag: `"5"`, wantIfMatch: `"5"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotIfMatch string
+			handler := CompressionMiddleware(16)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotIfMatch = r.Header.Get("If-Match")
+				w.Header().Set("ETag", `"5"`)
+				if r.Header.Get("If-None-Match") == `"5"` {
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(body))
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/songs/1", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %s, want %s", got, tt.wantETag)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tt.wantEncoding {
+				t.Errorf("Content-Encoding = %q, want %q", got, tt.wantEncoding)
+			}
+			if gotIfMatch != tt.wantIfMatch {
+				t.Errorf("If-Match seen by handler = %s, want %s", gotIfMatch, tt.wantIfMatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// ReadYourWritesHeader - заголовок, которым клиент запрашивает чтение с основной базы данных,
+// например сразу после собственного изменения.
+const ReadYourWritesHeader = "X-Read-Your-Writes"
+
+// ReadYourWritesMiddleware направляет чтения на основную базу данных для изменяющих запросов
+// и для запросов с заголовком X-Read-Your-Writes: true.
+func ReadYourWritesMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isSafeMethod(r.Method) || requestsReadYourWrites(r) {
+			r = r.WithContext(repository.WithReadYourWrites(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isSafeMethod проверяет, что метод не изменяет состояние ресурса.
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// requestsReadYourWrites проверяет заголовок X-Read-Your-Writes.
+func requestsReadYourWrites(r *http.Request) bool {
+	value, err := strconv.ParseBool(r.Header.Get(ReadYourWritesHeader))
+	return err == nil && value
+}
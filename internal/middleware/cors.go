@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSOptions описывает политику CORS.
+type CORSOptions struct {
+	AllowedOrigins   []string      // Разрешенные источники; "*" разрешает любой источник
+	AllowedMethods   []string      // Методы, разрешенные в предварительных запросах
+	AllowedHeaders   []string      // Заголовки запроса, разрешенные в предварительных запросах
+	ExposedHeaders   []string      // Заголовки ответа, доступные скриптам браузера
+	AllowCredentials bool          // Разрешить передачу cookie и заголовка Authorization
+	MaxAge           time.Duration // Время кэширования ответа на предварительный запрос
+}
+
+// CORSMiddleware добавляет заголовки CORS для разрешенных источников и отвечает 204
+// на предварительные запросы (OPTIONS с заголовком Access-Control-Request-Method).
+// Запросы без заголовка Origin и запросы с неразрешенных источников передаются дальше без изменений.
+func CORSMiddleware(opts CORSOptions) func(http.Handler) http.Handler {
+	allowAny := false
+	allowed := make(map[string]struct{}, len(opts.AllowedOrigins))
+	for _, origin := range opts.AllowedOrigins {
+		if origin == "*" {
+			allowAny = true
+		}
+		allowed[strings.ToLower(origin)] = struct{}{}
+	}
+
+	allowedMethods := strings.Join(opts.AllowedMethods, ", ")
+	allowedHeaders := strings.Join(opts.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(opts.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(opts.MaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Ответ зависит от источника, поэтому кэши должны учитывать заголовок Origin
+			w.Header().Add("Vary", "Origin")
+
+			if _, ok := allowed[strings.ToLower(origin)]; !ok && !allowAny {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if allowAny && !opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Add("Vary", "Access-Control-Request-Method")
+				w.Header().Add("Vary", "Access-Control-Request-Headers")
+				w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+				if allowedHeaders != "" {
+					w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				}
+				if opts.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PreflightHandler отвечает на запросы OPTIONS, которые не были обработаны CORSMiddleware,
+// перечисляя методы ресурса в заголовке Allow.
+func PreflightHandler(methods []string) http.HandlerFunc {
+	allow := strings.Join(append([]string{http.MethodOptions}, methods...), ", ")
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", allow)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
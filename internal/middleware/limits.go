@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// TimeoutMiddleware устанавливает дедлайн контекста запроса. Для маршрутов из routeTimeouts,
+// заданных в виде "METHOD /шаблон/маршрута", используется собственное значение, для остальных - defaultTimeout.
+// Нулевое значение отключает дедлайн.
+func TimeoutMiddleware(defaultTimeout time.Duration, routeTimeouts map[string]time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := defaultTimeout
+			if routeTimeout, ok := routeTimeouts[r.Method+" "+routeTemplate(r)]; ok {
+				timeout = routeTimeout
+			}
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// BodyLimitMiddleware ограничивает размер тела запроса maxBytes байтами.
+// Чтение сверх лимита завершается ошибкой *http.MaxBytesError. Нулевое значение отключает ограничение.
+func BodyLimitMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if maxBytes > 0 && r.Body != nil {
+				r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/ZnNr/songs-library/internal/logctx"
 	"go.uber.org/zap"
 )
 
@@ -12,6 +13,7 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			start := time.Now()
+			requestLogger := logctx.From(r.Context(), logger)
 
 			// Создаем ResponseWriter, который может отслеживать статус ответа
 			wrappedWriter := &responseWriter{
@@ -20,13 +22,13 @@ func LoggingMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
 			}
 
 			// Логируем входящий запрос
-			logRequest(logger, r)
+			logRequest(requestLogger, r)
 
 			// Передаем запрос дальше
 			next.ServeHTTP(wrappedWriter, r)
 
 			// Логируем результат запроса
-			logResponse(logger, r, wrappedWriter, time.Since(start))
+			logResponse(requestLogger, r, wrappedWriter, time.Since(start))
 		})
 	}
 }
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// HTTPObserver учитывает завершенные HTTP-запросы.
+type HTTPObserver interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+}
+
+// MetricsMiddleware создает middleware для учета количества и длительности HTTP запросов
+// в разрезе шаблона маршрута и статуса ответа.
+func MetricsMiddleware(observer HTTPObserver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			wrappedWriter := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+
+			next.ServeHTTP(wrappedWriter, r)
+
+			observer.ObserveHTTPRequest(r.Method, routeTemplate(r), wrappedWriter.status, time.Since(start))
+		})
+	}
+}
+
+// routeTemplate возвращает шаблон маршрута mux, чтобы метрики не зависели от значений параметров пути.
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+	return "unmatched"
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"go.uber.org/zap"
+)
+
+// RecoveryMiddleware перехватывает панику в обработчике, логирует ее вместе со стеком вызовов
+// и отвечает клиенту 500 в формате problem+json, если ответ еще не начат.
+func RecoveryMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			wrappedWriter := &responseWriter{
+				ResponseWriter: w,
+				status:         http.StatusOK,
+			}
+
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+				// http.ErrAbortHandler используется для намеренного разрыва соединения
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logctx.From(r.Context(), logger).Error("Panic while handling request",
+					zap.Any("panic", recovered),
+					zap.String("method", r.Method),
+					zap.String("path", r.URL.Path),
+					zap.ByteString("stack", debug.Stack()))
+
+				if wrappedWriter.wroteHeader {
+					return
+				}
+				problem := errors.NewProblem(
+					errors.NewInternal("internal server error", fmt.Errorf("panic: %v", recovered)), r.URL.Path)
+				if err := errors.WriteProblem(wrappedWriter, problem); err != nil {
+					logctx.From(r.Context(), logger).Error("Failed to write error response", zap.Error(err))
+				}
+			}()
+
+			next.ServeHTTP(wrappedWriter, r)
+		})
+	}
+}
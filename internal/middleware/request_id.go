@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// RequestIDHeader - заголовок с идентификатором запроса.
+	RequestIDHeader = "X-Request-ID"
+	// PrincipalHeader - заголовок, в котором аутентифицирующий прокси передает имя пользователя.
+	PrincipalHeader = "X-Forwarded-User"
+
+	maxRequestIDLength = 128
+)
+
+// RequestIDMiddleware принимает идентификатор запроса из заголовка X-Request-ID или генерирует новый,
+// возвращает его в ответе и сохраняет в контексте логгер, обогащенный идентификатором запроса,
+// шаблоном маршрута, пользователем и идентификаторами трассировки.
+func RequestIDMiddleware(logger *zap.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, requestID)
+
+			fields := []zap.Field{zap.String("request_id", requestID)}
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil {
+					fields = append(fields, zap.String("route", template))
+				}
+			}
+
+			if spanContext := trace.SpanContextFromContext(r.Context()); spanContext.IsValid() {
+				fields = append(fields,
+					zap.String("trace_id", spanContext.TraceID().String()),
+					zap.String("span_id", spanContext.SpanID().String()))
+			}
+
+			ctx := logctx.WithRequestID(r.Context(), requestID)
+			if principal := principal(r); principal != "" {
+				ctx = logctx.WithPrincipal(ctx, principal)
+				fields = append(fields, zap.String("principal", principal))
+			}
+			ctx = logctx.WithLogger(ctx, logger.With(fields...))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// principal определяет заявленного пользователя, выполняющего запрос, для логов: из заголовка
+// аутентифицирующего прокси или из имени Basic-аутентификации. Значение не проверяется, права доступа
+// определяются по пользователю, установленному AuthMiddleware.
+func principal(r *http.Request) string {
+	if user := r.Header.Get(PrincipalHeader); user != "" {
+		return user
+	}
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	return ""
+}
+
+// validRequestID проверяет, что идентификатор запроса не пуст, ограничен по длине
+// и состоит из печатных ASCII-символов, безопасных для логов и заголовков.
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(requestID); i++ {
+		if c := requestID[i]; c < '!' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID генерирует случайный идентификатор запроса.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
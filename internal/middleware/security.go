@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityHeadersOptions описывает заголовки безопасности ответа.
+type SecurityHeadersOptions struct {
+	HSTSMaxAge   time.Duration // Срок действия Strict-Transport-Security (0 - заголовок не отправляется)
+	FrameOptions string        // Значение X-Frame-Options: DENY или SAMEORIGIN
+	// UIPathPrefix - префикс страниц с HTML-интерфейсом (swagger), для которых не устанавливается
+	// строгая политика Content-Security-Policy, запрещающая скрипты и стили
+	UIPathPrefix string
+}
+
+// apiContentSecurityPolicy запрещает загрузку любых ресурсов: API отдает только JSON.
+const apiContentSecurityPolicy = "default-src 'none'; frame-ancestors 'none'"
+
+// SecurityHeadersMiddleware добавляет заголовки безопасности ко всем ответам.
+func SecurityHeadersMiddleware(opts SecurityHeadersOptions) func(http.Handler) http.Handler {
+	hsts := ""
+	if opts.HSTSMaxAge > 0 {
+		hsts = "max-age=" + strconv.Itoa(int(opts.HSTSMaxAge.Seconds())) + "; includeSubDomains"
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			header.Set("X-Content-Type-Options", "nosniff")
+			header.Set("Referrer-Policy", "no-referrer")
+			if opts.FrameOptions != "" {
+				header.Set("X-Frame-Options", opts.FrameOptions)
+			}
+			if hsts != "" {
+				header.Set("Strict-Transport-Security", hsts)
+			}
+			if opts.UIPathPrefix == "" || !strings.HasPrefix(r.URL.Path, opts.UIPathPrefix) {
+				header.Set("Content-Security-Policy", apiContentSecurityPolicy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Album представляет альбом исполнителя с упорядоченным списком треков.
+type Album struct {
+	ID          int          `json:"id" db:"id"`                                          // Уникальный идентификатор альбома
+	ArtistID    int          `json:"artist_id" db:"artist_id"`                            // Идентификатор исполнителя
+	GroupName   string       `json:"group_name" db:"name"`                                // Каноническое имя исполнителя
+	Title       string       `json:"title" db:"title"`                                    // Название альбома
+	ReleaseDate *ReleaseDate `json:"release_date" db:"release_date" swaggertype:"string"` // Дата выпуска альбома с учетом точности
+	CoverURL    string       `json:"cover_url" db:"cover_url"`                            // Ссылка на обложку альбома
+	TrackCount  int          `json:"track_count"`                                         // Количество треков, не находящихся в корзине
+	Tracks      []AlbumTrack `json:"tracks,omitempty"`                                    // Треки по порядку, только при получении альбома по идентификатору
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`                          // Дата и время создания записи
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`                          // Дата и время последнего обновления записи
+}
+
+// AlbumTrack представляет песню на позиции в альбоме.
+type AlbumTrack struct {
+	TrackNumber int  `json:"track_number"` // Номер трека в альбоме
+	Song        Song `json:"song"`         // Песня
+}
+
+// AlbumRequest представляет структуру запроса для создания или обновления альбома.
+type AlbumRequest struct {
+	ArtistID    int    `json:"artist_id" binding:"required"`      // Идентификатор исполнителя, обязательное поле
+	Title       string `json:"title" binding:"required"`          // Название альбома, обязательное поле
+	ReleaseDate string `json:"release_date" example:"16.07.2006"` // Дата выпуска: YYYY-MM-DD, DD.MM.YYYY, YYYY-MM или YYYY, необязательное поле
+	CoverURL    string `json:"cover_url"`                         // Ссылка на обложку, необязательное поле
+}
+
+// AlbumTrackRequest представляет структуру запроса на добавление песни в альбом.
+type AlbumTrackRequest struct {
+	SongID      int `json:"song_id" binding:"required"` // Идентификатор песни, обязательное поле
+	TrackNumber int `json:"track_number"`               // Номер трека; если не указан, песня добавляется в конец альбома
+}
+
+// AlbumTrackPositionRequest представляет структуру запроса на перемещение трека внутри альбома.
+type AlbumTrackPositionRequest struct {
+	TrackNumber int `json:"track_number" binding:"required"` // Новый номер трека, обязательное поле
+}
+
+// AlbumFilter представляет структуру фильтрации альбомов.
+type AlbumFilter struct {
+	ArtistID int    `json:"artist_id"` // Идентификатор исполнителя для фильтрации (0 - любой)
+	Search   string `json:"search"`    // Подстрока названия альбома для поиска
+	Page     int    `json:"page"`      // Номер текущей страницы
+	PageSize int    `json:"page_size"` // Размер страницы (количество элементов на странице)
+}
+
+// AlbumsResponse представляет структуру ответа со списком альбомов и информацией о пагинации.
+type AlbumsResponse struct {
+	Albums     []Album `json:"albums"`      // Список альбомов без треков
+	Page       int     `json:"page"`        // Номер текущей страницы
+	TotalPages int     `json:"total_pages"` // Общее количество страниц
+	TotalItems int     `json:"total_items"` // Общее количество альбомов
+	PageSize   int     `json:"page_size"`   // Количество элементов на странице
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Artist представляет исполнителя (группу), которому принадлежат песни.
+type Artist struct {
+	ID        int           `json:"id" db:"id"`                 // Уникальный идентификатор исполнителя
+	Name      string        `json:"name" db:"name"`             // Каноническое имя исполнителя
+	Aliases   []ArtistAlias `json:"aliases"`                    // Другие написания имени исполнителя
+	SongCount int           `json:"song_count"`                 // Количество песен исполнителя, не находящихся в корзине
+	CreatedAt time.Time     `json:"created_at" db:"created_at"` // Дата и время создания записи
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"` // Дата и время последнего обновления записи
+}
+
+// ArtistAlias представляет другое написание имени исполнителя, например прежнее название группы
+// или имя на другом языке.
+type ArtistAlias struct {
+	ID        int       `json:"id" db:"id"`                 // Уникальный идентификатор псевдонима
+	Name      string    `json:"name" db:"name"`             // Псевдоним, уникальный без учета регистра
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // Дата и время создания записи
+}
+
+// ArtistRequest представляет структуру запроса для создания или переименования исполнителя.
+type ArtistRequest struct {
+	Name string `json:"name" binding:"required"` // Имя исполнителя, обязательное поле
+}
+
+// ArtistAliasRequest представляет структуру запроса на добавление псевдонима исполнителя.
+type ArtistAliasRequest struct {
+	Name string `json:"name" binding:"required"` // Псевдоним исполнителя, обязательное поле
+}
+
+// MergeArtistsRequest представляет структуру запроса на объединение исполнителей.
+type MergeArtistsRequest struct {
+	SourceID int `json:"source_id" binding:"required"` // Исполнитель, который удаляется после переноса песен
+	TargetID int `json:"target_id" binding:"required"` // Исполнитель, которому переходят песни и псевдонимы
+}
+
+// ArtistFilter представляет структуру фильтрации исполнителей.
+type ArtistFilter struct {
+	Search   string `json:"search"`    // Подстрока имени или псевдонима исполнителя для поиска
+	Page     int    `json:"page"`      // Номер текущей страницы
+	PageSize int    `json:"page_size"` // Размер страницы (количество элементов на странице)
+}
+
+// ArtistsResponse представляет структуру ответа со списком исполнителей и информацией о пагинации.
+type ArtistsResponse struct {
+	Artists    []Artist `json:"artists"`     // Список исполнителей
+	Page       int      `json:"page"`        // Номер текущей страницы
+	TotalPages int      `json:"total_pages"` // Общее количество страниц
+	TotalItems int      `json:"total_items"` // Общее количество исполнителей
+	PageSize   int      `json:"page_size"`   // Количество элементов на странице
+}
@@ -0,0 +1,109 @@
+package models
+
+import "time"
+
+// PlaylistVisibility - видимость плейлиста для других пользователей.
+type PlaylistVisibility string
+
+// Возможные значения видимости плейлиста.
+const (
+	VisibilityPrivate  PlaylistVisibility = "private"  // Только владелец
+	VisibilityUnlisted PlaylistVisibility = "unlisted" // Владелец и все, у кого есть ссылка с токеном
+	VisibilityPublic   PlaylistVisibility = "public"   // Все пользователи
+)
+
+// Playlist представляет плейлист пользователя.
+type Playlist struct {
+	ID         int                `json:"id" db:"id"`                             // Уникальный идентификатор плейлиста
+	Owner      string             `json:"owner" db:"owner"`                       // Пользователь, создавший плейлист
+	Name       string             `json:"name" db:"name"`                         // Название плейлиста
+	Visibility PlaylistVisibility `json:"visibility" db:"visibility"`             // Видимость плейлиста
+	ShareToken string             `json:"share_token,omitempty" db:"share_token"` // Токен ссылки на плейлист, доступный по ссылке; виден только владельцу
+	SongCount  int                `json:"song_count"`                             // Количество элементов плейлиста, включая песни в корзине
+	CreatedAt  time.Time          `json:"created_at" db:"created_at"`             // Дата и время создания записи
+	UpdatedAt  time.Time          `json:"updated_at" db:"updated_at"`             // Дата и время последнего обновления записи
+}
+
+// PlaylistEntry представляет элемент плейлиста. Если песня перемещена в корзину, элемент сохраняет
+// свое место, но помечается недоступным и не содержит данных песни до ее восстановления.
+type PlaylistEntry struct {
+	ID        int       `json:"id"`             // Идентификатор элемента плейлиста
+	Position  int       `json:"position"`       // Порядковый номер элемента в плейлисте, начиная с 1
+	SongID    int       `json:"song_id"`        // Идентификатор песни
+	Available bool      `json:"available"`      // Песня не находится в корзине
+	Song      *Song     `json:"song,omitempty"` // Песня, если она доступна
+	AddedAt   time.Time `json:"added_at"`       // Дата и время добавления песни в плейлист
+}
+
+// PlaylistEntries представляет страницу элементов плейлиста.
+type PlaylistEntries struct {
+	Entries    []PlaylistEntry `json:"entries"`     // Элементы плейлиста по порядку
+	Page       int             `json:"page"`        // Номер текущей страницы
+	TotalPages int             `json:"total_pages"` // Общее количество страниц
+	TotalItems int             `json:"total_items"` // Общее количество элементов
+	PageSize   int             `json:"page_size"`   // Количество элементов на странице
+}
+
+// PlaylistResponse представляет структуру ответа с плейлистом и страницей его элементов.
+type PlaylistResponse struct {
+	Playlist
+	PlaylistEntries
+}
+
+// PlaylistRequest представляет структуру запроса для создания или изменения плейлиста.
+type PlaylistRequest struct {
+	Name       string             `json:"name" binding:"required"`                    // Название плейлиста, обязательное поле
+	Visibility PlaylistVisibility `json:"visibility" enums:"private,unlisted,public"` // Видимость, по умолчанию private
+}
+
+// PlaylistEntryRequest представляет структуру запроса на добавление песни в плейлист.
+type PlaylistEntryRequest struct {
+	SongID   int `json:"song_id" binding:"required"` // Идентификатор песни, обязательное поле
+	Position int `json:"position"`                   // Порядковый номер, на который вставляется песня; если не указан, песня добавляется в конец
+}
+
+// PlaylistPositionRequest представляет структуру запроса на перемещение элемента плейлиста.
+type PlaylistPositionRequest struct {
+	Position int `json:"position" binding:"required"` // Новый порядковый номер элемента, начиная с 1
+}
+
+// PlaylistsResponse представляет структуру ответа со списком плейлистов и информацией о пагинации.
+type PlaylistsResponse struct {
+	Playlists  []Playlist `json:"playlists"`   // Список плейлистов
+	Page       int        `json:"page"`        // Номер текущей страницы
+	TotalPages int        `json:"total_pages"` // Общее количество страниц
+	TotalItems int        `json:"total_items"` // Общее количество плейлистов
+	PageSize   int        `json:"page_size"`   // Количество элементов на странице
+}
+
+// SongMatch представляет песню, сопоставленную элементу импортируемого плейлиста.
+type SongMatch struct {
+	SongID int  // Идентификатор песни
+	Exact  bool // Исполнитель и название совпали без учета регистра, иначе совпадение нечеткое
+}
+
+// PlaylistImportResponse представляет результат импорта плейлиста из файла.
+type PlaylistImportResponse struct {
+	Playlist  Playlist              `json:"playlist"`  // Созданный плейлист
+	Matched   []PlaylistImportMatch `json:"matched"`   // Треки файла, добавленные в плейлист
+	Unmatched []PlaylistImportMiss  `json:"unmatched"` // Треки файла, для которых не нашлось песни
+}
+
+// PlaylistImportMatch представляет трек файла, сопоставленный песне библиотеки.
+type PlaylistImportMatch struct {
+	Index   int    `json:"index"`    // Порядковый номер трека в файле, начиная с 1
+	Creator string `json:"creator"`  // Исполнитель из файла
+	Title   string `json:"title"`    // Название из файла
+	SongID  int    `json:"song_id"`  // Идентификатор найденной песни
+	EntryID int    `json:"entry_id"` // Идентификатор элемента плейлиста
+	Fuzzy   bool   `json:"fuzzy"`    // Песня найдена по нечеткому совпадению, результат стоит проверить
+}
+
+// PlaylistImportMiss представляет трек файла, который не удалось сопоставить песне библиотеки.
+type PlaylistImportMiss struct {
+	Index    int    `json:"index"`              // Порядковый номер трека в файле, начиная с 1
+	Creator  string `json:"creator"`            // Исполнитель из файла
+	Title    string `json:"title"`              // Название из файла
+	Location string `json:"location,omitempty"` // Ссылка из файла
+	Reason   string `json:"reason"`             // Причина: missing_metadata - в файле нет исполнителя или названия, not_found - песня не найдена
+}
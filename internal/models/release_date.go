@@ -0,0 +1,112 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DatePrecision - точность, с которой известна дата выпуска песни.
+type DatePrecision string
+
+// Возможные значения точности даты выпуска.
+const (
+	PrecisionDay   DatePrecision = "day"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionYear  DatePrecision = "year"
+)
+
+// releaseDateFormats - поддерживаемые форматы даты выпуска и соответствующая им точность.
+var releaseDateFormats = []struct {
+	layout    string
+	precision DatePrecision
+}{
+	{"2006-01-02", PrecisionDay},
+	{time.RFC3339, PrecisionDay},
+	{"02.01.2006", PrecisionDay},
+	{"2006-01", PrecisionMonth},
+	{"01.2006", PrecisionMonth},
+	{"2006", PrecisionYear},
+}
+
+// ReleaseDate представляет дату выпуска песни, которая может быть известна частично (только год или год и месяц).
+// Time содержит начало периода: первый день месяца или года.
+type ReleaseDate struct {
+	Time      time.Time     // Начало периода выпуска
+	Precision DatePrecision // Точность даты
+}
+
+// ParseReleaseDate разбирает дату выпуска в форматах ISO (2006-01-02, RFC 3339), DD.MM.YYYY,
+// а также частичные даты: YYYY-MM, MM.YYYY и YYYY.
+func ParseReleaseDate(value string) (*ReleaseDate, error) {
+	value = strings.TrimSpace(value)
+	for _, format := range releaseDateFormats {
+		parsed, err := time.Parse(format.layout, value)
+		if err != nil {
+			continue
+		}
+		return NewReleaseDate(parsed, format.precision), nil
+	}
+	return nil, fmt.Errorf("invalid release date %q, expected YYYY-MM-DD, DD.MM.YYYY, YYYY-MM or YYYY", value)
+}
+
+// NewReleaseDate создает дату выпуска, усекая время до начала периода заданной точности.
+func NewReleaseDate(t time.Time, precision DatePrecision) *ReleaseDate {
+	year, month, day := t.Date()
+	switch precision {
+	case PrecisionYear:
+		month, day = time.January, 1
+	case PrecisionMonth:
+		day = 1
+	default:
+		precision = PrecisionDay
+	}
+	return &ReleaseDate{
+		Time:      time.Date(year, month, day, 0, 0, 0, 0, time.UTC),
+		Precision: precision,
+	}
+}
+
+// End возвращает последний день периода выпуска.
+func (d ReleaseDate) End() time.Time {
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Time.AddDate(1, 0, -1)
+	case PrecisionMonth:
+		return d.Time.AddDate(0, 1, -1)
+	default:
+		return d.Time
+	}
+}
+
+// String форматирует дату с учетом ее точности: YYYY, YYYY-MM или YYYY-MM-DD.
+func (d ReleaseDate) String() string {
+	switch d.Precision {
+	case PrecisionYear:
+		return d.Time.Format("2006")
+	case PrecisionMonth:
+		return d.Time.Format("2006-01")
+	default:
+		return d.Time.Format("2006-01-02")
+	}
+}
+
+// MarshalJSON сериализует дату строкой с учетом ее точности.
+func (d ReleaseDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON разбирает дату в любом из поддерживаемых форматов.
+func (d *ReleaseDate) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := ParseReleaseDate(value)
+	if err != nil {
+		return err
+	}
+	*d = *parsed
+	return nil
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseReleaseDate(t *testing.T) {
+	tests := []struct {
+		value     string
+		want      string // String() разобранной даты
+		precision DatePrecision
+		end       string
+	}{
+		{value: "2006-07-16", want: "2006-07-16", precision: PrecisionDay, end: "2006-07-16"},
+		{value: " 16.07.2006 ", want: "2006-07-16", precision: PrecisionDay, end: "2006-07-16"},
+		// Дата берется в часовом поясе значения, без перевода в UTC
+		{value: "2006-07-16T23:30:00-05:00", want: "2006-07-16", precision: PrecisionDay, end: "2006-07-16"},
+		{value: "2024-02", want: "2024-02", precision: PrecisionMonth, end: "2024-02-29"},
+		{value: "02.2023", want: "2023-02", precision: PrecisionMonth, end: "2023-02-28"},
+		{value: "1999", want: "1999", precision: PrecisionYear, end: "1999-12-31"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseReleaseDate(tt.value)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.String() != tt.want || got.Precision != tt.precision {
+				t.Errorf("ParseReleaseDate() = %s (%s), want %s (%s)", got, got.Precision, tt.want, tt.precision)
+			}
+			if end := got.End().Format("2006-01-02"); end != tt.end {
+				t.Errorf("End() = %s, want %s", end, tt.end)
+			}
+			if got.Time.Location() != time.UTC || got.Time.Hour() != 0 {
+				t.Errorf("Time = %v, want midnight UTC", got.Time)
+			}
+		})
+	}
+}
+
+func TestParseReleaseDateInvalid(t *testing.T) {
+	for _, value := range []string{"", "yesterday", "2024-13-01", "31.02.2024", "2024-1", "20240101", "2024/01/01"} {
+		if got, err := ParseReleaseDate(value); err == nil {
+			t.Errorf("ParseReleaseDate(%q) = %s, want error", value, got)
+		}
+	}
+}
+
+func TestReleaseDateJSON(t *testing.T) {
+	var song struct {
+		ReleaseDate *ReleaseDate `json:"releaseDate"`
+	}
+	if err := json.Unmarshal([]byte(`{"releaseDate":"07.1969"}`), &song); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	data, err := json.Marshal(song)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if want := `{"releaseDate":"1969-07"}`; string(data) != want {
+		t.Errorf("marshal = %s, want %s", data, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"releaseDate":"July 1969"}`), &song); err == nil {
+		t.Error("unmarshal of an unsupported format succeeded")
+	}
+}
+
+func TestNewReleaseDateTruncates(t *testing.T) {
+	moment := time.Date(2021, time.September, 17, 15, 4, 5, 0, time.FixedZone("MSK", 3*60*60))
+	for precision, want := range map[DatePrecision]string{
+		PrecisionDay:   "2021-09-17",
+		PrecisionMonth: "2021-09-01",
+		PrecisionYear:  "2021-01-01",
+		"decade":       "2021-09-17", // неизвестная точность считается днем
+	} {
+		if got := NewReleaseDate(moment, precision).Time.Format("2006-01-02"); got != want {
+			t.Errorf("NewReleaseDate(%s) = %s, want %s", precision, got, want)
+		}
+	}
+}
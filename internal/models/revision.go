@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// SongRevision представляет сохраненный снимок состояния песни.
+type SongRevision struct {
+	SongID      int          `json:"song_id" db:"song_id"`                                // Идентификатор песни
+	Revision    int          `json:"revision" db:"revision"`                              // Порядковый номер ревизии
+	GroupName   string       `json:"group_name" db:"group_name"`                          // Название группы или исполнителя
+	SongName    string       `json:"song_name" db:"song_name"`                            // Название песни
+	ReleaseDate *ReleaseDate `json:"release_date" db:"release_date" swaggertype:"string"` // Дата выпуска песни с учетом точности
+	Text        string       `json:"text" db:"text"`                                      // Текст песни
+	Link        string       `json:"link" db:"link"`                                      // Ссылка на песню
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`                          // Дата и время создания ревизии
+}
+
+// FieldChange описывает изменение одного поля метаданных песни между ревизиями.
+type FieldChange struct {
+	Field string `json:"field"` // Название поля
+	From  string `json:"from"`  // Значение в исходной ревизии
+	To    string `json:"to"`    // Значение в целевой ревизии
+}
+
+// SongDiff представляет структуру ответа с разницей между двумя ревизиями песни.
+type SongDiff struct {
+	SongID     int           `json:"song_id"`     // Идентификатор песни
+	From       int           `json:"from"`        // Номер исходной ревизии
+	To         int           `json:"to"`          // Номер целевой ревизии
+	Fields     []FieldChange `json:"fields"`      // Изменения полей метаданных
+	LyricsDiff string        `json:"lyrics_diff"` // Построчный unified diff текста песни
+}
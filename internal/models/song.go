@@ -4,43 +4,74 @@ import "time"
 
 // Song представляет модель песни в базе данных.
 type Song struct {
-	ID          int       `json:"id" db:"id"`                     // Уникальный идентификатор песни
-	GroupName   string    `json:"group_name" db:"group_name"`     // Название группы или исполнителя
-	SongName    string    `json:"song_name" db:"song_name"`       // Название песни
-	ReleaseDate time.Time `json:"release_date" db:"release_date"` // Дата выпуска песни
-	Text        string    `json:"text" db:"text"`                 // Текст песни
-	Link        string    `json:"link" db:"link"`                 // Ссылка на песню (например, на YouTube)
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`     // Дата и время создания записи
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`     // Дата и время последнего обновления записи
+	ID          int          `json:"id" db:"id"`                                          // Уникальный идентификатор песни
+	ArtistID    int          `json:"artist_id" db:"artist_id"`                            // Идентификатор исполнителя
+	GroupName   string       `json:"group_name" db:"name"`                                // Каноническое имя исполнителя
+	SongName    string       `json:"song_name" db:"song_name"`                            // Название песни
+	ReleaseDate *ReleaseDate `json:"release_date" db:"release_date" swaggertype:"string"` // Дата выпуска песни с учетом точности
+	Text        string       `json:"text" db:"text"`                                      // Текст песни
+	Link        string       `json:"link" db:"link"`                                      // Ссылка на песню (например, на YouTube)
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`                          // Дата и время создания записи
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`                          // Дата и время последнего обновления записи
+	Version     int          `json:"version" db:"version"`                                // Версия записи для оптимистичной блокировки
+	DeletedAt   *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`                // Дата и время перемещения в корзину
 }
 
 // SongRequest представляет структуру запроса для создания или обновления песни.
 type SongRequest struct {
-	GroupName string `json:"group" binding:"required"` // Название группы, обязательное поле
-	SongName  string `json:"song" binding:"required"`  // Название песни, обязательное поле
-	Text      string `json:"text"`                     // Текст песни, необязательное поле
-	Link      string `json:"link"`                     // Ссылка на песню, необязательное поле
+	GroupName   string `json:"group" binding:"required"`          // Название группы, обязательное поле
+	SongName    string `json:"song" binding:"required"`           // Название песни, обязательное поле
+	ReleaseDate string `json:"release_date" example:"16.07.2006"` // Дата выпуска: YYYY-MM-DD, DD.MM.YYYY, YYYY-MM или YYYY, необязательное поле
+	Text        string `json:"text"`                              // Текст песни, необязательное поле
+	Link        string `json:"link"`                              // Ссылка на песню, необязательное поле
+	AlbumID     int    `json:"album_id,omitempty"`                // Альбом, в который добавляется песня, только при создании
+	TrackNumber int    `json:"track_number,omitempty"`            // Номер трека в альбоме; если не указан, песня добавляется в конец
+}
+
+// SongDocument представляет изменяемые поля песни, к которым применяются PATCH-запросы.
+type SongDocument struct {
+	GroupName   string  `json:"group"`        // Название группы
+	SongName    string  `json:"song"`         // Название песни
+	ReleaseDate *string `json:"release_date"` // Дата выпуска в любом поддерживаемом формате, null - дата не указана
+	Text        string  `json:"text"`         // Текст песни
+	Link        string  `json:"link"`         // Ссылка на песню
 }
 
 // SongFilter представляет структуру фильтрации песен.
 type SongFilter struct {
+	ArtistID  int        `json:"artist_id"`  // Идентификатор исполнителя для фильтрации (0 - любой)
+	AlbumID   int        `json:"album"`      // Идентификатор альбома для фильтрации (0 - любой)
 	GroupName string     `json:"group_name"` // Название группы для фильтрации
 	SongName  string     `json:"song_name"`  // Название песни для фильтрации
-	FromDate  *time.Time `json:"from_date"`  // Дата начала фильтрации (включительно)
-	ToDate    *time.Time `json:"to_date"`    // Дата окончания фильтрации (включительно)
+	FromDate  *time.Time `json:"from_date"`  // Дата начала фильтрации (включительно), песни с частичной датой попадают при пересечении периодов
+	ToDate    *time.Time `json:"to_date"`    // Дата окончания фильтрации (включительно), песни с частичной датой попадают при пересечении периодов
 	Text      string     `json:"text"`       // Текст песни для фильтрации
 	Link      string     `json:"link"`       // Ссылка на песню для фильтрации
 	Page      int        `json:"page"`       // Номер текущей страницы
 	PageSize  int        `json:"page_size"`  // Размер страницы (количество элементов на странице)
+	Count     CountMode  `json:"count"`      // Способ подсчета общего количества песен, по умолчанию точный
 }
 
+// CountMode - способ подсчета общего количества элементов списка.
+type CountMode string
+
+// Поддерживаемые способы подсчета.
+const (
+	CountExact    CountMode = "exact"    // Точный подсчет COUNT(*)
+	CountEstimate CountMode = "estimate" // Оценка по статистике планировщика
+	CountNone     CountMode = "none"     // Без подсчета, только признак следующей страницы
+)
+
 // SongsResponse представляет структуру ответа со списком песен и информацией о пагинации.
+// При подсчете count=none общее количество песен и страниц не возвращается.
 type SongsResponse struct {
-	Songs      []Song `json:"songs"`       // Список песен
-	Page       int    `json:"page"`        // Номер текущей страницы
-	TotalPages int    `json:"total_pages"` // Общее количество страниц
-	TotalItems int    `json:"total_items"` // Общее количество песен
-	PageSize   int    `json:"page_size"`   // Количество элементов на странице
+	Songs           []Song `json:"songs"`                 // Список песен
+	Page            int    `json:"page"`                  // Номер текущей страницы
+	TotalPages      *int   `json:"total_pages,omitempty"` // Общее количество страниц
+	TotalItems      *int   `json:"total_items,omitempty"` // Общее количество песен
+	TotalItemsExact bool   `json:"total_items_exact"`     // Общее количество песен подсчитано точно, а не оценено
+	HasNext         bool   `json:"has_next"`              // Существует следующая страница
+	PageSize        int    `json:"page_size"`             // Количество элементов на странице
 }
 
 // LyricsResponse представляет структуру ответа с текстом куплетов и информацией о пагинации.
@@ -49,4 +80,7 @@ type LyricsResponse struct {
 	CurrentPage int    `json:"current_page"` // Номер текущей страницы
 	TotalPages  int    `json:"total_pages"`  // Общее количество страниц
 	PageSize    int    `json:"page_size"`    // Количество элементов на странице
+
+	Version   int       `json:"-"` // Версия песни, используется для ETag
+	UpdatedAt time.Time `json:"-"` // Дата последнего изменения песни, используется для Last-Modified
 }
@@ -0,0 +1,262 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	// Большая часть случаев - примеры из приложения A RFC 6902
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "add object member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "add array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			want:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			name:  "append to array",
+			doc:   `{"foo":["bar"]}`,
+			patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			want:  `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			name:  "add nested member object",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			want:  `{"foo":"bar","child":{"grandchild":{}}}`,
+		},
+		{
+			name:  "ignore unrecognized members",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux","xyz":123}]`,
+			want:  `{"foo":"bar","baz":"qux"}`,
+		},
+		{
+			name:    "add to nonexistent target",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:  "remove object member",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"remove","path":"/baz"}]`,
+			want:  `{"foo":"bar"}`,
+		},
+		{
+			name:  "remove array element",
+			doc:   `{"foo":["bar","qux","baz"]}`,
+			patch: `[{"op":"remove","path":"/foo/1"}]`,
+			want:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			name:    "remove missing member",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"remove","path":"/baz"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:  "replace value",
+			doc:   `{"baz":"qux","foo":"bar"}`,
+			patch: `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			want:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			name:  "replace document root",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"replace","path":"","value":{"baz":"qux"}}]`,
+			want:  `{"baz":"qux"}`,
+		},
+		{
+			name:    "replace missing member",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:  "move value",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "move array element",
+			doc:   `{"foo":["all","grass","cows","eat"]}`,
+			patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			want:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			name:    "move into own child",
+			doc:     `{"foo":{"bar":{}}}`,
+			patch:   `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:    "move from missing location",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"move","from":"/baz","path":"/qux"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:  "copy is independent of its source",
+			doc:   `{"foo":{"bar":1}}`,
+			patch: `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			want:  `{"foo":{"bar":1},"baz":{"bar":2}}`,
+		},
+		{
+			name:  "copy array element",
+			doc:   `{"foo":["a","b"]}`,
+			patch: `[{"op":"copy","from":"/foo/0","path":"/foo/-"}]`,
+			want:  `{"foo":["a","b","a"]}`,
+		},
+		{
+			name:  "test succeeds",
+			doc:   `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2}]`,
+			want:  `{"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			name:    "test fails",
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"test","path":"/baz","value":"bar"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:    "test compares types",
+			doc:     `{"/":9,"~1":10}`,
+			patch:   `[{"op":"test","path":"/~01","value":"10"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:  "pointer escapes ~0 and ~1",
+			doc:   `{"/":9,"~1":10}`,
+			patch: `[{"op":"test","path":"/~01","value":10},{"op":"test","path":"/~1","value":9}]`,
+			want:  `{"/":9,"~1":10}`,
+		},
+		{
+			name:  "add members with escaped names",
+			doc:   `{}`,
+			patch: `[{"op":"add","path":"/a~1b","value":1},{"op":"add","path":"/m~0n","value":2}]`,
+			want:  `{"a/b":1,"m~n":2}`,
+		},
+		{
+			name:    "failed operation discards earlier ones",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"remove","path":"/foo"},{"op":"test","path":"/foo","value":"bar"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:    "array index with leading zero",
+			doc:     `{"foo":["a","b"]}`,
+			patch:   `[{"op":"remove","path":"/foo/01"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:    "array index out of range",
+			doc:     `{"foo":["a","b"]}`,
+			patch:   `[{"op":"add","path":"/foo/3","value":"c"}]`,
+			wantErr: ErrPatchFailed,
+		},
+		{
+			name:    "pointer without leading slash",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"remove","path":"foo"}]`,
+			wantErr: ErrMalformedPatch,
+		},
+		{
+			name:    "missing value",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz"}]`,
+			wantErr: ErrMalformedPatch,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"merge","path":"/foo","value":"baz"}]`,
+			wantErr: ErrMalformedPatch,
+		},
+		{
+			name:    "patch is not an array",
+			doc:     `{"foo":"bar"}`,
+			patch:   `{"op":"remove","path":"/foo"}`,
+			wantErr: ErrMalformedPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ApplyJSONPatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if got != nil {
+					t.Errorf("document = %s, want nil on error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestParsePointer(t *testing.T) {
+	tests := []struct {
+		pointer string
+		want    []string
+	}{
+		{pointer: "", want: nil},
+		{pointer: "/", want: []string{""}},
+		{pointer: "/foo/0", want: []string{"foo", "0"}},
+		{pointer: "/a~1b", want: []string{"a/b"}},
+		{pointer: "/m~0n", want: []string{"m~n"}},
+		// ~01 - это экранированная тильда и символ 1, а не косая черта
+		{pointer: "/~01", want: []string{"~1"}},
+		{pointer: "/~10", want: []string{"/0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			got, err := parsePointer(tt.pointer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokens = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// assertJSONEqual сравнивает JSON-документы без учета порядка полей.
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("document = %s, want %s", got, want)
+	}
+}
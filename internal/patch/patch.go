@@ -0,0 +1,72 @@
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Типы содержимого, поддерживаемые PATCH-запросами.
+const (
+	MergePatchType = "application/merge-patch+json" // RFC 7396
+	JSONPatchType  = "application/json-patch+json"  // RFC 6902
+)
+
+var (
+	// ErrMalformedPatch возвращается, если документ изменений не является корректным JSON нужной структуры.
+	ErrMalformedPatch = errors.New("malformed patch document")
+	// ErrPatchFailed возвращается, если изменения не могут быть применены к документу.
+	ErrPatchFailed = errors.New("patch cannot be applied")
+)
+
+// decode разбирает JSON-документ, сохраняя числа без потери точности.
+func decode(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+// MergePatch применяет JSON Merge Patch (RFC 7396) к документу doc.
+// Значение null в patch удаляет соответствующее поле.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target document: %w", err)
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedPatch, err)
+	}
+
+	return json.Marshal(mergeValue(target, p))
+}
+
+// mergeValue рекурсивно применяет merge patch к значению.
+func mergeValue(target, patch interface{}) interface{} {
+	patchObj, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]interface{})
+	if !ok {
+		targetObj = map[string]interface{}{}
+	}
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+	return targetObj
+}
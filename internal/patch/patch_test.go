@@ -0,0 +1,69 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+	// Примеры из приложения A RFC 7396
+	tests := []struct {
+		doc   string
+		patch string
+		want  string
+	}{
+		{doc: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{doc: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{doc: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{doc: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{doc: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{doc: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{doc: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{doc: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{doc: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{doc: `{"a":"foo"}`, patch: `null`, want: `null`},
+		{doc: `{"a":"foo"}`, patch: `"bar"`, want: `"bar"`},
+		{doc: `{"e":null}`, patch: `{"a":1}`, want: `{"e":null,"a":1}`},
+		{doc: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{doc: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.doc+" + "+tt.patch, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergePatchKeepsNumberPrecision(t *testing.T) {
+	got, err := MergePatch([]byte(`{"id":9007199254740993}`), []byte(`{"a":1}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"a":1,"id":9007199254740993}`; string(got) != want {
+		t.Errorf("document = %s, want %s", got, want)
+	}
+}
+
+func TestMergePatchMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{name: "invalid JSON", patch: `{"a":`},
+		{name: "trailing data", patch: `{"a":1} {"b":2}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MergePatch([]byte(`{}`), []byte(tt.patch)); !errors.Is(err, ErrMalformedPatch) {
+				t.Errorf("error = %v, want %v", err, ErrMalformedPatch)
+			}
+		})
+	}
+}
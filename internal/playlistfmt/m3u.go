@@ -0,0 +1,102 @@
+package playlistfmt
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+)
+
+// Директивы расширенного M3U.
+const (
+	m3uHeader   = "#EXTM3U"
+	m3uPlaylist = "#PLAYLIST:"
+	m3uInfo     = "#EXTINF:"
+)
+
+// m3uSeparator разделяет исполнителя и название в отображаемом имени трека директивы #EXTINF.
+const m3uSeparator = " - "
+
+// encodeM3U кодирует плейлист в расширенный M3U. Трек без ссылки в M3U не представим и пропускается.
+func encodeM3U(playlist *Playlist) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(m3uHeader + "\n")
+	if playlist.Title != "" {
+		buf.WriteString(m3uPlaylist + m3uLine(playlist.Title) + "\n")
+	}
+	for _, track := range playlist.Tracks {
+		if track.Location == "" {
+			continue
+		}
+		display := track.Title
+		if track.Creator != "" {
+			display = track.Creator + m3uSeparator + track.Title
+		}
+		// Длительность неизвестна, что в M3U обозначается значением -1
+		buf.WriteString(m3uInfo + "-1," + m3uLine(display) + "\n")
+		buf.WriteString(m3uLine(track.Location) + "\n")
+	}
+	return buf.Bytes()
+}
+
+// decodeM3U разбирает M3U. Исполнитель и название берутся из директивы #EXTINF в виде "Исполнитель - Название";
+// строки без директивы дают трек только со ссылкой.
+func decodeM3U(data []byte) (*Playlist, error) {
+	playlist := &Playlist{}
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	var (
+		pending Track
+		hasInfo bool
+	)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || line == m3uHeader:
+		case strings.HasPrefix(line, m3uPlaylist):
+			playlist.Title = strings.TrimSpace(strings.TrimPrefix(line, m3uPlaylist))
+		case strings.HasPrefix(line, m3uInfo):
+			if hasInfo {
+				playlist.Tracks = append(playlist.Tracks, pending)
+			}
+			pending, hasInfo = parseM3UInfo(strings.TrimPrefix(line, m3uInfo)), true
+		case strings.HasPrefix(line, "#"):
+			// Прочие директивы и комментарии не влияют на состав плейлиста
+		default:
+			pending.Location = line
+			playlist.Tracks = append(playlist.Tracks, pending)
+			pending, hasInfo = Track{}, false
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if hasInfo {
+		playlist.Tracks = append(playlist.Tracks, pending)
+	}
+	return playlist, nil
+}
+
+// parseM3UInfo разбирает значение директивы #EXTINF: длительность и атрибуты до первой запятой вне кавычек,
+// затем отображаемое имя трека.
+func parseM3UInfo(value string) Track {
+	quoted := false
+	for i, r := range value {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			display := strings.TrimSpace(value[i+1:])
+			if creator, title, ok := strings.Cut(display, m3uSeparator); ok {
+				return Track{Creator: strings.TrimSpace(creator), Title: strings.TrimSpace(title)}
+			}
+			return Track{Title: display}
+		}
+	}
+	return Track{}
+}
+
+// m3uLine заменяет переводы строк пробелами, так как M3U построчный формат.
+func m3uLine(value string) string {
+	return strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(value)
+}
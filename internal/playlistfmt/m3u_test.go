@@ -0,0 +1,133 @@
+package playlistfmt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecodeM3U(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want *Playlist
+	}{
+		{
+			name: "extended playlist",
+			data: "#EXTM3U\n#PLAYLIST:Road trip\n#EXTINF:123,Muse - Uprising\nhttps://example.com/1\n#EXTINF:-1,Queen - Bohemian Rhapsody\nhttps://example.com/2\n",
+			want: &Playlist{Title: "Road trip", Tracks: []Track{
+				{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"},
+				{Creator: "Queen", Title: "Bohemian Rhapsody", Location: "https://example.com/2"},
+			}},
+		},
+		{
+			name: "quoted commas in attributes",
+			data: "#EXTM3U\n#EXTINF:-1 tvg-name=\"Doe, John\" group-title=\"Rock, Alt\",Muse - Uprising\nhttps://example.com/1\n",
+			want: &Playlist{Tracks: []Track{{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"}}},
+		},
+		{
+			name: "commas in display name",
+			data: "#EXTINF:-1,Crosby, Stills & Nash - Suite: Judy Blue Eyes, Live\nhttps://example.com/1\n",
+			want: &Playlist{Tracks: []Track{{Creator: "Crosby, Stills & Nash", Title: "Suite: Judy Blue Eyes, Live", Location: "https://example.com/1"}}},
+		},
+		{
+			name: "display name without creator",
+			data: "#EXTINF:-1,Uprising\nhttps://example.com/1\n",
+			want: &Playlist{Tracks: []Track{{Title: "Uprising", Location: "https://example.com/1"}}},
+		},
+		{
+			name: "plain M3U with comments, BOM and CRLF",
+			data: "\ufeff# my songs\r\nhttps://example.com/1\r\n\r\n/music/2.mp3\r\n",
+			want: &Playlist{Tracks: []Track{{Location: "https://example.com/1"}, {Location: "/music/2.mp3"}}},
+		},
+		{
+			name: "unknown directives are ignored",
+			data: "#EXTM3U\n#EXTINF:-1,Muse - Uprising\n#EXTGRP:Rock\nhttps://example.com/1\n",
+			want: &Playlist{Tracks: []Track{{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"}}},
+		},
+		{
+			name: "info without location",
+			data: "#EXTINF:-1,Muse - Uprising\n#EXTINF:-1,Queen - Innuendo\nhttps://example.com/2\n#EXTINF:-1,Blur - Song 2\n",
+			want: &Playlist{Tracks: []Track{
+				{Creator: "Muse", Title: "Uprising"},
+				{Creator: "Queen", Title: "Innuendo", Location: "https://example.com/2"},
+				{Creator: "Blur", Title: "Song 2"},
+			}},
+		},
+		{
+			name: "info without comma",
+			data: "#EXTINF:-1 tvg-name=\"a,b\"\nhttps://example.com/1\n",
+			want: &Playlist{Tracks: []Track{{Location: "https://example.com/1"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(M3U8, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("playlist = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeM3U(t *testing.T) {
+	tests := []struct {
+		name     string
+		playlist *Playlist
+		want     string
+	}{
+		{
+			name: "tracks with and without creator",
+			playlist: &Playlist{Title: "Mix", Tracks: []Track{
+				{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"},
+				{Title: "Untitled", Location: "https://example.com/2"},
+			}},
+			want: "#EXTM3U\n#PLAYLIST:Mix\n#EXTINF:-1,Muse - Uprising\nhttps://example.com/1\n#EXTINF:-1,Untitled\nhttps://example.com/2\n",
+		},
+		{
+			name:     "track without location is skipped",
+			playlist: &Playlist{Tracks: []Track{{Creator: "Muse", Title: "Uprising"}}},
+			want:     "#EXTM3U\n",
+		},
+		{
+			name:     "line breaks are replaced",
+			playlist: &Playlist{Title: "Two\nlines", Tracks: []Track{{Title: "A\r\nB", Location: "https://example.com/1"}}},
+			want:     "#EXTM3U\n#PLAYLIST:Two lines\n#EXTINF:-1,A B\nhttps://example.com/1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Encode(M3U8, tt.playlist)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("encoded = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestM3URoundTrip(t *testing.T) {
+	playlist := &Playlist{Title: "Road trip", Tracks: []Track{
+		{Creator: "Crosby, Stills & Nash", Title: "Suite: Judy Blue Eyes", Location: "https://example.com/1"},
+		{Title: "Song \"quoted\", with comma", Location: "https://example.com/2?a=1&b=2"},
+		{Creator: "Мумий Тролль", Title: "Утекай", Location: "/music/утекай.mp3"},
+	}}
+
+	data, err := Encode(M3U8, playlist)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	got, err := Decode(M3U8, data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(got, playlist) {
+		t.Errorf("round trip = %+v, want %+v", got, playlist)
+	}
+}
@@ -0,0 +1,117 @@
+// Package playlistfmt кодирует и разбирает файлы плейлистов в форматах M3U8, XSPF и JSPF.
+package playlistfmt
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// Поддерживаемые форматы файлов плейлистов.
+const (
+	M3U8 = "m3u8" // Расширенный M3U в кодировке UTF-8
+	XSPF = "xspf" // XML Shareable Playlist Format
+	JSPF = "jspf" // JSON-представление XSPF
+)
+
+var (
+	// ErrUnsupportedFormat возвращается для неизвестного формата или типа содержимого.
+	ErrUnsupportedFormat = errors.New("unsupported playlist format")
+	// ErrMalformed возвращается, если файл не соответствует формату.
+	ErrMalformed = errors.New("malformed playlist")
+)
+
+// Track - трек плейлиста. Поля, которых нет в файле, остаются пустыми.
+type Track struct {
+	Creator  string // Исполнитель
+	Title    string // Название трека
+	Location string // Ссылка на трек
+}
+
+// Playlist - содержимое файла плейлиста.
+type Playlist struct {
+	Title  string  // Название плейлиста
+	Tracks []Track // Треки по порядку
+}
+
+// contentTypes - тип содержимого ответа для каждого формата.
+var contentTypes = map[string]string{
+	M3U8: "audio/x-mpegurl; charset=utf-8",
+	XSPF: "application/xspf+xml",
+	JSPF: "application/jspf+json",
+}
+
+// mediaTypes - типы содержимого, по которым определяется формат загружаемого файла.
+var mediaTypes = map[string]string{
+	"audio/x-mpegurl":               M3U8,
+	"audio/mpegurl":                 M3U8,
+	"application/x-mpegurl":         M3U8,
+	"application/vnd.apple.mpegurl": M3U8,
+	"application/xspf+xml":          XSPF,
+	"application/jspf+json":         JSPF,
+	"application/json":              JSPF,
+}
+
+// Formats возвращает поддерживаемые форматы.
+func Formats() []string {
+	return []string{M3U8, XSPF, JSPF}
+}
+
+// ContentType возвращает тип содержимого файла в формате format.
+func ContentType(format string) (string, error) {
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	return contentType, nil
+}
+
+// FormatFromContentType определяет формат файла по заголовку Content-Type.
+func FormatFromContentType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	format, ok := mediaTypes[strings.ToLower(mediaType)]
+	if !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnsupportedFormat, mediaType)
+	}
+	return format, nil
+}
+
+// Encode кодирует плейлист в формате format.
+func Encode(format string, playlist *Playlist) ([]byte, error) {
+	switch format {
+	case M3U8:
+		return encodeM3U(playlist), nil
+	case XSPF:
+		return encodeXSPF(playlist)
+	case JSPF:
+		return encodeJSPF(playlist)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// Decode разбирает файл плейлиста в формате format.
+func Decode(format string, data []byte) (*Playlist, error) {
+	var (
+		playlist *Playlist
+		err      error
+	)
+	switch format {
+	case M3U8:
+		playlist, err = decodeM3U(data)
+	case XSPF:
+		playlist, err = decodeXSPF(data)
+	case JSPF:
+		playlist, err = decodeJSPF(data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformed, err)
+	}
+	return playlist, nil
+}
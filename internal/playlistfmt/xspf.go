@@ -0,0 +1,126 @@
+package playlistfmt
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+)
+
+// xspfNamespace - пространство имен XSPF версии 1.
+const xspfNamespace = "http://xspf.org/ns/0/"
+
+// xspfPlaylist - корневой элемент XSPF. Пространство имен при разборе не проверяется,
+// чтобы принимать файлы, в которых оно не указано.
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"playlist"`
+	Xmlns   string      `xml:"xmlns,attr"`
+	Version string      `xml:"version,attr"`
+	Title   string      `xml:"title,omitempty"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+// xspfTrack - элемент track XSPF. Элементов location может быть несколько, используется первый.
+type xspfTrack struct {
+	Locations []string `xml:"location"`
+	Title     string   `xml:"title,omitempty"`
+	Creator   string   `xml:"creator,omitempty"`
+}
+
+// jspfDocument - корневой объект JSPF.
+type jspfDocument struct {
+	Playlist jspfPlaylist `json:"playlist"`
+}
+
+// jspfPlaylist - плейлист JSPF.
+type jspfPlaylist struct {
+	Title  string      `json:"title,omitempty"`
+	Tracks []jspfTrack `json:"track"`
+}
+
+// jspfTrack - трек JSPF. По спецификации location - массив ссылок.
+type jspfTrack struct {
+	Locations []string `json:"location,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	Creator   string   `json:"creator,omitempty"`
+}
+
+// encodeXSPF кодирует плейлист в XSPF.
+func encodeXSPF(playlist *Playlist) ([]byte, error) {
+	doc := xspfPlaylist{Xmlns: xspfNamespace, Version: "1", Title: playlist.Title, Tracks: []xspfTrack{}}
+	for _, track := range playlist.Tracks {
+		doc.Tracks = append(doc.Tracks, xspfTrack{
+			Locations: locations(track.Location),
+			Title:     track.Title,
+			Creator:   track.Creator,
+		})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+	buf.WriteString("\n")
+	return buf.Bytes(), nil
+}
+
+// decodeXSPF разбирает XSPF.
+func decodeXSPF(data []byte) (*Playlist, error) {
+	var doc xspfPlaylist
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	playlist := &Playlist{Title: strings.TrimSpace(doc.Title)}
+	for _, track := range doc.Tracks {
+		playlist.Tracks = append(playlist.Tracks, newTrack(track.Creator, track.Title, track.Locations))
+	}
+	return playlist, nil
+}
+
+// encodeJSPF кодирует плейлист в JSPF.
+func encodeJSPF(playlist *Playlist) ([]byte, error) {
+	doc := jspfDocument{Playlist: jspfPlaylist{Title: playlist.Title, Tracks: []jspfTrack{}}}
+	for _, track := range playlist.Tracks {
+		doc.Playlist.Tracks = append(doc.Playlist.Tracks, jspfTrack{
+			Locations: locations(track.Location),
+			Title:     track.Title,
+			Creator:   track.Creator,
+		})
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// decodeJSPF разбирает JSPF.
+func decodeJSPF(data []byte) (*Playlist, error) {
+	var doc jspfDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	playlist := &Playlist{Title: strings.TrimSpace(doc.Playlist.Title)}
+	for _, track := range doc.Playlist.Tracks {
+		playlist.Tracks = append(playlist.Tracks, newTrack(track.Creator, track.Title, track.Locations))
+	}
+	return playlist, nil
+}
+
+// newTrack создает трек из полей XSPF или JSPF, используя первую ссылку.
+func newTrack(creator, title string, locations []string) Track {
+	track := Track{Creator: strings.TrimSpace(creator), Title: strings.TrimSpace(title)}
+	if len(locations) > 0 {
+		track.Location = strings.TrimSpace(locations[0])
+	}
+	return track
+}
+
+// locations возвращает список ссылок трека: пустой, если ссылки нет.
+func locations(location string) []string {
+	if location == "" {
+		return nil
+	}
+	return []string{location}
+}
@@ -0,0 +1,120 @@
+package playlistfmt
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestXSPFAndJSPFRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		playlist *Playlist
+	}{
+		{
+			name: "full tracks",
+			playlist: &Playlist{Title: "Road trip", Tracks: []Track{
+				{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"},
+				{Creator: "Queen", Title: "Bohemian Rhapsody", Location: "https://example.com/2"},
+			}},
+		},
+		{
+			name: "special characters",
+			playlist: &Playlist{Title: `Rock & "Roll" <live>`, Tracks: []Track{
+				{Creator: "AC/DC", Title: "It's a Long Way", Location: "https://example.com/songs?id=1&lang=en"},
+				{Creator: "Мумий Тролль", Title: "Утекай", Location: "https://example.com/утекай"},
+			}},
+		},
+		{
+			name: "track without location or creator",
+			playlist: &Playlist{Tracks: []Track{
+				{Title: "Uprising"},
+				{Location: "https://example.com/2"},
+			}},
+		},
+		{
+			name:     "empty playlist",
+			playlist: &Playlist{Title: "Empty"},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, format := range []string{XSPF, JSPF} {
+			t.Run(format+"/"+tt.name, func(t *testing.T) {
+				data, err := Encode(format, tt.playlist)
+				if err != nil {
+					t.Fatalf("encode: %v", err)
+				}
+				got, err := Decode(format, data)
+				if err != nil {
+					t.Fatalf("decode: %v\n%s", err, data)
+				}
+				if !reflect.DeepEqual(got, tt.playlist) {
+					t.Errorf("round trip = %+v, want %+v\n%s", got, tt.playlist, data)
+				}
+			})
+		}
+	}
+}
+
+func TestDecodeXSPFAndJSPF(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		data    string
+		want    *Playlist
+		wantErr bool
+	}{
+		{
+			name:   "XSPF with several locations and extra elements",
+			format: XSPF,
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<playlist version="1" xmlns="http://xspf.org/ns/0/">
+  <title> Mix </title>
+  <trackList>
+    <track>
+      <location>https://example.com/1</location>
+      <location>https://mirror.example.com/1</location>
+      <title>Uprising</title>
+      <creator>Muse</creator>
+      <duration>305000</duration>
+    </track>
+  </trackList>
+</playlist>`,
+			want: &Playlist{Title: "Mix", Tracks: []Track{{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"}}},
+		},
+		{
+			name:   "XSPF without namespace",
+			format: XSPF,
+			data:   `<playlist version="1"><trackList><track><title>Uprising</title></track></trackList></playlist>`,
+			want:   &Playlist{Tracks: []Track{{Title: "Uprising"}}},
+		},
+		{
+			name:   "JSPF with several locations and extra members",
+			format: JSPF,
+			data:   `{"playlist":{"title":"Mix","creator":"me","track":[{"location":["https://example.com/1","https://mirror.example.com/1"],"title":"Uprising","creator":"Muse","duration":305000}]}}`,
+			want:   &Playlist{Title: "Mix", Tracks: []Track{{Creator: "Muse", Title: "Uprising", Location: "https://example.com/1"}}},
+		},
+		{name: "malformed XSPF", format: XSPF, data: `<playlist><trackList>`, wantErr: true},
+		{name: "malformed JSPF", format: JSPF, data: `{"playlist":`, wantErr: true},
+		{name: "JSPF location is not an array", format: JSPF, data: `{"playlist":{"track":[{"location":"https://example.com/1"}]}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.format, []byte(tt.data))
+			if tt.wantErr {
+				if !errors.Is(err, ErrMalformed) {
+					t.Fatalf("error = %v, want %v", err, ErrMalformed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("playlist = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/models"
+)
+
+// AlbumRepository методы для взаимодействия с данными альбомов и их треков в базе данных
+type AlbumRepository interface {
+	GetAlbums(ctx context.Context, filter *models.AlbumFilter) (*models.AlbumsResponse, error)
+	GetAlbumByID(ctx context.Context, id int) (*models.Album, error)
+	CreateAlbum(ctx context.Context, album *models.Album) (*models.Album, error)
+	UpdateAlbum(ctx context.Context, album *models.Album) (*models.Album, error)
+	DeleteAlbum(ctx context.Context, id int) error
+	AddAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error)
+	MoveAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error)
+	RemoveAlbumTrack(ctx context.Context, albumID, songID int) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/models"
+)
+
+// ArtistRepository методы для взаимодействия с данными исполнителей в базе данных
+type ArtistRepository interface {
+	GetArtists(ctx context.Context, filter *models.ArtistFilter) (*models.ArtistsResponse, error)
+	GetArtistByID(ctx context.Context, id int) (*models.Artist, error)
+	CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error)
+	UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error)
+	DeleteArtist(ctx context.Context, id int) error
+	AddArtistAlias(ctx context.Context, artistID int, name string) (*models.Artist, error)
+	DeleteArtistAlias(ctx context.Context, artistID, aliasID int) error
+	MergeArtists(ctx context.Context, sourceID, targetID int) (*models.Artist, error)
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// AlbumRepository - декоратор репозитория альбомов, инвалидирующий страницы списка песен при изменении
+// состава альбомов, так как список песен можно отфильтровать по альбому. Альбомы не кэшируются.
+type AlbumRepository struct {
+	next  repository.AlbumRepository
+	songs *SongRepository
+}
+
+var _ repository.AlbumRepository = (*AlbumRepository)(nil)
+
+// NewAlbumRepository создает декоратор над репозиторием next, инвалидирующий кэш songs.
+func NewAlbumRepository(next repository.AlbumRepository, songs *SongRepository) *AlbumRepository {
+	return &AlbumRepository{next: next, songs: songs}
+}
+
+// GetAlbums получает список альбомов без кэширования.
+func (r *AlbumRepository) GetAlbums(ctx context.Context, filter *models.AlbumFilter) (*models.AlbumsResponse, error) {
+	return r.next.GetAlbums(ctx, filter)
+}
+
+// GetAlbumByID получает альбом без кэширования.
+func (r *AlbumRepository) GetAlbumByID(ctx context.Context, id int) (*models.Album, error) {
+	return r.next.GetAlbumByID(ctx, id)
+}
+
+// CreateAlbum создает альбом. Треков у нового альбома нет, поэтому кэш не инвалидируется.
+func (r *AlbumRepository) CreateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	return r.next.CreateAlbum(ctx, album)
+}
+
+// UpdateAlbum обновляет альбом. Представление песен от альбома не зависит, поэтому кэш не инвалидируется.
+func (r *AlbumRepository) UpdateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	return r.next.UpdateAlbum(ctx, album)
+}
+
+// DeleteAlbum удаляет альбом вместе с треками и инвалидирует страницы списка, отфильтрованные по альбому.
+func (r *AlbumRepository) DeleteAlbum(ctx context.Context, id int) error {
+	err := r.next.DeleteAlbum(ctx, id)
+	r.songs.lists.clear()
+	return err
+}
+
+// AddAlbumTrack добавляет песню в альбом и инвалидирует страницы списка.
+func (r *AlbumRepository) AddAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	album, err := r.next.AddAlbumTrack(ctx, albumID, songID, trackNumber)
+	r.songs.lists.clear()
+	return album, err
+}
+
+// MoveAlbumTrack переносит трек альбома. Состав альбома не меняется, поэтому кэш не инвалидируется.
+func (r *AlbumRepository) MoveAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	return r.next.MoveAlbumTrack(ctx, albumID, songID, trackNumber)
+}
+
+// RemoveAlbumTrack удаляет песню из альбома и инвалидирует страницы списка.
+func (r *AlbumRepository) RemoveAlbumTrack(ctx context.Context, albumID, songID int) error {
+	err := r.next.RemoveAlbumTrack(ctx, albumID, songID)
+	r.songs.lists.clear()
+	return err
+}
@@ -0,0 +1,73 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// ArtistRepository - декоратор репозитория исполнителей, инвалидирующий кэш песен при изменениях,
+// которые меняют представление песен или результаты фильтра списка. Исполнители не кэшируются.
+type ArtistRepository struct {
+	next  repository.ArtistRepository
+	songs *SongRepository
+}
+
+var _ repository.ArtistRepository = (*ArtistRepository)(nil)
+
+// NewArtistRepository создает декоратор над репозиторием next, инвалидирующий кэш songs.
+func NewArtistRepository(next repository.ArtistRepository, songs *SongRepository) *ArtistRepository {
+	return &ArtistRepository{next: next, songs: songs}
+}
+
+// GetArtists получает список исполнителей без кэширования.
+func (r *ArtistRepository) GetArtists(ctx context.Context, filter *models.ArtistFilter) (*models.ArtistsResponse, error) {
+	return r.next.GetArtists(ctx, filter)
+}
+
+// GetArtistByID получает исполнителя без кэширования.
+func (r *ArtistRepository) GetArtistByID(ctx context.Context, id int) (*models.Artist, error) {
+	return r.next.GetArtistByID(ctx, id)
+}
+
+// CreateArtist создает исполнителя. Песни при этом не меняются, поэтому кэш не инвалидируется.
+func (r *ArtistRepository) CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	return r.next.CreateArtist(ctx, artist)
+}
+
+// UpdateArtist переименовывает исполнителя и инвалидирует весь кэш, так как имя группы
+// входит в представление всех его песен.
+func (r *ArtistRepository) UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	updated, err := r.next.UpdateArtist(ctx, artist)
+	r.songs.invalidateAll()
+	return updated, err
+}
+
+// DeleteArtist удаляет исполнителя. Удалить можно только исполнителя без песен, поэтому кэш не инвалидируется.
+func (r *ArtistRepository) DeleteArtist(ctx context.Context, id int) error {
+	return r.next.DeleteArtist(ctx, id)
+}
+
+// AddArtistAlias добавляет псевдоним исполнителю. Представление песен не меняется, но по псевдониму
+// фильтр списка начинает находить песни исполнителя, поэтому инвалидируются страницы списка.
+func (r *ArtistRepository) AddArtistAlias(ctx context.Context, artistID int, name string) (*models.Artist, error) {
+	artist, err := r.next.AddArtistAlias(ctx, artistID, name)
+	r.songs.lists.clear()
+	return artist, err
+}
+
+// DeleteArtistAlias удаляет псевдоним исполнителя и инвалидирует страницы списка.
+func (r *ArtistRepository) DeleteArtistAlias(ctx context.Context, artistID, aliasID int) error {
+	err := r.next.DeleteArtistAlias(ctx, artistID, aliasID)
+	r.songs.lists.clear()
+	return err
+}
+
+// MergeArtists объединяет исполнителей и инвалидирует весь кэш, так как у песен исходного
+// исполнителя меняется имя группы.
+func (r *ArtistRepository) MergeArtists(ctx context.Context, sourceID, targetID int) (*models.Artist, error) {
+	merged, err := r.next.MergeArtists(ctx, sourceID, targetID)
+	r.songs.invalidateAll()
+	return merged, err
+}
@@ -0,0 +1,124 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry - элемент кэша со временем истечения.
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+}
+
+// lru - потокобезопасный LRU-кэш с ограничением количества элементов и временем жизни элементов.
+type lru[K comparable, V any] struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	items    map[K]*list.Element
+
+	// generation увеличивается при каждой инвалидации. Значение, загруженное до инвалидации,
+	// не сохраняется в кэш, чтобы конкурентное чтение не вернуло в кэш устаревшие данные.
+	generation uint64
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+func newLRU[K comparable, V any](capacity int, ttl time.Duration) *lru[K, V] {
+	return &lru[K, V]{
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		items:    make(map[K]*list.Element, capacity),
+	}
+}
+
+// get возвращает значение по ключу и текущее поколение кэша для последующего сохранения загруженного значения.
+func (c *lru[K, V]) get(key K) (V, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		if time.Now().Before(e.expiresAt) {
+			c.order.MoveToFront(elem)
+			c.hits++
+			return e.value, c.generation, true
+		}
+		c.removeElement(elem)
+	}
+
+	c.misses++
+	var zero V
+	return zero, c.generation, false
+}
+
+// add сохраняет значение, если с момента чтения generation кэш не инвалидировался.
+func (c *lru[K, V]) add(key K, value V, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation || c.capacity <= 0 {
+		return
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.removeElement(c.order.Back())
+		c.evictions++
+	}
+}
+
+// remove удаляет значение по ключу.
+func (c *lru[K, V]) remove(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if elem, ok := c.items[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// clear удаляет все значения.
+func (c *lru[K, V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.order.Init()
+	c.items = make(map[K]*list.Element, c.capacity)
+}
+
+// stats возвращает статистику использования кэша.
+func (c *lru[K, V]) stats(name string) Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{
+		Name:      name,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   c.order.Len(),
+	}
+}
+
+func (c *lru[K, V]) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.items, elem.Value.(*entry[K, V]).key)
+}
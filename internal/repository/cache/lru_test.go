@@ -0,0 +1,99 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newLRU[string, int](2, time.Hour)
+	_, generation, _ := c.get("a")
+	c.add("a", 1, generation)
+	c.add("b", 2, generation)
+
+	// Чтение "a" делает "b" самым давним элементом
+	if value, _, ok := c.get("a"); !ok || value != 1 {
+		t.Fatalf("get(a) = %d, %v; want 1, true", value, ok)
+	}
+	c.add("c", 3, generation)
+
+	if _, _, ok := c.get("b"); ok {
+		t.Error("b was not evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if value, _, ok := c.get(key); !ok || value != want {
+			t.Errorf("get(%s) = %d, %v; want %d, true", key, value, ok, want)
+		}
+	}
+
+	stats := c.stats("test")
+	if stats.Entries != 2 || stats.Evictions != 1 || stats.Hits != 3 || stats.Misses != 2 {
+		t.Errorf("stats = %+v, want 2 entries, 1 eviction, 3 hits, 2 misses", stats)
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	c := newLRU[string, int](10, time.Nanosecond)
+	_, generation, _ := c.get("a")
+	c.add("a", 1, generation)
+	time.Sleep(time.Millisecond)
+
+	if _, _, ok := c.get("a"); ok {
+		t.Error("expired entry returned")
+	}
+	if entries := c.stats("test").Entries; entries != 0 {
+		t.Errorf("entries = %d, want expired entry removed", entries)
+	}
+}
+
+func TestLRUUpdateRefreshesValue(t *testing.T) {
+	c := newLRU[string, int](1, time.Hour)
+	_, generation, _ := c.get("a")
+	c.add("a", 1, generation)
+	c.add("a", 2, generation)
+
+	if value, _, ok := c.get("a"); !ok || value != 2 {
+		t.Errorf("get(a) = %d, %v; want 2, true", value, ok)
+	}
+	if evictions := c.stats("test").Evictions; evictions != 0 {
+		t.Errorf("evictions = %d, want 0 for an updated key", evictions)
+	}
+}
+
+func TestLRUDropsValuesLoadedBeforeInvalidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		invalidate func(c *lru[string, int])
+	}{
+		{name: "remove of another key", invalidate: func(c *lru[string, int]) { c.remove("b") }},
+		{name: "clear", invalidate: func(c *lru[string, int]) { c.clear() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newLRU[string, int](10, time.Hour)
+			_, generation, _ := c.get("a")
+			// Значение загружалось из базы, пока кэш инвалидировался
+			tt.invalidate(c)
+			c.add("a", 1, generation)
+
+			if _, _, ok := c.get("a"); ok {
+				t.Error("value loaded before invalidation was cached")
+			}
+			_, generation, _ = c.get("a")
+			c.add("a", 2, generation)
+			if value, _, ok := c.get("a"); !ok || value != 2 {
+				t.Errorf("get(a) = %d, %v; want 2, true after a fresh load", value, ok)
+			}
+		})
+	}
+}
+
+func TestLRUZeroCapacityDisablesCaching(t *testing.T) {
+	c := newLRU[string, int](0, time.Hour)
+	_, generation, _ := c.get("a")
+	c.add("a", 1, generation)
+	if _, _, ok := c.get("a"); ok {
+		t.Error("value cached with zero capacity")
+	}
+}
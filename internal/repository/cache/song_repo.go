@@ -0,0 +1,254 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"golang.org/x/sync/singleflight"
+)
+
+// Config содержит настройки кэша репозитория.
+type Config struct {
+	TTL          time.Duration // Время жизни элемента кэша
+	MaxSongs     int           // Максимальное количество песен в кэше
+	MaxLists     int           // Максимальное количество страниц списка песен в кэше
+	SingleFlight bool          // Объединять одновременные загрузки одного ключа в один запрос
+	LoadTimeout  time.Duration // Ограничение времени общей загрузки в режиме single-flight
+}
+
+// Stats - статистика использования одного кэша.
+type Stats struct {
+	Name      string // Название кэша
+	Hits      uint64 // Количество попаданий
+	Misses    uint64 // Количество промахов
+	Evictions uint64 // Количество вытесненных элементов
+	Entries   int    // Текущее количество элементов
+}
+
+// SongRepository - декоратор репозитория песен с кэшированием чтения песни по идентификатору
+// и страниц списка песен. Любое изменение песен инвалидирует затронутую песню и все страницы списка.
+// Возвращаемые значения копируются, поэтому изменение результата вызывающим кодом не портит кэш.
+// Чтения, требующие собственных изменений (repository.ReadYourWrites), минуют кэш, но обновляют его.
+type SongRepository struct {
+	next        repository.SongRepository
+	songs       *lru[int, *models.Song]
+	lists       *lru[string, *models.SongsResponse]
+	flight      *singleflight.Group
+	loadTimeout time.Duration
+}
+
+var _ repository.SongRepository = (*SongRepository)(nil)
+
+// NewSongRepository создает кэширующий декоратор над репозиторием next.
+func NewSongRepository(next repository.SongRepository, cfg Config) *SongRepository {
+	r := &SongRepository{
+		next:  next,
+		songs: newLRU[int, *models.Song](cfg.MaxSongs, cfg.TTL),
+		lists: newLRU[string, *models.SongsResponse](cfg.MaxLists, cfg.TTL),
+	}
+	if cfg.SingleFlight {
+		r.flight = &singleflight.Group{}
+		r.loadTimeout = cfg.LoadTimeout
+	}
+	return r
+}
+
+// Stats возвращает статистику кэшей песен и страниц списка.
+func (r *SongRepository) Stats() []Stats {
+	return []Stats{r.songs.stats("songs"), r.lists.stats("song_lists")}
+}
+
+// load выполняет загрузку, объединяя одновременные загрузки одного ключа в режиме single-flight.
+// Чтения собственных изменений не присоединяются к общей загрузке, которая может идти с реплики.
+//
+// Общая загрузка выполняется с контекстом, не отменяемым вместе с запросом первого вызывающего,
+// и ограничена LoadTimeout: отмена одного запроса не должна завершать ошибкой остальные.
+// Каждый вызывающий перестает ждать результат при отмене собственного контекста.
+func (r *SongRepository) load(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if r.flight == nil || repository.ReadYourWrites(ctx) {
+		return fn(ctx)
+	}
+
+	results := r.flight.DoChan(key, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		if r.loadTimeout > 0 {
+			var cancel context.CancelFunc
+			loadCtx, cancel = context.WithTimeout(loadCtx, r.loadTimeout)
+			defer cancel()
+		}
+		return fn(loadCtx)
+	})
+	select {
+	case result := <-results:
+		return result.Val, result.Err
+	case <-ctx.Done():
+		if stderrors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, errors.NewTimeout("request timed out", ctx.Err())
+		}
+		return nil, errors.NewCanceled("request canceled", ctx.Err())
+	}
+}
+
+// GetSongs получает страницу списка песен из кэша или из репозитория.
+func (r *SongRepository) GetSongs(ctx context.Context, filter *models.SongFilter) (*models.SongsResponse, error) {
+	key := filterKey(filter)
+	cached, generation, ok := r.lists.get(key)
+	if ok && !repository.ReadYourWrites(ctx) {
+		return copySongsResponse(cached), nil
+	}
+
+	value, err := r.load(ctx, "list:"+key, func(ctx context.Context) (interface{}, error) {
+		resp, err := r.next.GetSongs(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		r.lists.add(key, resp, generation)
+		return resp, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copySongsResponse(value.(*models.SongsResponse)), nil
+}
+
+// GetSongByID получает песню из кэша или из репозитория.
+func (r *SongRepository) GetSongByID(ctx context.Context, id int) (*models.Song, error) {
+	cached, generation, ok := r.songs.get(id)
+	if ok && !repository.ReadYourWrites(ctx) {
+		return copySong(cached), nil
+	}
+
+	value, err := r.load(ctx, "song:"+strconv.Itoa(id), func(ctx context.Context) (interface{}, error) {
+		song, err := r.next.GetSongByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		r.songs.add(id, song, generation)
+		return song, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return copySong(value.(*models.Song)), nil
+}
+
+// CreateSong создает песню и инвалидирует страницы списка.
+func (r *SongRepository) CreateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	created, err := r.next.CreateSong(ctx, song)
+	r.lists.clear()
+	return created, err
+}
+
+// UpdateSong обновляет песню и инвалидирует ее вместе со страницами списка.
+func (r *SongRepository) UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	updated, err := r.next.UpdateSong(ctx, song)
+	r.invalidate(song.ID)
+	return updated, err
+}
+
+// DeleteSong помещает песню в корзину и инвалидирует ее вместе со страницами списка.
+func (r *SongRepository) DeleteSong(ctx context.Context, id, version int) error {
+	err := r.next.DeleteSong(ctx, id, version)
+	r.invalidate(id)
+	return err
+}
+
+// GetRevisions получает историю ревизий песни без кэширования.
+func (r *SongRepository) GetRevisions(ctx context.Context, songID int) ([]models.SongRevision, error) {
+	return r.next.GetRevisions(ctx, songID)
+}
+
+// GetRevision получает ревизию песни без кэширования.
+func (r *SongRepository) GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error) {
+	return r.next.GetRevision(ctx, songID, revision)
+}
+
+// GetDeletedSongs получает список песен в корзине без кэширования.
+func (r *SongRepository) GetDeletedSongs(ctx context.Context, page, pageSize int) (*models.SongsResponse, error) {
+	return r.next.GetDeletedSongs(ctx, page, pageSize)
+}
+
+// RestoreSong восстанавливает песню из корзины и инвалидирует ее вместе со страницами списка.
+func (r *SongRepository) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	song, err := r.next.RestoreSong(ctx, id)
+	r.invalidate(id)
+	return song, err
+}
+
+// PurgeSong окончательно удаляет песню и инвалидирует ее вместе со страницами списка.
+func (r *SongRepository) PurgeSong(ctx context.Context, id int) error {
+	err := r.next.PurgeSong(ctx, id)
+	r.invalidate(id)
+	return err
+}
+
+// PurgeExpiredSongs окончательно удаляет песни из корзины. Удаленные песни уже отсутствуют
+// в кэше чтения, поэтому кэш не инвалидируется.
+func (r *SongRepository) PurgeExpiredSongs(ctx context.Context, before time.Time) (int64, error) {
+	return r.next.PurgeExpiredSongs(ctx, before)
+}
+
+// invalidate удаляет песню и все страницы списка из кэша.
+// Инвалидация выполняется и при ошибке, так как изменение могло быть применено частично.
+func (r *SongRepository) invalidate(id int) {
+	r.songs.remove(id)
+	r.lists.clear()
+}
+
+// invalidateAll удаляет из кэша все песни и страницы списка.
+func (r *SongRepository) invalidateAll() {
+	r.songs.clear()
+	r.lists.clear()
+}
+
+// filterKey формирует ключ кэша для фильтра списка песен.
+func filterKey(filter *models.SongFilter) string {
+	return fmt.Sprintf("%d|%d|%q|%q|%s|%s|%q|%q|%d|%d|%s",
+		filter.ArtistID, filter.AlbumID, filter.GroupName, filter.SongName, timeKey(filter.FromDate), timeKey(filter.ToDate),
+		filter.Text, filter.Link, filter.Page, filter.PageSize, filter.Count)
+}
+
+func timeKey(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// copySong создает копию песни, не разделяющую указатели с исходной.
+func copySong(song *models.Song) *models.Song {
+	copied := *song
+	if song.ReleaseDate != nil {
+		releaseDate := *song.ReleaseDate
+		copied.ReleaseDate = &releaseDate
+	}
+	if song.DeletedAt != nil {
+		deletedAt := *song.DeletedAt
+		copied.DeletedAt = &deletedAt
+	}
+	return &copied
+}
+
+// copySongsResponse создает копию страницы списка песен.
+func copySongsResponse(resp *models.SongsResponse) *models.SongsResponse {
+	copied := *resp
+	if resp.TotalItems != nil {
+		totalItems := *resp.TotalItems
+		copied.TotalItems = &totalItems
+	}
+	if resp.TotalPages != nil {
+		totalPages := *resp.TotalPages
+		copied.TotalPages = &totalPages
+	}
+	copied.Songs = make([]models.Song, len(resp.Songs))
+	for i := range resp.Songs {
+		copied.Songs[i] = *copySong(&resp.Songs[i])
+	}
+	return &copied
+}
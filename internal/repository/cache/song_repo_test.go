@@ -0,0 +1,194 @@
+package cache
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// repoStub считает обращения к нижележащему репозиторию. Загрузка песни может быть
+// приостановлена до закрытия канала release.
+type repoStub struct {
+	repository.SongRepository
+	songCalls atomic.Int32
+	listCalls atomic.Int32
+	release   chan struct{}
+	started   chan struct{}
+}
+
+func (r *repoStub) GetSongByID(ctx context.Context, id int) (*models.Song, error) {
+	r.songCalls.Add(1)
+	if r.started != nil {
+		r.started <- struct{}{}
+	}
+	if r.release != nil {
+		select {
+		case <-r.release:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return &models.Song{ID: id, GroupName: "Muse", Version: int(r.songCalls.Load())}, nil
+}
+
+func (r *repoStub) GetSongs(ctx context.Context, filter *models.SongFilter) (*models.SongsResponse, error) {
+	r.listCalls.Add(1)
+	return &models.SongsResponse{Songs: []models.Song{{ID: 1, GroupName: "Muse"}}}, nil
+}
+
+func (r *repoStub) UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	return song, nil
+}
+
+func newTestRepo(next *repoStub, singleFlight bool) *SongRepository {
+	return NewSongRepository(next, Config{TTL: time.Hour, MaxSongs: 10, MaxLists: 10, SingleFlight: singleFlight, LoadTimeout: time.Second})
+}
+
+func TestSongRepositoryCachesReads(t *testing.T) {
+	ctx := context.Background()
+	next := &repoStub{}
+	repo := newTestRepo(next, false)
+
+	first, err := repo.GetSongByID(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Изменение результата вызывающим кодом не должно попасть в кэш
+	first.GroupName = "changed"
+
+	second, _ := repo.GetSongByID(ctx, 1)
+	if calls := next.songCalls.Load(); calls != 1 {
+		t.Errorf("repository called %d times, want 1", calls)
+	}
+	if second.GroupName != "Muse" {
+		t.Errorf("cached song was modified by the caller: %q", second.GroupName)
+	}
+
+	filter := &models.SongFilter{GroupName: "Muse", Page: 1, PageSize: 10}
+	page, _ := repo.GetSongs(ctx, filter)
+	page.Songs[0].GroupName = "changed"
+	page, _ = repo.GetSongs(ctx, &models.SongFilter{GroupName: "Muse", Page: 1, PageSize: 10})
+	if calls := next.listCalls.Load(); calls != 1 || page.Songs[0].GroupName != "Muse" {
+		t.Errorf("list calls = %d, group = %q; want 1 call and an unmodified page", calls, page.Songs[0].GroupName)
+	}
+	if _, _ = repo.GetSongs(ctx, &models.SongFilter{GroupName: "Muse", Page: 2, PageSize: 10}); next.listCalls.Load() != 2 {
+		t.Error("different page served from the cache")
+	}
+}
+
+func TestSongRepositoryInvalidatesOnWrite(t *testing.T) {
+	ctx := context.Background()
+	next := &repoStub{}
+	repo := newTestRepo(next, false)
+	filter := &models.SongFilter{Page: 1, PageSize: 10}
+
+	_, _ = repo.GetSongByID(ctx, 1)
+	_, _ = repo.GetSongs(ctx, filter)
+	if _, err := repo.UpdateSong(ctx, &models.Song{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = repo.GetSongByID(ctx, 1)
+	_, _ = repo.GetSongs(ctx, filter)
+
+	if songs, lists := next.songCalls.Load(), next.listCalls.Load(); songs != 2 || lists != 2 {
+		t.Errorf("repository calls after update: songs %d, lists %d; want 2 and 2", songs, lists)
+	}
+}
+
+func TestSongRepositoryDropsLoadRacingWithUpdate(t *testing.T) {
+	ctx := context.Background()
+	next := &repoStub{release: make(chan struct{}), started: make(chan struct{}, 1)}
+	repo := newTestRepo(next, false)
+
+	done := make(chan *models.Song)
+	go func() {
+		song, _ := repo.GetSongByID(ctx, 1)
+		done <- song
+	}()
+	<-next.started
+	// Песня изменяется, пока чтение еще не вернуло прежнюю версию
+	_, _ = repo.UpdateSong(ctx, &models.Song{ID: 1})
+	close(next.release)
+	<-done
+
+	next.release, next.started = nil, nil
+	song, _ := repo.GetSongByID(ctx, 1)
+	if song.Version != 2 {
+		t.Errorf("version = %d, want a fresh load instead of the stale cached song", song.Version)
+	}
+}
+
+func TestSongRepositorySingleFlight(t *testing.T) {
+	ctx := context.Background()
+	next := &repoStub{release: make(chan struct{}), started: make(chan struct{}, 10)}
+	repo := newTestRepo(next, true)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if song, err := repo.GetSongByID(ctx, 1); err != nil || song.ID != 1 {
+				t.Errorf("GetSongByID() = %v, %v", song, err)
+			}
+		}()
+	}
+	<-next.started
+	// Даем остальным вызовам присоединиться к выполняющейся загрузке
+	time.Sleep(50 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+
+	if calls := next.songCalls.Load(); calls != 1 {
+		t.Errorf("repository called %d times, want 1 for concurrent loads", calls)
+	}
+}
+
+func TestSongRepositorySingleFlightSurvivesCanceledCaller(t *testing.T) {
+	next := &repoStub{release: make(chan struct{}), started: make(chan struct{}, 10)}
+	repo := newTestRepo(next, true)
+
+	// Первый вызывающий запускает общую загрузку и отменяет свой запрос, не дождавшись ее
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error)
+	go func() {
+		_, err := repo.GetSongByID(firstCtx, 1)
+		firstErr <- err
+	}()
+	<-next.started
+
+	second := make(chan *models.Song)
+	go func() {
+		song, err := repo.GetSongByID(context.Background(), 1)
+		if err != nil {
+			t.Errorf("second caller: %v", err)
+		}
+		second <- song
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancelFirst()
+	var appErr *errors.Error
+	if err := <-firstErr; !stderrors.As(err, &appErr) || appErr.Type != errors.Canceled {
+		t.Errorf("canceled caller error = %v, want Canceled", err)
+	}
+
+	close(next.release)
+	if song := <-second; song == nil || song.ID != 1 {
+		t.Fatalf("second caller got %v, want the shared load result", song)
+	}
+	if calls := next.songCalls.Load(); calls != 1 {
+		t.Errorf("repository called %d times, want 1", calls)
+	}
+	if _, _, ok := repo.songs.get(1); !ok {
+		t.Error("shared load result was not cached")
+	}
+}
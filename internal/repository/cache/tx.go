@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// WithTx выполняет fn в транзакции. Чтения внутри транзакции минуют кэш, так как должны видеть
+// ее незафиксированные изменения. Затронутые песни и страницы списка инвалидируются после
+// завершения транзакции, чтобы кэш не заполнился данными до фиксации.
+func (r *SongRepository) WithTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	tx := &txRepository{}
+	err := r.next.WithTx(ctx, func(next repository.Tx) error {
+		tx.Tx = next
+		return fn(tx)
+	})
+	if tx.dirty {
+		for _, id := range tx.changed {
+			r.songs.remove(id)
+		}
+		r.lists.clear()
+	}
+	return err
+}
+
+// txRepository - репозиторий, привязанный к транзакции, запоминающий изменения для инвалидации кэша.
+// Запоминание выполняется и при ошибке, так как транзакция может быть повторена или фиксация может
+// завершиться неизвестным результатом.
+type txRepository struct {
+	repository.Tx
+	dirty   bool  // в транзакции выполнялись изменения
+	changed []int // идентификаторы измененных песен
+}
+
+// record отмечает изменение песни с идентификатором id. Нулевой id означает создание песни.
+func (t *txRepository) record(id int) {
+	t.dirty = true
+	if id != 0 {
+		t.changed = append(t.changed, id)
+	}
+}
+
+// CreateSong создает песню в транзакции.
+func (t *txRepository) CreateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	t.record(0)
+	return t.Tx.CreateSong(ctx, song)
+}
+
+// UpdateSong обновляет песню в транзакции.
+func (t *txRepository) UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	t.record(song.ID)
+	return t.Tx.UpdateSong(ctx, song)
+}
+
+// DeleteSong помещает песню в корзину в транзакции.
+func (t *txRepository) DeleteSong(ctx context.Context, id, version int) error {
+	t.record(id)
+	return t.Tx.DeleteSong(ctx, id, version)
+}
+
+// RestoreSong восстанавливает песню из корзины в транзакции.
+func (t *txRepository) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	t.record(id)
+	return t.Tx.RestoreSong(ctx, id)
+}
+
+// PurgeSong окончательно удаляет песню в транзакции.
+func (t *txRepository) PurgeSong(ctx context.Context, id int) error {
+	t.record(id)
+	return t.Tx.PurgeSong(ctx, id)
+}
+
+// DeleteAlbum удаляет альбом в транзакции, что меняет результаты фильтра списка по альбому.
+func (t *txRepository) DeleteAlbum(ctx context.Context, id int) error {
+	t.dirty = true
+	return t.Tx.DeleteAlbum(ctx, id)
+}
+
+// AddAlbumTrack добавляет песню в альбом в транзакции, что меняет результаты фильтра списка по альбому.
+func (t *txRepository) AddAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	t.dirty = true
+	return t.Tx.AddAlbumTrack(ctx, albumID, songID, trackNumber)
+}
+
+// RemoveAlbumTrack удаляет песню из альбома в транзакции, что меняет результаты фильтра списка по альбому.
+func (t *txRepository) RemoveAlbumTrack(ctx context.Context, albumID, songID int) error {
+	t.dirty = true
+	return t.Tx.RemoveAlbumTrack(ctx, albumID, songID)
+}
+
+// WithTx присоединяет вложенный вызов к текущей транзакции.
+func (t *txRepository) WithTx(_ context.Context, fn func(tx repository.Tx) error) error {
+	return fn(t)
+}
@@ -0,0 +1,23 @@
+package repository
+
+import "context"
+
+// ctxKey - тип ключей контекста пакета, исключающий коллизии с другими пакетами.
+type ctxKey int
+
+const (
+	readYourWritesKey ctxKey = iota
+	isolationLevelKey
+)
+
+// WithReadYourWrites отмечает, что чтения в рамках контекста должны видеть собственные изменения
+// вызывающего кода и поэтому выполняются на основной базе данных, а не на репликах.
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readYourWritesKey, true)
+}
+
+// ReadYourWrites проверяет, требуется ли чтение собственных изменений.
+func ReadYourWrites(ctx context.Context) bool {
+	required, _ := ctx.Value(readYourWritesKey).(bool)
+	return required
+}
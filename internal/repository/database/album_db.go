@@ -0,0 +1,447 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"go.uber.org/zap"
+)
+
+// albumColumns - столбцы альбома в порядке, ожидаемом scanAlbum, включая количество треков вне корзины.
+const albumColumns = `al.id, al.artist_id, a.name, al.title, al.release_date, al.release_date_precision, al.cover_url,
+		al.created_at, al.updated_at,
+		(SELECT COUNT(*) FROM album_tracks t JOIN songs s ON s.id = t.song_id WHERE t.album_id = al.id AND s.deleted_at IS NULL)`
+
+// albumsFrom - источник строк альбомов вместе с их исполнителями.
+const albumsFrom = `albums al JOIN artists a ON a.id = al.artist_id`
+
+// SQL Queries
+const (
+	// insert добавить альбом
+	addAlbumQuery = `
+		INSERT INTO albums (artist_id, title, release_date, release_date_precision, cover_url)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	// queries получить альбом по id
+	getAlbumByIDQuery = `
+		SELECT ` + albumColumns + `
+		FROM ` + albumsFrom + `
+		WHERE al.id = $1`
+
+	// queries получить треки альбома по порядку, кроме песен в корзине
+	getAlbumTracksQuery = `
+		SELECT t.track_number, ` + songColumns + `
+		FROM album_tracks t
+		JOIN songs s ON s.id = t.song_id
+		JOIN artists a ON a.id = s.artist_id
+		WHERE t.album_id = $1 AND s.deleted_at IS NULL
+		ORDER BY t.track_number`
+
+	// update обновить альбом
+	updateAlbumQuery = `
+		UPDATE albums
+		SET artist_id = $1,
+			title = $2,
+			release_date = $3,
+			release_date_precision = $4,
+			cover_url = $5,
+			updated_at = NOW()
+		WHERE id = $6`
+
+	// delete удалить альбом вместе с его треками, песни остаются
+	deleteAlbumQuery = `DELETE FROM albums WHERE id = $1`
+
+	// queries заблокировать альбом, чтобы изменения порядка треков выполнялись последовательно
+	lockAlbumQuery = `SELECT id FROM albums WHERE id = $1 FOR UPDATE`
+
+	// update отметить изменение альбома
+	touchAlbumQuery = `UPDATE albums SET updated_at = NOW() WHERE id = $1`
+
+	// queries проверить, что песня существует и не находится в корзине
+	songActiveQuery = `SELECT EXISTS(SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)`
+
+	// queries получить номер трека песни в альбоме
+	getTrackNumberQuery = `SELECT track_number FROM album_tracks WHERE album_id = $1 AND song_id = $2`
+
+	// queries проверить, занят ли номер трека
+	trackNumberTakenQuery = `SELECT EXISTS(SELECT 1 FROM album_tracks WHERE album_id = $1 AND track_number = $2)`
+
+	// queries получить номер трека после последнего
+	nextTrackNumberQuery = `SELECT COALESCE(MAX(track_number), 0) + 1 FROM album_tracks WHERE album_id = $1`
+
+	// update сдвинуть треки, начиная с номера, на одну позицию
+	shiftTracksQuery = `
+		UPDATE album_tracks
+		SET track_number = track_number + 1
+		WHERE album_id = $1 AND track_number >= $2`
+
+	// insert добавить трек
+	addTrackQuery = `INSERT INTO album_tracks (album_id, song_id, track_number) VALUES ($1, $2, $3)`
+
+	// delete удалить трек
+	removeTrackQuery = `DELETE FROM album_tracks WHERE album_id = $1 AND song_id = $2`
+)
+
+// GetAlbums получает список альбомов с количеством треков, отфильтрованный по исполнителю и подстроке названия.
+func (r *PostgresSongRepository) GetAlbums(ctx context.Context, filter *models.AlbumFilter) (*models.AlbumsResponse, error) {
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	var response *models.AlbumsResponse
+	err := r.read(ctx, func(db querier) error {
+		var err error
+		response, err = r.getAlbums(ctx, db, filter)
+		return err
+	})
+	return response, err
+}
+
+// getAlbums получает страницу альбомов и их общее количество на одном экземпляре базы данных.
+func (r *PostgresSongRepository) getAlbums(ctx context.Context, db querier, filter *models.AlbumFilter) (*models.AlbumsResponse, error) {
+	b := &queryBuilder{}
+	if filter.ArtistID != 0 {
+		b.where("al.artist_id = " + b.arg(filter.ArtistID))
+	}
+	if filter.Search != "" {
+		b.where("al.title ILIKE " + b.arg(containsPattern(filter.Search)))
+	}
+
+	var totalItems int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM albums al "+b.clause(), b.args...).Scan(&totalItems); err != nil {
+		return nil, mapError("failed to count albums", err)
+	}
+
+	totalPages := (totalItems + filter.PageSize - 1) / filter.PageSize
+	if totalItems > 0 && filter.Page > totalPages {
+		return nil, errors.NewNotFound(fmt.Sprintf("page %d does not exist, total pages: %d", filter.Page, totalPages), nil)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := "SELECT " + albumColumns + " FROM " + albumsFrom + " " + b.clause() +
+		" ORDER BY lower(al.title), al.id LIMIT " + b.arg(filter.PageSize) + " OFFSET " + b.arg(offset)
+	rows, err := db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, mapError("failed to query albums", err)
+	}
+	defer rows.Close()
+
+	albums := []models.Album{}
+	for rows.Next() {
+		var album models.Album
+		if err := scanAlbum(rows, &album); err != nil {
+			return nil, mapError("failed to scan album", err)
+		}
+		albums = append(albums, album)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError("error occurred while iterating over albums", err)
+	}
+
+	return &models.AlbumsResponse{
+		Albums:     albums,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetAlbumByID получает альбом с треками по порядку.
+func (r *PostgresSongRepository) GetAlbumByID(ctx context.Context, id int) (*models.Album, error) {
+	var album *models.Album
+	err := r.read(ctx, func(db querier) error {
+		var err error
+		album, err = getAlbum(ctx, db, id)
+		return err
+	})
+	return album, err
+}
+
+// CreateAlbum создает альбом исполнителя. Название должно быть уникальным у исполнителя без учета регистра.
+func (r *PostgresSongRepository) CreateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	var created *models.Album
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		var id int
+		if err := tx.q.QueryRowContext(ctx, addAlbumQuery,
+			album.ArtistID,
+			album.Title,
+			releaseDateValue(album.ReleaseDate),
+			releaseDatePrecision(album.ReleaseDate),
+			album.CoverURL,
+		).Scan(&id); err != nil {
+			return mapError("failed to insert album", err)
+		}
+
+		var err error
+		created, err = getAlbum(ctx, tx.q, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Album inserted", zap.Int("albumId", created.ID))
+	return created, nil
+}
+
+// UpdateAlbum заменяет исполнителя, название, дату выпуска и обложку альбома.
+func (r *PostgresSongRepository) UpdateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	var updated *models.Album
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		result, err := tx.q.ExecContext(ctx, updateAlbumQuery,
+			album.ArtistID,
+			album.Title,
+			releaseDateValue(album.ReleaseDate),
+			releaseDatePrecision(album.ReleaseDate),
+			album.CoverURL,
+			album.ID,
+		)
+		if err != nil {
+			return mapError("failed to update album", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return mapError("failed to retrieve affected rows after update", err)
+		} else if rowsAffected == 0 {
+			return errors.NewNotFound("album not found", nil)
+		}
+
+		updated, err = getAlbum(ctx, tx.q, album.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Album updated", zap.Int("albumId", updated.ID))
+	return updated, nil
+}
+
+// DeleteAlbum удаляет альбом и его треки. Сами песни не удаляются.
+func (r *PostgresSongRepository) DeleteAlbum(ctx context.Context, id int) error {
+	var result sql.Result
+	err := r.withRetry(ctx, func() (err error) {
+		result, err = r.q.ExecContext(ctx, deleteAlbumQuery, id)
+		return err
+	})
+	if err != nil {
+		return mapError("failed to delete album", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return mapError("failed to retrieve affected rows after delete", err)
+	} else if rowsAffected == 0 {
+		return errors.NewNotFound("album not found", nil)
+	}
+
+	r.log(ctx).Debug("Album deleted", zap.Int("albumId", id))
+	return nil
+}
+
+// AddAlbumTrack добавляет песню в альбом под номером trackNumber, сдвигая треки с этим и большими номерами.
+// Нулевой trackNumber добавляет песню в конец альбома.
+func (r *PostgresSongRepository) AddAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	var album *models.Album
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockAlbum(ctx, albumID); err != nil {
+			return err
+		}
+
+		var active bool
+		if err := tx.q.QueryRowContext(ctx, songActiveQuery, songID).Scan(&active); err != nil {
+			return mapError("failed to check song", err)
+		}
+		if !active {
+			return errors.NewNotFound("song not found", nil)
+		}
+
+		var current int
+		err := tx.q.QueryRowContext(ctx, getTrackNumberQuery, albumID, songID).Scan(&current)
+		if err == nil {
+			return errors.NewConflict(fmt.Sprintf("song is already track %d of the album", current), nil)
+		} else if err != sql.ErrNoRows {
+			return mapError("failed to get album track", err)
+		}
+
+		if err := tx.placeAlbumTrack(ctx, albumID, songID, trackNumber); err != nil {
+			return err
+		}
+
+		album, err = getAlbum(ctx, tx.q, albumID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Album track inserted", zap.Int("albumId", albumID), zap.Int("songId", songID))
+	return album, nil
+}
+
+// MoveAlbumTrack переносит песню альбома на номер trackNumber, сдвигая треки с этим и большими номерами.
+func (r *PostgresSongRepository) MoveAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	var album *models.Album
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockAlbum(ctx, albumID); err != nil {
+			return err
+		}
+
+		var current int
+		err := tx.q.QueryRowContext(ctx, getTrackNumberQuery, albumID, songID).Scan(&current)
+		if err == sql.ErrNoRows {
+			return errors.NewNotFound("album track not found", err)
+		} else if err != nil {
+			return mapError("failed to get album track", err)
+		}
+
+		if current != trackNumber {
+			// Трек освобождает свою позицию и занимает новую так же, как при добавлении
+			if _, err := tx.q.ExecContext(ctx, removeTrackQuery, albumID, songID); err != nil {
+				return mapError("failed to move album track", err)
+			}
+			if err := tx.placeAlbumTrack(ctx, albumID, songID, trackNumber); err != nil {
+				return err
+			}
+		}
+
+		album, err = getAlbum(ctx, tx.q, albumID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Album track moved", zap.Int("albumId", albumID), zap.Int("songId", songID))
+	return album, nil
+}
+
+// RemoveAlbumTrack удаляет песню из альбома. Номера остальных треков не меняются.
+func (r *PostgresSongRepository) RemoveAlbumTrack(ctx context.Context, albumID, songID int) error {
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		result, err := tx.q.ExecContext(ctx, removeTrackQuery, albumID, songID)
+		if err != nil {
+			return mapError("failed to delete album track", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return mapError("failed to retrieve affected rows after delete", err)
+		} else if rowsAffected == 0 {
+			return errors.NewNotFound("album track not found", nil)
+		}
+
+		if _, err := tx.q.ExecContext(ctx, touchAlbumQuery, albumID); err != nil {
+			return mapError("failed to update album", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.log(ctx).Debug("Album track deleted", zap.Int("albumId", albumID), zap.Int("songId", songID))
+	return nil
+}
+
+// placeAlbumTrack записывает песню в альбом под номером trackNumber. Если номер занят, треки с этим
+// и большими номерами сдвигаются на одну позицию. Нулевой trackNumber означает позицию после последнего трека.
+func (r *PostgresSongRepository) placeAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) error {
+	if trackNumber == 0 {
+		if err := r.q.QueryRowContext(ctx, nextTrackNumberQuery, albumID).Scan(&trackNumber); err != nil {
+			return mapError("failed to get next track number", err)
+		}
+	} else {
+		var taken bool
+		if err := r.q.QueryRowContext(ctx, trackNumberTakenQuery, albumID, trackNumber).Scan(&taken); err != nil {
+			return mapError("failed to check track number", err)
+		}
+		if taken {
+			if _, err := r.q.ExecContext(ctx, shiftTracksQuery, albumID, trackNumber); err != nil {
+				return mapError("failed to shift album tracks", err)
+			}
+		}
+	}
+
+	if _, err := r.q.ExecContext(ctx, addTrackQuery, albumID, songID, trackNumber); err != nil {
+		return mapError("failed to insert album track", err)
+	}
+	if _, err := r.q.ExecContext(ctx, touchAlbumQuery, albumID); err != nil {
+		return mapError("failed to update album", err)
+	}
+	return nil
+}
+
+// lockAlbum блокирует альбом до конца транзакции и проверяет, что он существует.
+func (r *PostgresSongRepository) lockAlbum(ctx context.Context, id int) error {
+	err := r.q.QueryRowContext(ctx, lockAlbumQuery, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.NewNotFound("album not found", err)
+	}
+	return mapError("failed to lock album", err)
+}
+
+// getAlbum получает альбом с треками по порядку.
+func getAlbum(ctx context.Context, db querier, id int) (*models.Album, error) {
+	var album models.Album
+	err := scanAlbum(db.QueryRowContext(ctx, getAlbumByIDQuery, id), &album)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFound("album not found", err)
+	} else if err != nil {
+		return nil, mapError("failed to get album", err)
+	}
+
+	rows, err := db.QueryContext(ctx, getAlbumTracksQuery, id)
+	if err != nil {
+		return nil, mapError("failed to query album tracks", err)
+	}
+	defer rows.Close()
+
+	album.Tracks = []models.AlbumTrack{}
+	for rows.Next() {
+		var track models.AlbumTrack
+		if err := scanSong(prefixScanner{row: rows, prefix: []interface{}{&track.TrackNumber}}, &track.Song); err != nil {
+			return nil, mapError("failed to scan album track", err)
+		}
+		album.Tracks = append(album.Tracks, track)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError("error occurred while iterating over album tracks", err)
+	}
+	return &album, nil
+}
+
+// scanAlbum считывает альбом вместе с количеством треков.
+func scanAlbum(row rowScanner, album *models.Album) error {
+	var releaseDate releaseDateColumns
+	if err := row.Scan(
+		&album.ID,
+		&album.ArtistID,
+		&album.GroupName,
+		&album.Title,
+		&releaseDate.date,
+		&releaseDate.precision,
+		&album.CoverURL,
+		&album.CreatedAt,
+		&album.UpdatedAt,
+		&album.TrackCount,
+	); err != nil {
+		return err
+	}
+	album.ReleaseDate = releaseDate.value()
+	return nil
+}
+
+// prefixScanner считывает столбцы prefix перед столбцами, которые ожидает вложенная функция сканирования.
+type prefixScanner struct {
+	row    rowScanner
+	prefix []interface{}
+}
+
+// Scan считывает строку в prefix и dest.
+func (p prefixScanner) Scan(dest ...interface{}) error {
+	return p.row.Scan(append(append([]interface{}{}, p.prefix...), dest...)...)
+}
@@ -0,0 +1,540 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// artistColumns - столбцы исполнителя в порядке, ожидаемом scanArtist, включая количество его песен.
+const artistColumns = `a.id, a.name, a.created_at, a.updated_at,
+		(SELECT COUNT(*) FROM songs s WHERE s.artist_id = a.id AND s.deleted_at IS NULL)`
+
+// SQL Queries
+const (
+	// insert добавить исполнителя
+	addArtistQuery = `
+		INSERT INTO artists AS a (name)
+		VALUES ($1)
+		RETURNING ` + artistColumns
+
+	// insert добавить исполнителя, если исполнителя с таким именем без учета регистра еще нет
+	addArtistIfMissingQuery = `
+		INSERT INTO artists AS a (name)
+		VALUES ($1)
+		ON CONFLICT ((lower(name))) DO NOTHING
+		RETURNING ` + artistColumns
+
+	// queries получить исполнителя по id
+	getArtistByIDQuery = `
+		SELECT ` + artistColumns + `
+		FROM artists a
+		WHERE a.id = $1`
+
+	// queries найти исполнителя по имени или псевдониму без учета регистра
+	getArtistByNameQuery = `
+		SELECT ` + artistColumns + `
+		FROM artists a
+		WHERE lower(a.name) = lower($1)
+		   OR a.id = (SELECT al.artist_id FROM artist_aliases al WHERE lower(al.name) = lower($1))`
+
+	// queries проверить, занято ли имя другим исполнителем или псевдонимом
+	artistNameTakenQuery = `
+		SELECT EXISTS(SELECT 1 FROM artists WHERE lower(name) = lower($1) AND id <> $2)
+		    OR EXISTS(SELECT 1 FROM artist_aliases WHERE lower(name) = lower($1))`
+
+	// queries получить псевдонимы исполнителей
+	getArtistAliasesQuery = `
+		SELECT artist_id, id, name, created_at
+		FROM artist_aliases
+		WHERE artist_id = ANY($1)
+		ORDER BY lower(name), id`
+
+	// insert добавить псевдоним исполнителя
+	addArtistAliasQuery = `INSERT INTO artist_aliases (artist_id, name) VALUES ($1, $2)`
+
+	// delete удалить псевдоним исполнителя
+	deleteArtistAliasQuery = `DELETE FROM artist_aliases WHERE id = $1 AND artist_id = $2`
+
+	// queries заблокировать исполнителей в порядке идентификаторов, чтобы избежать взаимоблокировок
+	lockArtistsQuery = `SELECT id FROM artists WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+
+	// queries найти песню исходного исполнителя, название которой уже есть у целевого
+	mergeDuplicateSongQuery = `
+		SELECT s.song_name
+		FROM songs s
+		JOIN songs t ON t.artist_id = $2 AND t.song_name = s.song_name AND t.deleted_at IS NULL
+		WHERE s.artist_id = $1 AND s.deleted_at IS NULL
+		LIMIT 1`
+
+	// update перенести песни исполнителя, включая песни в корзине, к другому исполнителю
+	moveArtistSongsQuery = `
+		UPDATE songs
+		SET artist_id = $2,
+			updated_at = NOW(),
+			version = version + 1
+		WHERE artist_id = $1`
+
+	// queries найти альбом исходного исполнителя, название которого уже есть у целевого
+	mergeDuplicateAlbumQuery = `
+		SELECT al.title
+		FROM albums al
+		JOIN albums t ON t.artist_id = $2 AND lower(t.title) = lower(al.title)
+		WHERE al.artist_id = $1
+		LIMIT 1`
+
+	// update перенести альбомы исполнителя к другому исполнителю
+	moveArtistAlbumsQuery = `
+		UPDATE albums
+		SET artist_id = $2,
+			updated_at = NOW()
+		WHERE artist_id = $1`
+
+	// update перенести псевдонимы исполнителя к другому исполнителю
+	moveArtistAliasesQuery = `UPDATE artist_aliases SET artist_id = $2 WHERE artist_id = $1`
+
+	// delete удалить исполнителя, сохранив его имя псевдонимом другого исполнителя
+	replaceArtistWithAliasQuery = `
+		WITH deleted AS (
+			DELETE FROM artists WHERE id = $1 RETURNING name
+		)
+		INSERT INTO artist_aliases (artist_id, name)
+		SELECT $2, name FROM deleted`
+
+	// update отметить изменение исполнителя
+	touchArtistQuery = `UPDATE artists SET updated_at = NOW() WHERE id = $1`
+
+	// update переименовать исполнителя
+	updateArtistQuery = `
+		UPDATE artists a
+		SET name = $1,
+			updated_at = NOW()
+		WHERE a.id = $2`
+
+	// update увеличить версию песен исполнителя, так как изменилось их представление
+	touchArtistSongsQuery = `
+		UPDATE songs
+		SET updated_at = NOW(),
+			version = version + 1
+		WHERE artist_id = $1`
+
+	// queries проверить, есть ли у исполнителя песни, включая песни в корзине
+	artistHasSongsQuery = `SELECT EXISTS(SELECT 1 FROM songs WHERE artist_id = $1)`
+
+	// queries проверить, есть ли у исполнителя альбомы
+	artistHasAlbumsQuery = `SELECT EXISTS(SELECT 1 FROM albums WHERE artist_id = $1)`
+
+	// delete удалить исполнителя
+	deleteArtistQuery = `DELETE FROM artists WHERE id = $1`
+)
+
+// GetArtists получает список исполнителей с количеством песен и псевдонимами, отфильтрованный по подстроке
+// имени или псевдонима.
+func (r *PostgresSongRepository) GetArtists(ctx context.Context, filter *models.ArtistFilter) (*models.ArtistsResponse, error) {
+	if filter.PageSize <= 0 {
+		filter.PageSize = 10
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+
+	var response *models.ArtistsResponse
+	err := r.read(ctx, func(db querier) error {
+		var err error
+		response, err = r.getArtists(ctx, db, filter)
+		return err
+	})
+	return response, err
+}
+
+// getArtists получает страницу исполнителей и их общее количество на одном экземпляре базы данных.
+func (r *PostgresSongRepository) getArtists(ctx context.Context, db querier, filter *models.ArtistFilter) (*models.ArtistsResponse, error) {
+	b := &queryBuilder{}
+	if filter.Search != "" {
+		pattern := b.arg(containsPattern(filter.Search))
+		b.where("(a.name ILIKE " + pattern +
+			" OR EXISTS (SELECT 1 FROM artist_aliases al WHERE al.artist_id = a.id AND al.name ILIKE " + pattern + "))")
+	}
+
+	var totalItems int
+	if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM artists a "+b.clause(), b.args...).Scan(&totalItems); err != nil {
+		return nil, mapError("failed to count artists", err)
+	}
+
+	totalPages := (totalItems + filter.PageSize - 1) / filter.PageSize
+	if totalItems > 0 && filter.Page > totalPages {
+		return nil, errors.NewNotFound(fmt.Sprintf("page %d does not exist, total pages: %d", filter.Page, totalPages), nil)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	query := "SELECT " + artistColumns + " FROM artists a " + b.clause() +
+		" ORDER BY lower(a.name), a.id LIMIT " + b.arg(filter.PageSize) + " OFFSET " + b.arg(offset)
+	rows, err := db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, mapError("failed to query artists", err)
+	}
+	defer rows.Close()
+
+	artists := []models.Artist{}
+	for rows.Next() {
+		var artist models.Artist
+		if err := scanArtist(rows, &artist); err != nil {
+			return nil, mapError("failed to scan artist", err)
+		}
+		artists = append(artists, artist)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, mapError("error occurred while iterating over artists", err)
+	}
+	rows.Close()
+
+	if err := loadAliases(ctx, db, artists); err != nil {
+		return nil, err
+	}
+
+	return &models.ArtistsResponse{
+		Artists:    artists,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalItems: totalItems,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetArtistByID получает исполнителя с псевдонимами по идентификатору.
+func (r *PostgresSongRepository) GetArtistByID(ctx context.Context, id int) (*models.Artist, error) {
+	var artist *models.Artist
+	err := r.read(ctx, func(db querier) error {
+		var err error
+		artist, err = getArtist(ctx, db, id)
+		return err
+	})
+	return artist, err
+}
+
+// CreateArtist создает исполнителя. Имя должно быть уникальным без учета регистра
+// и не совпадать с псевдонимами других исполнителей.
+func (r *PostgresSongRepository) CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	var created models.Artist
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.checkArtistName(ctx, artist.Name, 0); err != nil {
+			return err
+		}
+		if err := scanArtist(tx.q.QueryRowContext(ctx, addArtistQuery, artist.Name), &created); err != nil {
+			return mapError("failed to insert artist", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created.Aliases = []models.ArtistAlias{}
+	r.log(ctx).Debug("Artist inserted", zap.Int("artistId", created.ID))
+	return &created, nil
+}
+
+// UpdateArtist переименовывает исполнителя. Версии его песен увеличиваются в той же транзакции,
+// так как имя группы входит в их представление и ETag.
+func (r *PostgresSongRepository) UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	var updated *models.Artist
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockArtists(ctx, artist.ID); err != nil {
+			return err
+		}
+		if err := tx.checkArtistName(ctx, artist.Name, artist.ID); err != nil {
+			return err
+		}
+		if _, err := tx.q.ExecContext(ctx, updateArtistQuery, artist.Name, artist.ID); err != nil {
+			return mapError("failed to update artist", err)
+		}
+		if _, err := tx.q.ExecContext(ctx, touchArtistSongsQuery, artist.ID); err != nil {
+			return mapError("failed to update artist songs", err)
+		}
+
+		var err error
+		updated, err = getArtist(ctx, tx.q, artist.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Artist updated", zap.Int("artistId", updated.ID))
+	return updated, nil
+}
+
+// DeleteArtist удаляет исполнителя, у которого нет песен, в том числе в корзине, и альбомов.
+// Псевдонимы удаляются вместе с ним.
+func (r *PostgresSongRepository) DeleteArtist(ctx context.Context, id int) error {
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		var hasSongs bool
+		if err := tx.q.QueryRowContext(ctx, artistHasSongsQuery, id).Scan(&hasSongs); err != nil {
+			return mapError("failed to check artist songs", err)
+		}
+		if hasSongs {
+			return errors.NewConflict("artist has songs", nil)
+		}
+
+		var hasAlbums bool
+		if err := tx.q.QueryRowContext(ctx, artistHasAlbumsQuery, id).Scan(&hasAlbums); err != nil {
+			return mapError("failed to check artist albums", err)
+		}
+		if hasAlbums {
+			return errors.NewConflict("artist has albums", nil)
+		}
+
+		result, err := tx.q.ExecContext(ctx, deleteArtistQuery, id)
+		if err != nil {
+			return mapError("failed to delete artist", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return mapError("failed to retrieve affected rows after delete", err)
+		} else if rowsAffected == 0 {
+			return errors.NewNotFound("artist not found", nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.log(ctx).Debug("Artist deleted", zap.Int("artistId", id))
+	return nil
+}
+
+// AddArtistAlias добавляет исполнителю псевдоним. Псевдоним не должен совпадать без учета регистра
+// ни с именем какого-либо исполнителя, ни с другим псевдонимом.
+func (r *PostgresSongRepository) AddArtistAlias(ctx context.Context, artistID int, name string) (*models.Artist, error) {
+	var artist *models.Artist
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockArtists(ctx, artistID); err != nil {
+			return err
+		}
+		if err := tx.checkArtistName(ctx, name, 0); err != nil {
+			return err
+		}
+		if _, err := tx.q.ExecContext(ctx, addArtistAliasQuery, artistID, name); err != nil {
+			return mapError("failed to insert artist alias", err)
+		}
+		if _, err := tx.q.ExecContext(ctx, touchArtistQuery, artistID); err != nil {
+			return mapError("failed to update artist", err)
+		}
+
+		var err error
+		artist, err = getArtist(ctx, tx.q, artistID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Artist alias inserted", zap.Int("artistId", artistID))
+	return artist, nil
+}
+
+// DeleteArtistAlias удаляет псевдоним исполнителя.
+func (r *PostgresSongRepository) DeleteArtistAlias(ctx context.Context, artistID, aliasID int) error {
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		result, err := tx.q.ExecContext(ctx, deleteArtistAliasQuery, aliasID, artistID)
+		if err != nil {
+			return mapError("failed to delete artist alias", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return mapError("failed to retrieve affected rows after delete", err)
+		} else if rowsAffected == 0 {
+			return errors.NewNotFound("artist alias not found", nil)
+		}
+
+		if _, err := tx.q.ExecContext(ctx, touchArtistQuery, artistID); err != nil {
+			return mapError("failed to update artist", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.log(ctx).Debug("Artist alias deleted", zap.Int("artistId", artistID), zap.Int("aliasId", aliasID))
+	return nil
+}
+
+// MergeArtists объединяет исходного исполнителя с целевым в одной транзакции: песни, включая песни
+// в корзине, альбомы и псевдонимы переходят к целевому исполнителю, имя исходного становится псевдонимом,
+// а сам исходный исполнитель удаляется. Если у обоих исполнителей есть песня или альбом с одинаковым
+// названием, возвращается конфликт, так как после объединения они нарушили бы уникальность.
+// Объединение исполнителя с самим собой - ошибка валидации.
+func (r *PostgresSongRepository) MergeArtists(ctx context.Context, sourceID, targetID int) (*models.Artist, error) {
+	if sourceID == targetID {
+		// Иначе блокировка нашла бы одного исполнителя из двух и вернула бы "не найден"
+		return nil, errors.NewValidation("cannot merge an artist into itself", nil)
+	}
+
+	var merged *models.Artist
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockArtists(ctx, sourceID, targetID); err != nil {
+			return err
+		}
+
+		var songName string
+		err := tx.q.QueryRowContext(ctx, mergeDuplicateSongQuery, sourceID, targetID).Scan(&songName)
+		if err == nil {
+			return errors.NewConflict(fmt.Sprintf("both artists have a song named %q", songName), nil)
+		} else if err != sql.ErrNoRows {
+			return mapError("failed to check duplicate songs", err)
+		}
+
+		var albumTitle string
+		err = tx.q.QueryRowContext(ctx, mergeDuplicateAlbumQuery, sourceID, targetID).Scan(&albumTitle)
+		if err == nil {
+			return errors.NewConflict(fmt.Sprintf("both artists have an album titled %q", albumTitle), nil)
+		} else if err != sql.ErrNoRows {
+			return mapError("failed to check duplicate albums", err)
+		}
+
+		statements := []struct {
+			query   string
+			message string
+		}{
+			{moveArtistSongsQuery, "failed to move artist songs"},
+			{moveArtistAlbumsQuery, "failed to move artist albums"},
+			{moveArtistAliasesQuery, "failed to move artist aliases"},
+			{replaceArtistWithAliasQuery, "failed to delete merged artist"},
+		}
+		for _, stmt := range statements {
+			if _, err := tx.q.ExecContext(ctx, stmt.query, sourceID, targetID); err != nil {
+				return mapError(stmt.message, err)
+			}
+		}
+		if _, err := tx.q.ExecContext(ctx, touchArtistQuery, targetID); err != nil {
+			return mapError("failed to update artist", err)
+		}
+
+		merged, err = getArtist(ctx, tx.q, targetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Info("Artists merged", zap.Int("sourceId", sourceID), zap.Int("targetId", targetID))
+	return merged, nil
+}
+
+// resolveSongArtist находит исполнителя песни по имени группы или псевдониму без учета регистра
+// или создает его, заполняя идентификатор исполнителя и каноническое имя группы.
+func (r *PostgresSongRepository) resolveSongArtist(ctx context.Context, song *models.Song) error {
+	var artist models.Artist
+	err := scanArtist(r.q.QueryRowContext(ctx, getArtistByNameQuery, song.GroupName), &artist)
+	if err == sql.ErrNoRows {
+		err = scanArtist(r.q.QueryRowContext(ctx, addArtistIfMissingQuery, song.GroupName), &artist)
+		if err == sql.ErrNoRows {
+			// Исполнитель создан параллельным запросом между поиском и вставкой
+			err = scanArtist(r.q.QueryRowContext(ctx, getArtistByNameQuery, song.GroupName), &artist)
+		}
+	}
+	if err != nil {
+		return mapError("failed to resolve artist", err)
+	}
+
+	song.ArtistID = artist.ID
+	song.GroupName = artist.Name
+	return nil
+}
+
+// checkArtistName проверяет, что имя не занято другим исполнителем или псевдонимом.
+// Исполнитель с идентификатором artistID может сохранить собственное имя.
+func (r *PostgresSongRepository) checkArtistName(ctx context.Context, name string, artistID int) error {
+	var taken bool
+	if err := r.q.QueryRowContext(ctx, artistNameTakenQuery, name, artistID).Scan(&taken); err != nil {
+		return mapError("failed to check artist name", err)
+	}
+	if taken {
+		return errors.NewAlreadyExists("artist with this name or alias already exists", nil)
+	}
+	return nil
+}
+
+// lockArtists блокирует исполнителей до конца транзакции и проверяет, что все они существуют.
+func (r *PostgresSongRepository) lockArtists(ctx context.Context, ids ...int) error {
+	list := make([]int64, len(ids))
+	for i, id := range ids {
+		list[i] = int64(id)
+	}
+
+	rows, err := r.q.QueryContext(ctx, lockArtistsQuery, pq.Array(list))
+	if err != nil {
+		return mapError("failed to lock artists", err)
+	}
+	defer rows.Close()
+
+	var locked int
+	for rows.Next() {
+		locked++
+	}
+	if err := rows.Err(); err != nil {
+		return mapError("failed to lock artists", err)
+	}
+	if locked < len(ids) {
+		return errors.NewNotFound("artist not found", nil)
+	}
+	return nil
+}
+
+// getArtist получает исполнителя с псевдонимами.
+func getArtist(ctx context.Context, db querier, id int) (*models.Artist, error) {
+	artists := make([]models.Artist, 1)
+	err := scanArtist(db.QueryRowContext(ctx, getArtistByIDQuery, id), &artists[0])
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFound("artist not found", err)
+	} else if err != nil {
+		return nil, mapError("failed to get artist", err)
+	}
+
+	if err := loadAliases(ctx, db, artists); err != nil {
+		return nil, err
+	}
+	return &artists[0], nil
+}
+
+// loadAliases заполняет псевдонимы исполнителей одним запросом.
+func loadAliases(ctx context.Context, db querier, artists []models.Artist) error {
+	if len(artists) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(artists))
+	index := make(map[int]int, len(artists))
+	for i := range artists {
+		artists[i].Aliases = []models.ArtistAlias{}
+		ids[i] = int64(artists[i].ID)
+		index[artists[i].ID] = i
+	}
+
+	rows, err := db.QueryContext(ctx, getArtistAliasesQuery, pq.Array(ids))
+	if err != nil {
+		return mapError("failed to query artist aliases", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var artistID int
+		var alias models.ArtistAlias
+		if err := rows.Scan(&artistID, &alias.ID, &alias.Name, &alias.CreatedAt); err != nil {
+			return mapError("failed to scan artist alias", err)
+		}
+		i := index[artistID]
+		artists[i].Aliases = append(artists[i].Aliases, alias)
+	}
+	return mapError("error occurred while iterating over artist aliases", rows.Err())
+}
+
+// scanArtist считывает исполнителя вместе с количеством его песен.
+func scanArtist(row rowScanner, artist *models.Artist) error {
+	return row.Scan(&artist.ID, &artist.Name, &artist.CreatedAt, &artist.UpdatedAt, &artist.SongCount)
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// Коды ошибок PostgreSQL, требующие отдельной обработки.
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgNotNullViolation     = "23502"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgLockNotAvailable     = "55P03"
+	pgQueryCanceled        = "57014"
+	pgAdminShutdown        = "57P01"
+	pgCrashShutdown        = "57P02"
+	pgCannotConnectNow     = "57P03"
+
+	pgClassDataException        = "22"
+	pgClassConnectionException  = "08"
+	pgClassInsufficientResource = "53"
+)
+
+// Параметры повторного выполнения операций при конфликтах сериализации и взаимоблокировках.
+const (
+	maxRetryAttempts = 3
+	retryBaseDelay   = 20 * time.Millisecond
+)
+
+// mapError преобразует ошибку базы данных в доменную ошибку.
+// Ошибки, уже являющиеся *errors.Error, возвращаются без изменений.
+func mapError(message string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var appErr *errors.Error
+	if stderrors.As(err, &appErr) {
+		return err
+	}
+
+	if stderrors.Is(err, context.Canceled) {
+		return errors.NewCanceled("request canceled", err)
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return errors.NewTimeout("database query timed out", err)
+	}
+
+	var pqErr *pq.Error
+	if stderrors.As(err, &pqErr) {
+		return mapPQError(message, pqErr)
+	}
+
+	var netErr net.Error
+	if stderrors.Is(err, driver.ErrBadConn) || stderrors.As(err, &netErr) {
+		return errors.NewUnavailable("database is unavailable", err)
+	}
+
+	return errors.NewInternal(message, err)
+}
+
+// mapPQError преобразует ошибку PostgreSQL в доменную ошибку по ее коду.
+func mapPQError(message string, err *pq.Error) error {
+	switch err.Code {
+	case pgUniqueViolation:
+		return errors.NewAlreadyExists("resource already exists", err)
+	case pgForeignKeyViolation:
+		return errors.NewValidation("referenced resource does not exist", err)
+	case pgNotNullViolation, pgCheckViolation:
+		return validationError(err)
+	case pgQueryCanceled, pgLockNotAvailable:
+		return errors.NewTimeout("database query timed out", err)
+	case pgSerializationFailure, pgDeadlockDetected:
+		return errors.NewUnavailable("database conflict, please retry", err)
+	case pgAdminShutdown, pgCrashShutdown, pgCannotConnectNow:
+		return errors.NewUnavailable("database is unavailable", err)
+	}
+
+	switch string(err.Code.Class()) {
+	case pgClassDataException:
+		return validationError(err)
+	case pgClassConnectionException, pgClassInsufficientResource:
+		return errors.NewUnavailable("database is unavailable", err)
+	}
+
+	return errors.NewInternal(message, err)
+}
+
+// validationError формирует ошибку валидации для некорректного значения,
+// указывая колонку, если PostgreSQL ее сообщил.
+func validationError(err *pq.Error) error {
+	if err.Column != "" {
+		appErr := errors.NewValidationFields("invalid input value", []errors.FieldError{
+			{Field: err.Column, Message: err.Message},
+		})
+		appErr.Err = err
+		return appErr
+	}
+	return errors.NewValidation("invalid input value", err)
+}
+
+// isUnavailable проверяет, что ошибка вызвана недоступностью экземпляра базы данных.
+func isUnavailable(err error) bool {
+	var appErr *errors.Error
+	return stderrors.As(err, &appErr) && appErr.Type == errors.Unavailable
+}
+
+// isRetryable проверяет, можно ли повторить операцию после ошибки.
+func isRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !stderrors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == pgSerializationFailure || pqErr.Code == pgDeadlockDetected
+}
+
+// withRetry выполняет операцию, повторяя ее при конфликтах сериализации и взаимоблокировках
+// с экспоненциальной задержкой.
+// Внутри транзакции операция выполняется один раз: после ошибки PostgreSQL прерывает транзакцию,
+// поэтому повторяется транзакция целиком в WithTx.
+func (r *PostgresSongRepository) withRetry(ctx context.Context, op func() error) error {
+	if r.inTx {
+		return op()
+	}
+	for attempt := 1; ; attempt++ {
+		err := op()
+		if err == nil || attempt >= maxRetryAttempts || !isRetryable(err) {
+			return err
+		}
+
+		delay := retryBaseDelay << (attempt - 1)
+		delay += time.Duration(rand.Int63n(int64(delay)))
+		r.log(ctx).Warn("Retrying database operation",
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err))
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantType   errors.ErrorType
+		wantFields []errors.FieldError
+	}{
+		{name: "unique violation", err: &pq.Error{Code: pgUniqueViolation}, wantType: errors.AlreadyExists},
+		{name: "foreign key violation", err: &pq.Error{Code: pgForeignKeyViolation}, wantType: errors.Validation},
+		{
+			name:       "not null violation names the column",
+			err:        &pq.Error{Code: pgNotNullViolation, Column: "song_name", Message: "null value in column"},
+			wantType:   errors.Validation,
+			wantFields: []errors.FieldError{{Field: "song_name", Message: "null value in column"}},
+		},
+		{name: "data exception class", err: &pq.Error{Code: "22007"}, wantType: errors.Validation},
+		{name: "statement timeout", err: &pq.Error{Code: pgQueryCanceled}, wantType: errors.Timeout},
+		{name: "lock not available", err: &pq.Error{Code: pgLockNotAvailable}, wantType: errors.Timeout},
+		{name: "serialization failure", err: &pq.Error{Code: pgSerializationFailure}, wantType: errors.Unavailable},
+		{name: "admin shutdown", err: &pq.Error{Code: pgAdminShutdown}, wantType: errors.Unavailable},
+		{name: "connection exception class", err: &pq.Error{Code: "08006"}, wantType: errors.Unavailable},
+		{name: "unknown code", err: &pq.Error{Code: "XX000"}, wantType: errors.Internal},
+		{name: "wrapped pq error", err: fmt.Errorf("scan: %w", &pq.Error{Code: pgUniqueViolation}), wantType: errors.AlreadyExists},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, wantType: errors.Timeout},
+		{name: "canceled by client", err: fmt.Errorf("query: %w", context.Canceled), wantType: errors.Canceled},
+		{name: "bad connection", err: driver.ErrBadConn, wantType: errors.Unavailable},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: stderrors.New("connection refused")}, wantType: errors.Unavailable},
+		{name: "application error is kept", err: errors.NewNotFound("song not found", nil), wantType: errors.NotFound},
+		{name: "other error", err: stderrors.New("boom"), wantType: errors.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var appErr *errors.Error
+			if err := mapError("failed", tt.err); !stderrors.As(err, &appErr) {
+				t.Fatalf("mapError() = %v, want *errors.Error", err)
+			}
+			if appErr.Type != tt.wantType {
+				t.Errorf("type = %s, want %s", appErr.Type, tt.wantType)
+			}
+			if tt.wantFields != nil && fmt.Sprint(appErr.Fields) != fmt.Sprint(tt.wantFields) {
+				t.Errorf("fields = %v, want %v", appErr.Fields, tt.wantFields)
+			}
+			// Исходная ошибка драйвера сохраняется для логов
+			var pqErr *pq.Error
+			if stderrors.As(tt.err, &pqErr) && !stderrors.Is(appErr, pqErr) {
+				t.Error("mapped error does not wrap the PostgreSQL error")
+			}
+		})
+	}
+
+	if err := mapError("failed", nil); err != nil {
+		t.Errorf("mapError(nil) = %v, want nil", err)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	serialization := &pq.Error{Code: pgSerializationFailure}
+	deadlock := &pq.Error{Code: pgDeadlockDetected}
+	unique := &pq.Error{Code: pgUniqueViolation}
+
+	tests := []struct {
+		name      string
+		errs      []error // ошибки последовательных попыток
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "success", errs: []error{nil}, wantCalls: 1},
+		{name: "retried serialization failure", errs: []error{serialization, nil}, wantCalls: 2},
+		{name: "retried deadlock", errs: []error{deadlock, serialization, nil}, wantCalls: 3},
+		{name: "attempts are limited", errs: []error{serialization, serialization, serialization, nil}, wantCalls: maxRetryAttempts, wantErr: serialization},
+		{name: "other errors are not retried", errs: []error{unique, nil}, wantCalls: 1, wantErr: unique},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &PostgresSongRepository{logger: zap.NewNop()}
+			calls := 0
+			err := repo.withRetry(context.Background(), func() error {
+				calls++
+				return tt.errs[calls-1]
+			})
+			if calls != tt.wantCalls || err != tt.wantErr {
+				t.Errorf("calls = %d, err = %v; want %d, %v", calls, err, tt.wantCalls, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithRetryStopsOnCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	repo := &PostgresSongRepository{logger: zap.NewNop()}
+	calls := 0
+	serialization := &pq.Error{Code: pgSerializationFailure}
+	err := repo.withRetry(ctx, func() error {
+		calls++
+		return serialization
+	})
+	if calls != 1 || err != serialization {
+		t.Errorf("calls = %d, err = %v; want one call and the last error", calls, err)
+	}
+}
@@ -0,0 +1,582 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// positionStep - расстояние между ключами порядка соседних элементов плейлиста при добавлении в конец
+// и перенумерации. Промежутки позволяют вставлять и перемещать элементы, не меняя ключи остальных.
+const positionStep = 1024
+
+// playlistColumns - столбцы плейлиста в порядке, ожидаемом scanPlaylist, включая количество элементов.
+const playlistColumns = `p.id, p.owner, p.name, p.visibility, COALESCE(p.share_token, ''), p.created_at, p.updated_at,
+		(SELECT COUNT(*) FROM playlist_entries e WHERE e.playlist_id = p.id)`
+
+// playlistEntrySource - источник строк элементов плейлиста вместе с песнями и их исполнителями.
+const playlistEntrySource = `playlist_entries e
+		JOIN songs s ON s.id = e.song_id
+		JOIN artists a ON a.id = s.artist_id`
+
+// SQL Queries
+const (
+	// insert добавить плейлист
+	addPlaylistQuery = `
+		INSERT INTO playlists AS p (owner, name, visibility, share_token)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING ` + playlistColumns
+
+	// queries получить плейлист по id
+	getPlaylistByIDQuery = `
+		SELECT ` + playlistColumns + `
+		FROM playlists p
+		WHERE p.id = $1`
+
+	// queries подсчитать плейлисты пользователя
+	countPlaylistsQuery = `SELECT COUNT(*) FROM playlists WHERE owner = $1`
+
+	// queries получить страницу плейлистов пользователя
+	getPlaylistsQuery = `
+		SELECT ` + playlistColumns + `
+		FROM playlists p
+		WHERE p.owner = $1
+		ORDER BY lower(p.name), p.id
+		LIMIT $2 OFFSET $3`
+
+	// update изменить название и видимость плейлиста
+	updatePlaylistQuery = `
+		UPDATE playlists p
+		SET name = $1,
+			visibility = $2,
+			share_token = NULLIF($3, ''),
+			updated_at = NOW()
+		WHERE p.id = $4
+		RETURNING ` + playlistColumns
+
+	// delete удалить плейлист вместе с его элементами
+	deletePlaylistQuery = `DELETE FROM playlists WHERE id = $1`
+
+	// queries заблокировать плейлист, чтобы изменения порядка элементов выполнялись последовательно
+	lockPlaylistQuery = `SELECT id FROM playlists WHERE id = $1 FOR UPDATE`
+
+	// update отметить изменение плейлиста
+	touchPlaylistQuery = `UPDATE playlists SET updated_at = NOW() WHERE id = $1`
+
+	// queries подсчитать элементы плейлиста
+	countPlaylistEntriesQuery = `SELECT COUNT(*) FROM playlist_entries WHERE playlist_id = $1`
+
+	// queries получить страницу элементов плейлиста по порядку, включая песни в корзине
+	getPlaylistEntriesQuery = `
+		SELECT e.id, ROW_NUMBER() OVER (ORDER BY e.position), e.song_id, e.added_at, s.deleted_at IS NULL,
+			` + songColumns + `
+		FROM ` + playlistEntrySource + `
+		WHERE e.playlist_id = $1
+		ORDER BY e.position
+		LIMIT $2 OFFSET $3`
+
+	// queries получить элемент плейлиста с его порядковым номером
+	getPlaylistEntryQuery = `
+		SELECT e.id,
+			(SELECT COUNT(*) FROM playlist_entries o WHERE o.playlist_id = e.playlist_id AND o.position <= e.position),
+			e.song_id, e.added_at, s.deleted_at IS NULL,
+			` + songColumns + `
+		FROM ` + playlistEntrySource + `
+		WHERE e.playlist_id = $1 AND e.id = $2`
+
+	// queries получить ключи двух соседних элементов, между которыми вставляется элемент, без учета самого элемента
+	neighbourPositionsQuery = `
+		SELECT position
+		FROM playlist_entries
+		WHERE playlist_id = $1 AND id <> $2
+		ORDER BY position
+		LIMIT 2 OFFSET $3`
+
+	// queries получить ключ последнего элемента без учета перемещаемого элемента
+	lastPositionQuery = `SELECT COALESCE(MAX(position), 0) FROM playlist_entries WHERE playlist_id = $1 AND id <> $2`
+
+	// update перенумеровать ключи элементов плейлиста с равными промежутками, сохранив порядок
+	renumberPlaylistQuery = `
+		UPDATE playlist_entries e
+		SET position = r.n * $2
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY position) AS n
+			FROM playlist_entries
+			WHERE playlist_id = $1
+		) r
+		WHERE e.id = r.id`
+
+	// insert добавить элемент плейлиста
+	addPlaylistEntryQuery = `
+		INSERT INTO playlist_entries (playlist_id, song_id, position)
+		VALUES ($1, $2, $3)
+		RETURNING id`
+
+	// update изменить ключ порядка элемента плейлиста
+	movePlaylistEntryQuery = `UPDATE playlist_entries SET position = $3 WHERE playlist_id = $1 AND id = $2`
+
+	// delete удалить элемент плейлиста
+	removePlaylistEntryQuery = `DELETE FROM playlist_entries WHERE playlist_id = $1 AND id = $2`
+
+	// queries найти активную песню по точному совпадению исполнителя или его псевдонима и названия без учета регистра
+	matchSongExactQuery = `
+		SELECT s.id
+		FROM songs s
+		WHERE s.deleted_at IS NULL
+		  AND lower(s.song_name) = lower($2)
+		  AND s.artist_id IN (
+			SELECT id FROM artists WHERE lower(name) = lower($1)
+			UNION
+			SELECT artist_id FROM artist_aliases WHERE lower(name) = lower($1)
+		  )
+		ORDER BY s.id
+		LIMIT 1`
+
+	// queries найти наиболее похожую активную песню по триграммам исполнителя или его псевдонима и названия.
+	// Оператор % использует порог pg_trgm.similarity_threshold и триграммные индексы
+	matchSongFuzzyQuery = `
+		SELECT s.id
+		FROM songs s
+		JOIN (
+			SELECT id AS artist_id, similarity(name, $1) AS score FROM artists WHERE name % $1
+			UNION ALL
+			SELECT artist_id, similarity(name, $1) FROM artist_aliases WHERE name % $1
+		) a ON a.artist_id = s.artist_id
+		WHERE s.deleted_at IS NULL
+		  AND s.song_name % $2
+		ORDER BY a.score + similarity(s.song_name, $2) DESC, s.id
+		LIMIT 1`
+)
+
+// playlistRepository - репозиторий в роли repository.PlaylistRepository. Отдельный тип нужен,
+// так как его WithTx передает в fn репозиторий плейлистов, а не репозиторий песен.
+type playlistRepository struct {
+	*PostgresSongRepository
+}
+
+var _ repository.PlaylistRepository = playlistRepository{}
+
+// Playlists возвращает репозиторий плейлистов, работающий с той же базой данных и репликами.
+func (r *PostgresSongRepository) Playlists() repository.PlaylistRepository {
+	return playlistRepository{r}
+}
+
+// WithTx выполняет fn в транзакции на основной базе данных.
+func (r playlistRepository) WithTx(ctx context.Context, fn func(repo repository.PlaylistRepository) error) error {
+	return r.transaction(ctx, func(tx *PostgresSongRepository) error { return fn(playlistRepository{tx}) })
+}
+
+// GetPlaylists получает страницу плейлистов пользователя.
+func (r *PostgresSongRepository) GetPlaylists(ctx context.Context, owner string, page, pageSize int) (*models.PlaylistsResponse, error) {
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	var response *models.PlaylistsResponse
+	err := r.read(ctx, func(db querier) error {
+		var totalItems int
+		if err := db.QueryRowContext(ctx, countPlaylistsQuery, owner).Scan(&totalItems); err != nil {
+			return mapError("failed to count playlists", err)
+		}
+
+		totalPages := (totalItems + pageSize - 1) / pageSize
+		if totalItems > 0 && page > totalPages {
+			return errors.NewNotFound(fmt.Sprintf("page %d does not exist, total pages: %d", page, totalPages), nil)
+		}
+
+		rows, err := db.QueryContext(ctx, getPlaylistsQuery, owner, pageSize, (page-1)*pageSize)
+		if err != nil {
+			return mapError("failed to query playlists", err)
+		}
+		defer rows.Close()
+
+		playlists := []models.Playlist{}
+		for rows.Next() {
+			var playlist models.Playlist
+			if err := scanPlaylist(rows, &playlist); err != nil {
+				return mapError("failed to scan playlist", err)
+			}
+			playlists = append(playlists, playlist)
+		}
+		if err := rows.Err(); err != nil {
+			return mapError("error occurred while iterating over playlists", err)
+		}
+
+		response = &models.PlaylistsResponse{
+			Playlists:  playlists,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalItems: totalItems,
+			TotalPages: totalPages,
+		}
+		return nil
+	})
+	return response, err
+}
+
+// GetPlaylistByID получает плейлист по идентификатору.
+func (r *PostgresSongRepository) GetPlaylistByID(ctx context.Context, id int) (*models.Playlist, error) {
+	var playlist models.Playlist
+	err := r.read(ctx, func(db querier) error {
+		err := scanPlaylist(db.QueryRowContext(ctx, getPlaylistByIDQuery, id), &playlist)
+		if err == sql.ErrNoRows {
+			return errors.NewNotFound("playlist not found", err)
+		}
+		return mapError("failed to get playlist", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &playlist, nil
+}
+
+// GetPlaylistEntries получает страницу элементов плейлиста по порядку. Элементы с песнями в корзине
+// возвращаются недоступными без данных песни, чтобы порядковые номера остальных элементов не менялись.
+func (r *PostgresSongRepository) GetPlaylistEntries(ctx context.Context, playlistID, page, pageSize int) (*models.PlaylistEntries, error) {
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	if page <= 0 {
+		page = 1
+	}
+
+	var response *models.PlaylistEntries
+	err := r.read(ctx, func(db querier) error {
+		var totalItems int
+		if err := db.QueryRowContext(ctx, countPlaylistEntriesQuery, playlistID).Scan(&totalItems); err != nil {
+			return mapError("failed to count playlist entries", err)
+		}
+
+		totalPages := (totalItems + pageSize - 1) / pageSize
+		if totalItems > 0 && page > totalPages {
+			return errors.NewNotFound(fmt.Sprintf("page %d does not exist, total pages: %d", page, totalPages), nil)
+		}
+
+		rows, err := db.QueryContext(ctx, getPlaylistEntriesQuery, playlistID, pageSize, (page-1)*pageSize)
+		if err != nil {
+			return mapError("failed to query playlist entries", err)
+		}
+		defer rows.Close()
+
+		entries := []models.PlaylistEntry{}
+		for rows.Next() {
+			var entry models.PlaylistEntry
+			if err := scanPlaylistEntry(rows, &entry); err != nil {
+				return mapError("failed to scan playlist entry", err)
+			}
+			entries = append(entries, entry)
+		}
+		if err := rows.Err(); err != nil {
+			return mapError("error occurred while iterating over playlist entries", err)
+		}
+
+		response = &models.PlaylistEntries{
+			Entries:    entries,
+			Page:       page,
+			PageSize:   pageSize,
+			TotalItems: totalItems,
+			TotalPages: totalPages,
+		}
+		return nil
+	})
+	return response, err
+}
+
+// CreatePlaylist создает плейлист.
+func (r *PostgresSongRepository) CreatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error) {
+	var created models.Playlist
+	err := r.withRetry(ctx, func() error {
+		return scanPlaylist(r.q.QueryRowContext(ctx, addPlaylistQuery,
+			playlist.Owner, playlist.Name, playlist.Visibility, playlist.ShareToken), &created)
+	})
+	if err != nil {
+		return nil, mapError("failed to insert playlist", err)
+	}
+
+	r.log(ctx).Debug("Playlist inserted", zap.Int("playlistId", created.ID))
+	return &created, nil
+}
+
+// UpdatePlaylist изменяет название, видимость и токен ссылки плейлиста.
+func (r *PostgresSongRepository) UpdatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error) {
+	var updated models.Playlist
+	err := r.withRetry(ctx, func() error {
+		return scanPlaylist(r.q.QueryRowContext(ctx, updatePlaylistQuery,
+			playlist.Name, playlist.Visibility, playlist.ShareToken, playlist.ID), &updated)
+	})
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFound("playlist not found", err)
+	} else if err != nil {
+		return nil, mapError("failed to update playlist", err)
+	}
+
+	r.log(ctx).Debug("Playlist updated", zap.Int("playlistId", updated.ID))
+	return &updated, nil
+}
+
+// DeletePlaylist удаляет плейлист и его элементы. Сами песни не удаляются.
+func (r *PostgresSongRepository) DeletePlaylist(ctx context.Context, id int) error {
+	var result sql.Result
+	err := r.withRetry(ctx, func() (err error) {
+		result, err = r.q.ExecContext(ctx, deletePlaylistQuery, id)
+		return err
+	})
+	if err != nil {
+		return mapError("failed to delete playlist", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return mapError("failed to retrieve affected rows after delete", err)
+	} else if rowsAffected == 0 {
+		return errors.NewNotFound("playlist not found", nil)
+	}
+
+	r.log(ctx).Debug("Playlist deleted", zap.Int("playlistId", id))
+	return nil
+}
+
+// AddPlaylistEntry добавляет песню в плейлист на порядковый номер position или в конец, если position
+// равен нулю или больше количества элементов. Песня может входить в плейлист несколько раз.
+func (r *PostgresSongRepository) AddPlaylistEntry(ctx context.Context, playlistID, songID, position int) (*models.PlaylistEntry, error) {
+	var entry *models.PlaylistEntry
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockPlaylist(ctx, playlistID); err != nil {
+			return err
+		}
+
+		var active bool
+		if err := tx.q.QueryRowContext(ctx, songActiveQuery, songID).Scan(&active); err != nil {
+			return mapError("failed to check song", err)
+		}
+		if !active {
+			return errors.NewNotFound("song not found", nil)
+		}
+
+		key, err := tx.entryPosition(ctx, playlistID, 0, position)
+		if err != nil {
+			return err
+		}
+
+		var entryID int
+		if err := tx.q.QueryRowContext(ctx, addPlaylistEntryQuery, playlistID, songID, key).Scan(&entryID); err != nil {
+			return mapError("failed to insert playlist entry", err)
+		}
+		if _, err := tx.q.ExecContext(ctx, touchPlaylistQuery, playlistID); err != nil {
+			return mapError("failed to update playlist", err)
+		}
+
+		entry, err = tx.getPlaylistEntry(ctx, playlistID, entryID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Playlist entry inserted", zap.Int("playlistId", playlistID), zap.Int("entryId", entry.ID))
+	return entry, nil
+}
+
+// MovePlaylistEntry перемещает элемент плейлиста на порядковый номер position. Меняется только ключ
+// порядка перемещаемого элемента, если между соседями есть свободный промежуток.
+func (r *PostgresSongRepository) MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) (*models.PlaylistEntry, error) {
+	var entry *models.PlaylistEntry
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.lockPlaylist(ctx, playlistID); err != nil {
+			return err
+		}
+		if _, err := tx.getPlaylistEntry(ctx, playlistID, entryID); err != nil {
+			return err
+		}
+
+		key, err := tx.entryPosition(ctx, playlistID, entryID, position)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.q.ExecContext(ctx, movePlaylistEntryQuery, playlistID, entryID, key); err != nil {
+			return mapError("failed to move playlist entry", err)
+		}
+		if _, err := tx.q.ExecContext(ctx, touchPlaylistQuery, playlistID); err != nil {
+			return mapError("failed to update playlist", err)
+		}
+
+		entry, err = tx.getPlaylistEntry(ctx, playlistID, entryID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Playlist entry moved", zap.Int("playlistId", playlistID), zap.Int("entryId", entryID))
+	return entry, nil
+}
+
+// RemovePlaylistEntry удаляет элемент плейлиста. Ключи порядка остальных элементов не меняются.
+func (r *PostgresSongRepository) RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error {
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		result, err := tx.q.ExecContext(ctx, removePlaylistEntryQuery, playlistID, entryID)
+		if err != nil {
+			return mapError("failed to delete playlist entry", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err != nil {
+			return mapError("failed to retrieve affected rows after delete", err)
+		} else if rowsAffected == 0 {
+			return errors.NewNotFound("playlist entry not found", nil)
+		}
+
+		if _, err := tx.q.ExecContext(ctx, touchPlaylistQuery, playlistID); err != nil {
+			return mapError("failed to update playlist", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.log(ctx).Debug("Playlist entry deleted", zap.Int("playlistId", playlistID), zap.Int("entryId", entryID))
+	return nil
+}
+
+// MatchSong находит активную песню для трека импортируемого плейлиста: сначала по точному совпадению
+// исполнителя или его псевдонима и названия без учета регистра, затем по наибольшему сходству триграмм.
+// Возвращает nil, если подходящей песни нет.
+func (r *PostgresSongRepository) MatchSong(ctx context.Context, artist, title string) (*models.SongMatch, error) {
+	var match *models.SongMatch
+	err := r.read(ctx, func(db querier) error {
+		for _, query := range []struct {
+			sql   string
+			exact bool
+		}{{matchSongExactQuery, true}, {matchSongFuzzyQuery, false}} {
+			var songID int
+			err := db.QueryRowContext(ctx, query.sql, artist, title).Scan(&songID)
+			if err == sql.ErrNoRows {
+				continue
+			} else if err != nil {
+				return mapError("failed to match song", err)
+			}
+			match = &models.SongMatch{SongID: songID, Exact: query.exact}
+			return nil
+		}
+		return nil
+	})
+	return match, err
+}
+
+// entryPosition выбирает ключ порядка для элемента на порядковом номере position без учета самого
+// элемента entryID (0 - новый элемент). Ключ берется из середины промежутка между соседями; если
+// промежутка не осталось, ключи плейлиста перенумеровываются и выбор повторяется.
+func (r *PostgresSongRepository) entryPosition(ctx context.Context, playlistID, entryID, position int) (int64, error) {
+	for renumbered := false; ; renumbered = true {
+		prev, next, err := r.neighbourPositions(ctx, playlistID, entryID, position)
+		if err != nil {
+			return 0, err
+		}
+		if next == 0 {
+			return prev + positionStep, nil
+		}
+		if next-prev > 1 {
+			return prev + (next-prev)/2, nil
+		}
+		if renumbered {
+			return 0, errors.NewInternal("failed to allocate playlist position", nil)
+		}
+
+		if _, err := r.q.ExecContext(ctx, renumberPlaylistQuery, playlistID, positionStep); err != nil {
+			return 0, mapError("failed to renumber playlist", err)
+		}
+		r.log(ctx).Debug("Playlist renumbered", zap.Int("playlistId", playlistID))
+	}
+}
+
+// neighbourPositions возвращает ключи элементов, между которыми окажется элемент на порядковом номере
+// position, без учета самого элемента entryID. Нулевой next означает вставку в конец плейлиста.
+func (r *PostgresSongRepository) neighbourPositions(ctx context.Context, playlistID, entryID, position int) (prev, next int64, err error) {
+	if position > 0 {
+		rows, err := r.q.QueryContext(ctx, neighbourPositionsQuery, playlistID, entryID, max(position-2, 0))
+		if err != nil {
+			return 0, 0, mapError("failed to get playlist positions", err)
+		}
+		defer rows.Close()
+
+		var keys []int64
+		for rows.Next() {
+			var key int64
+			if err := rows.Scan(&key); err != nil {
+				return 0, 0, mapError("failed to scan playlist position", err)
+			}
+			keys = append(keys, key)
+		}
+		if err := rows.Err(); err != nil {
+			return 0, 0, mapError("error occurred while iterating over playlist positions", err)
+		}
+
+		switch {
+		case position == 1 && len(keys) > 0:
+			return 0, keys[0], nil
+		case position > 1 && len(keys) == 2:
+			return keys[0], keys[1], nil
+		case position > 1 && len(keys) == 1:
+			return keys[0], 0, nil
+		}
+	}
+
+	// Порядковый номер не задан или больше количества элементов: элемент добавляется в конец
+	if err := r.q.QueryRowContext(ctx, lastPositionQuery, playlistID, entryID).Scan(&prev); err != nil {
+		return 0, 0, mapError("failed to get last playlist position", err)
+	}
+	return prev, 0, nil
+}
+
+// getPlaylistEntry получает элемент плейлиста с его порядковым номером.
+func (r *PostgresSongRepository) getPlaylistEntry(ctx context.Context, playlistID, entryID int) (*models.PlaylistEntry, error) {
+	var entry models.PlaylistEntry
+	err := scanPlaylistEntry(r.q.QueryRowContext(ctx, getPlaylistEntryQuery, playlistID, entryID), &entry)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFound("playlist entry not found", err)
+	} else if err != nil {
+		return nil, mapError("failed to get playlist entry", err)
+	}
+	return &entry, nil
+}
+
+// lockPlaylist блокирует плейлист до конца транзакции и проверяет, что он существует.
+func (r *PostgresSongRepository) lockPlaylist(ctx context.Context, id int) error {
+	err := r.q.QueryRowContext(ctx, lockPlaylistQuery, id).Scan(&id)
+	if err == sql.ErrNoRows {
+		return errors.NewNotFound("playlist not found", err)
+	}
+	return mapError("failed to lock playlist", err)
+}
+
+// scanPlaylist считывает плейлист вместе с количеством элементов.
+func scanPlaylist(row rowScanner, playlist *models.Playlist) error {
+	return row.Scan(
+		&playlist.ID,
+		&playlist.Owner,
+		&playlist.Name,
+		&playlist.Visibility,
+		&playlist.ShareToken,
+		&playlist.CreatedAt,
+		&playlist.UpdatedAt,
+		&playlist.SongCount,
+	)
+}
+
+// scanPlaylistEntry считывает элемент плейлиста. Данные песни в корзине не возвращаются.
+func scanPlaylistEntry(row rowScanner, entry *models.PlaylistEntry) error {
+	var song models.Song
+	prefix := []interface{}{&entry.ID, &entry.Position, &entry.SongID, &entry.AddedAt, &entry.Available}
+	if err := scanSong(prefixScanner{row: row, prefix: prefix}, &song); err != nil {
+		return err
+	}
+	if entry.Available {
+		entry.Song = &song
+	}
+	return nil
+}
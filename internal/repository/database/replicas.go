@@ -0,0 +1,124 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// replicaPingTimeout ограничивает время проверки доступности одной реплики.
+const replicaPingTimeout = 2 * time.Second
+
+// replica - реплика базы данных с признаком доступности.
+type replica struct {
+	name    string
+	db      *tracedDB
+	healthy atomic.Bool
+}
+
+// ReplicaSet распределяет чтения между репликами по кругу и периодически проверяет их доступность.
+// Недоступные реплики исключаются из распределения до следующей успешной проверки.
+type ReplicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	interval time.Duration
+	logger   *zap.Logger
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewReplicaSet создает набор реплик. До первой проверки все реплики считаются доступными.
+func NewReplicaSet(dbs []*sql.DB, logger *zap.Logger, interval time.Duration) *ReplicaSet {
+	s := &ReplicaSet{
+		interval: interval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for i, db := range dbs {
+		rep := &replica{name: fmt.Sprintf("replica-%d", i+1), db: newTracedDB(db)}
+		rep.healthy.Store(true)
+		s.replicas = append(s.replicas, rep)
+	}
+	return s
+}
+
+// Start запускает фоновую проверку доступности реплик.
+func (s *ReplicaSet) Start() {
+	go s.run()
+}
+
+// Stop останавливает проверку доступности и дожидается ее завершения или отмены ctx.
+func (s *ReplicaSet) Stop(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *ReplicaSet) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.checkAll()
+	for {
+		select {
+		case <-ticker.C:
+			s.checkAll()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// checkAll проверяет доступность всех реплик.
+func (s *ReplicaSet) checkAll() {
+	for _, rep := range s.replicas {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+		err := rep.db.PingContext(ctx)
+		cancel()
+
+		if err != nil {
+			s.markDown(rep, err)
+			continue
+		}
+		if !rep.healthy.Swap(true) {
+			s.logger.Info("Database replica is available again", zap.String("replica", rep.name))
+		}
+	}
+}
+
+// markDown исключает реплику из распределения чтений до следующей успешной проверки.
+func (s *ReplicaSet) markDown(rep *replica, err error) {
+	if rep.healthy.Swap(false) {
+		s.logger.Warn("Database replica is unavailable, reading from primary",
+			zap.String("replica", rep.name), zap.Error(err))
+	}
+}
+
+// pick возвращает следующую доступную реплику по кругу или nil, если доступных реплик нет.
+func (s *ReplicaSet) pick() *replica {
+	if s == nil || len(s.replicas) == 0 {
+		return nil
+	}
+	start := s.next.Add(1)
+	for i := 0; i < len(s.replicas); i++ {
+		rep := s.replicas[(start+uint64(i))%uint64(len(s.replicas))]
+		if rep.healthy.Load() {
+			return rep
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	stderrors "errors"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// newTestReplicaSet создает набор из n реплик без подключений: тесты сравнивают только экземпляры.
+func newTestReplicaSet(n int) *ReplicaSet {
+	dbs := make([]*sql.DB, n)
+	for i := range dbs {
+		dbs[i] = new(sql.DB)
+	}
+	return NewReplicaSet(dbs, zap.NewNop(), 0)
+}
+
+func TestReplicaSetPickRoundRobin(t *testing.T) {
+	s := newTestReplicaSet(3)
+
+	seen := make(map[*replica]int)
+	for i := 0; i < 6; i++ {
+		seen[s.pick()]++
+	}
+	if len(seen) != 3 {
+		t.Fatalf("picked %d distinct replicas, want 3", len(seen))
+	}
+	for rep, n := range seen {
+		if n != 2 {
+			t.Errorf("%s picked %d times, want 2", rep.name, n)
+		}
+	}
+}
+
+func TestReplicaSetMarkDown(t *testing.T) {
+	s := newTestReplicaSet(2)
+	down := s.replicas[0]
+	s.markDown(down, stderrors.New("connection refused"))
+
+	for i := 0; i < 4; i++ {
+		if rep := s.pick(); rep != s.replicas[1] {
+			t.Fatalf("pick() = %v, want the healthy replica", rep)
+		}
+	}
+
+	s.markDown(s.replicas[1], stderrors.New("connection refused"))
+	if rep := s.pick(); rep != nil {
+		t.Errorf("pick() = %s, want nil when every replica is down", rep.name)
+	}
+
+	var empty *ReplicaSet
+	if rep := empty.pick(); rep != nil {
+		t.Error("nil replica set returned a replica")
+	}
+}
+
+func TestRepositoryReadFallsBackToPrimary(t *testing.T) {
+	primary := newTracedDB(new(sql.DB))
+	unavailable := errors.NewUnavailable("database is unavailable", nil)
+
+	tests := []struct {
+		name        string
+		ctx         func() context.Context
+		inTx        bool
+		replicaErr  error
+		wantDBs     int
+		wantPrimary bool
+		wantHealthy bool
+	}{
+		{
+			name:        "healthy replica serves the read",
+			ctx:         context.Background,
+			wantDBs:     1,
+			wantHealthy: true,
+		},
+		{
+			name:        "unavailable replica is marked down",
+			ctx:         context.Background,
+			replicaErr:  unavailable,
+			wantDBs:     2,
+			wantPrimary: true,
+		},
+		{
+			name:        "other errors are returned as is",
+			ctx:         context.Background,
+			replicaErr:  errors.NewNotFound("song not found", nil),
+			wantDBs:     1,
+			wantHealthy: true,
+		},
+		{
+			name: "read your writes goes to primary",
+			ctx: func() context.Context {
+				return repository.WithReadYourWrites(context.Background())
+			},
+			wantDBs:     1,
+			wantPrimary: true,
+			wantHealthy: true,
+		},
+		{
+			name:        "transaction reads stay on primary",
+			ctx:         context.Background,
+			inTx:        true,
+			wantDBs:     1,
+			wantPrimary: true,
+			wantHealthy: true,
+		},
+		{
+			name: "canceled read is not retried",
+			ctx: func() context.Context {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx
+			},
+			replicaErr:  unavailable,
+			wantDBs:     1,
+			wantHealthy: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replicas := newTestReplicaSet(1)
+			repo := &PostgresSongRepository{db: primary, q: primary, inTx: tt.inTx, replicas: replicas, logger: zap.NewNop()}
+
+			var used []querier
+			err := repo.read(tt.ctx(), func(db querier) error {
+				used = append(used, db)
+				if db != primary {
+					return tt.replicaErr
+				}
+				return nil
+			})
+
+			if len(used) != tt.wantDBs {
+				t.Fatalf("read used %d databases, want %d", len(used), tt.wantDBs)
+			}
+			if last := used[len(used)-1]; (last == primary) != tt.wantPrimary {
+				t.Errorf("read served by primary = %v, want %v", last == primary, tt.wantPrimary)
+			}
+			if tt.wantPrimary && err != nil {
+				t.Errorf("read() error = %v, want nil from primary", err)
+			}
+			if !tt.wantPrimary && err != tt.replicaErr {
+				t.Errorf("read() error = %v, want %v", err, tt.replicaErr)
+			}
+			if healthy := replicas.replicas[0].healthy.Load(); healthy != tt.wantHealthy {
+				t.Errorf("replica healthy = %v, want %v", healthy, tt.wantHealthy)
+			}
+		})
+	}
+}
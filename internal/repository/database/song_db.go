@@ -3,123 +3,222 @@ package database
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
 	"github.com/ZnNr/songs-library/internal/models"
 	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+	"time"
 )
 
 // SQL Queries
 const (
 	addSongQuery = `
-	INSERT INTO songs (group_name, song_name, release_date, text, link)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, group_name, song_name, release_date, text, link, created_at, updated_at`
-
-	getAllSongsQuery = `
-		SELECT id, group_name, song_name, release_date, text, link, created_at, updated_at
-		FROM songs
-		WHERE ($1 = '' OR group_name ILIKE '%' || $1 || '%')
-		AND ($2 = '' OR song_name ILIKE '%' || $2 || '%')
-		AND ($3::timestamp IS NULL OR release_date >= $3)
-		AND ($4::timestamp IS NULL OR release_date <= $4)
-		AND ($5 = '' OR text ILIKE '%' || $5 || '%')
-		AND ($6 = '' OR link ILIKE '%' || $6 || '%')
-		ORDER BY created_at DESC
-		LIMIT $7 OFFSET $8`
-
-	// queries счетчик количества песен с фильтрами
-	countSongsQuery = `
-		SELECT COUNT(*)
-		FROM songs
-		WHERE ($1 = '' OR group_name ILIKE '%' || $1 || '%')
-		AND ($2 = '' OR song_name ILIKE '%' || $2 || '%')
-		AND ($3::timestamp IS NULL OR release_date >= $3)
-		AND ($4::timestamp IS NULL OR release_date <= $4)
-		AND ($5 = '' OR text ILIKE '%' || $5 || '%')
-		AND ($6 = '' OR link ILIKE '%' || $6 || '%')`
+		WITH s AS (
+			INSERT INTO songs (artist_id, song_name, release_date, release_date_precision, text, link)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING *
+		)
+		SELECT ` + songColumns + `
+		FROM s JOIN artists a ON a.id = s.artist_id`
 
 	// queries получить песню по id
 	getSongByIDQuery = `
-		SELECT id, group_name, song_name, release_date, text, link, created_at, updated_at
-		FROM songs
-		WHERE id = $1`
+		SELECT ` + songColumns + `
+		FROM ` + songsFrom + `
+		WHERE s.id = $1 AND s.deleted_at IS NULL`
 
 	// update обновить песню
 	updateSongQuery = `
-		UPDATE songs
-		SET group_name = $1, 
-			song_name = $2, 
+		UPDATE songs s
+		SET artist_id = $1,
+			song_name = $2,
 			release_date = $3,
-			text = $4,
-			link = $5,
-			updated_at = NOW()
-		WHERE id = $6
-		RETURNING id, group_name, song_name, release_date, text, link, created_at, updated_at`
+			release_date_precision = $4,
+			text = $5,
+			link = $6,
+			updated_at = NOW(),
+			version = s.version + 1
+		FROM artists a
+		WHERE a.id = $1 AND s.id = $7 AND s.version = $8 AND s.deleted_at IS NULL
+		RETURNING ` + songColumns
 
-	// delete удалить песню по id
-	deleteSongQuery = `DELETE FROM songs WHERE id = $1`
+	// delete переместить песню в корзину, проверяя ожидаемую версию (0 - любая версия)
+	deleteSongQuery = `
+		UPDATE songs
+		SET deleted_at = NOW(),
+			version = version + 1
+		WHERE id = $1 AND ($2::integer = 0 OR version = $2) AND deleted_at IS NULL`
 
 	// queries проверить существование песни
 	checkSongExistsQuery = `
 		SELECT EXISTS(
-			SELECT 1 FROM songs 
-			WHERE group_name = $1 
-			AND song_name = $2 
+			SELECT 1 FROM songs
+			WHERE artist_id = $1
+			AND song_name = $2
 			AND id != $3
+			AND deleted_at IS NULL
 		)`
+
+	// queries получить песни из корзины
+	getDeletedSongsQuery = `
+		SELECT ` + songColumns + `, s.deleted_at
+		FROM ` + songsFrom + `
+		WHERE s.deleted_at IS NOT NULL
+		ORDER BY s.deleted_at DESC
+		LIMIT $1 OFFSET $2`
+
+	// queries оценка количества строк в таблице песен по статистике
+	estimateSongsTableQuery = `SELECT reltuples FROM pg_class WHERE oid = 'songs'::regclass`
+
+	// queries счетчик количества песен в корзине
+	countDeletedSongsQuery = `SELECT COUNT(*) FROM songs WHERE deleted_at IS NOT NULL`
+
+	// queries получить песню из корзины по id
+	getDeletedSongByIDQuery = `
+		SELECT ` + songColumns + `, s.deleted_at
+		FROM ` + songsFrom + `
+		WHERE s.id = $1 AND s.deleted_at IS NOT NULL`
+
+	// update восстановить песню из корзины
+	restoreSongQuery = `
+		UPDATE songs s
+		SET deleted_at = NULL,
+			updated_at = NOW(),
+			version = s.version + 1
+		FROM artists a
+		WHERE a.id = s.artist_id AND s.id = $1 AND s.deleted_at IS NOT NULL
+		RETURNING ` + songColumns
+
+	// delete окончательно удалить песню из корзины
+	purgeSongQuery = `DELETE FROM songs WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	// delete окончательно удалить песни, находящиеся в корзине дольше срока хранения
+	purgeExpiredSongsQuery = `DELETE FROM songs WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	// insert сохранить ревизию песни со следующим порядковым номером
+	addRevisionQuery = `
+		INSERT INTO song_revisions (song_id, revision, group_name, song_name, release_date, release_date_precision, text, link)
+		SELECT $1, COALESCE(MAX(revision), 0) + 1, $2, $3, $4, $5, $6, $7
+		FROM song_revisions
+		WHERE song_id = $1`
+
+	// queries получить все ревизии песни
+	getRevisionsQuery = `
+		SELECT song_id, revision, group_name, song_name, release_date, release_date_precision, text, link, created_at
+		FROM song_revisions
+		WHERE song_id = $1
+		ORDER BY revision`
+
+	// queries получить ревизию песни по номеру
+	getRevisionQuery = `
+		SELECT song_id, revision, group_name, song_name, release_date, release_date_precision, text, link, created_at
+		FROM song_revisions
+		WHERE song_id = $1 AND revision = $2`
 )
 
-// PostgresSongRepository имплементирует SongRepository для PostgreSQL.
+// PostgresSongRepository имплементирует SongRepository, ArtistRepository и AlbumRepository для PostgreSQL.
+// Запись и чтения, требующие собственных изменений, выполняются на основной базе данных,
+// остальные чтения песен распределяются между репликами.
+// Внутри WithTx все запросы, включая чтения, выполняются в транзакции на основной базе данных.
 type PostgresSongRepository struct {
-	db *sql.DB
+	db        *tracedDB                 // основная база данных
+	q         querier                   // исполнитель запросов: основная база данных или текущая транзакция
+	inTx      bool                      // репозиторий привязан к транзакции
+	replicas  *ReplicaSet               // реплики для чтения, может быть nil
+	isolation repository.IsolationLevel // уровень изоляции транзакций по умолчанию
+	logger    *zap.Logger
 }
 
-func NewPostgresSongRepository(db *sql.DB) repository.SongRepository {
-	return &PostgresSongRepository{db: db}
+var (
+	_ repository.Tx               = (*PostgresSongRepository)(nil)
+	_ repository.ArtistRepository = (*PostgresSongRepository)(nil)
+)
+
+// NewPostgresSongRepository создает репозиторий. replicas может быть nil, тогда все запросы
+// выполняются на основной базе данных. isolation задает уровень изоляции транзакций по умолчанию.
+func NewPostgresSongRepository(db *sql.DB, replicas *ReplicaSet, isolation repository.IsolationLevel, logger *zap.Logger) *PostgresSongRepository {
+	traced := newTracedDB(db)
+	return &PostgresSongRepository{db: traced, q: traced, replicas: replicas, isolation: isolation, logger: logger}
 }
 
-// CreateSong создает новую песню
+// read выполняет чтение на реплике, если контекст не требует чтения собственных изменений
+// и репозиторий не привязан к транзакции.
+// Если реплика оказалась недоступна, она исключается из распределения, а чтение повторяется на основной базе.
+func (r *PostgresSongRepository) read(ctx context.Context, op func(db querier) error) error {
+	var rep *replica
+	if !r.inTx && !repository.ReadYourWrites(ctx) {
+		rep = r.replicas.pick()
+	}
+	if rep == nil {
+		return op(r.q)
+	}
+
+	err := op(rep.db)
+	if isUnavailable(err) && ctx.Err() == nil {
+		r.replicas.markDown(rep, err)
+		return op(r.q)
+	}
+	return err
+}
+
+// log возвращает логгер запроса из контекста или логгер репозитория.
+func (r *PostgresSongRepository) log(ctx context.Context) *zap.Logger {
+	return logctx.From(ctx, r.logger)
+}
+
+// CreateSong создает новую песню. Проверка существования, вставка и первая ревизия
+// выполняются в одной транзакции.
 func (r *PostgresSongRepository) CreateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
-	if exists, err := r.songExists(ctx, song.GroupName, song.SongName, 0); err != nil || exists {
-		if err != nil {
-			return nil, errors.NewInternal("failed to check song existence", err)
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.resolveSongArtist(ctx, song); err != nil {
+			return err
+		}
+		if exists, err := tx.songExists(ctx, song.ArtistID, song.SongName, 0); err != nil || exists {
+			if err != nil {
+				return err
+			}
+			return errors.NewAlreadyExists("song already exists", nil)
+		}
+		// Уникальный индекс защищает от гонки между проверкой и вставкой: нарушение станет AlreadyExists
+		if err := tx.insertSong(ctx, song); err != nil {
+			return mapError("failed to insert song", err)
 		}
-		return nil, errors.NewAlreadyExists("song already exists", nil)
+		return tx.insertRevision(ctx, song)
+	})
+	if err != nil {
+		return nil, err
 	}
-	return song, r.insertSong(ctx, song)
+
+	r.log(ctx).Debug("Song inserted", zap.Int("songId", song.ID))
+	return song, nil
 }
 
-// songExists проверяет, существует ли песня с указанным названием и группой.
-func (r *PostgresSongRepository) songExists(ctx context.Context, groupName, songName string, songID int) (bool, error) {
+// songExists проверяет, существует ли песня с указанным названием у исполнителя.
+func (r *PostgresSongRepository) songExists(ctx context.Context, artistID int, songName string, songID int) (bool, error) {
 	var exists bool
-	err := r.db.QueryRowContext(ctx, checkSongExistsQuery, groupName, songName, songID).Scan(&exists)
+	err := r.q.QueryRowContext(ctx, checkSongExistsQuery, artistID, songName, songID).Scan(&exists)
 	if err != nil {
-		return false, errors.NewInternal("failed to check song existence", err)
+		return false, mapError("failed to check song existence", err)
 	}
 	return exists, nil
 }
 
 // insertSong вставляет новую песню в базу данных.
 func (r *PostgresSongRepository) insertSong(ctx context.Context, song *models.Song) error {
-	return r.db.QueryRowContext(
+	row := r.q.QueryRowContext(
 		ctx,
 		addSongQuery,
-		song.GroupName,
+		song.ArtistID,
 		song.SongName,
-		song.ReleaseDate,
+		releaseDateValue(song.ReleaseDate),
+		releaseDatePrecision(song.ReleaseDate),
 		song.Text,
 		song.Link,
-	).Scan(
-		&song.ID,
-		&song.GroupName,
-		&song.SongName,
-		&song.ReleaseDate,
-		&song.Text,
-		&song.Link,
-		&song.CreatedAt,
-		&song.UpdatedAt,
 	)
+	return scanSong(row, song)
 }
 
 // GetSongs получает список песен с учетом фильтров и постраничной навигации
@@ -127,8 +226,23 @@ func (r *PostgresSongRepository) GetSongs(ctx context.Context, filter *models.So
 	// Устанавливаем значения по умолчанию
 	setDefaultFilterValues(filter)
 
+	var response *models.SongsResponse
+	err := r.read(ctx, func(db querier) error {
+		var err error
+		response, err = r.getSongs(ctx, db, filter)
+		return err
+	})
+	return response, err
+}
+
+// getSongs получает страницу песен и общее количество на одном экземпляре базы данных.
+func (r *PostgresSongRepository) getSongs(ctx context.Context, db querier, filter *models.SongFilter) (*models.SongsResponse, error) {
+	if filter.Count == models.CountEstimate || filter.Count == models.CountNone {
+		return r.getSongsWithoutCount(ctx, db, filter)
+	}
+
 	// Получаем общее количество записей
-	totalItems, err := r.countTotalSongs(ctx, filter)
+	totalItems, err := r.countTotalSongs(ctx, db, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -136,27 +250,81 @@ func (r *PostgresSongRepository) GetSongs(ctx context.Context, filter *models.So
 	// Вычисляем общее количество страниц
 	totalPages := (totalItems + filter.PageSize - 1) / filter.PageSize
 
-	// Проверка существования запрашиваемой страницы
-	if filter.Page > totalPages {
+	// Проверка существования запрашиваемой страницы, пустой список возвращается первой страницей
+	if totalItems > 0 && filter.Page > totalPages {
 		return nil, errors.NewNotFound(fmt.Sprintf("page %d does not exist, total pages: %d", filter.Page, totalPages), nil)
 	}
 
 	offset := (filter.Page - 1) * filter.PageSize
 
 	// Получаем записи для текущей страницы
-	songs, err := r.getSongsByPage(ctx, filter, offset)
+	songs, err := r.getSongsByPage(ctx, db, filter, filter.PageSize, offset)
 	if err != nil {
 		return nil, err
 	}
 
 	// Формируем ответ
-	return &models.SongsResponse{
-		TotalItems: totalItems,
-		TotalPages: totalPages,
-		Page:       filter.Page,
-		PageSize:   filter.PageSize,
-		Songs:      songs,
-	}, nil
+	response := &models.SongsResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Songs:    songs,
+		HasNext:  filter.Page < totalPages,
+	}
+	setTotal(response, totalItems, true)
+	return response, nil
+}
+
+// getSongsWithoutCount получает страницу песен без COUNT(*). Наличие следующей страницы определяется
+// выборкой на одну песню больше размера страницы, общее количество при count=estimate оценивается.
+// Страница за пределами списка возвращается пустой, так как без подсчета ее нельзя отличить от существующей.
+func (r *PostgresSongRepository) getSongsWithoutCount(ctx context.Context, db querier, filter *models.SongFilter) (*models.SongsResponse, error) {
+	offset := (filter.Page - 1) * filter.PageSize
+	songs, err := r.getSongsByPage(ctx, db, filter, filter.PageSize+1, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	hasNext := len(songs) > filter.PageSize
+	if hasNext {
+		songs = songs[:filter.PageSize]
+	}
+	response := &models.SongsResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Songs:    songs,
+		HasNext:  hasNext,
+	}
+	if filter.Count == models.CountNone {
+		return response, nil
+	}
+
+	// На последней странице количество известно точно без подсчета
+	if !hasNext && (len(songs) > 0 || filter.Page == 1) {
+		setTotal(response, offset+len(songs), true)
+		return response, nil
+	}
+
+	estimate, err := r.estimateTotalSongs(ctx, db, filter)
+	if err != nil {
+		return nil, err
+	}
+	if hasNext {
+		// Оценка не может быть меньше количества уже найденных песен
+		estimate = max(estimate, offset+len(songs)+1)
+	} else {
+		// Страница за пределами списка: песен не больше, чем на предыдущих страницах
+		estimate = min(estimate, offset)
+	}
+	setTotal(response, estimate, false)
+	return response, nil
+}
+
+// setTotal заполняет общее количество песен и страниц в ответе.
+func setTotal(response *models.SongsResponse, totalItems int, exact bool) {
+	totalPages := (totalItems + response.PageSize - 1) / response.PageSize
+	response.TotalItems = &totalItems
+	response.TotalPages = &totalPages
+	response.TotalItemsExact = exact
 }
 
 // setDefaultFilterValues устанавливает значения по умолчанию для фильтра
@@ -170,37 +338,53 @@ func setDefaultFilterValues(filter *models.SongFilter) {
 }
 
 // countTotalSongs получает общее количество песен, соответствующих фильтру
-func (r *PostgresSongRepository) countTotalSongs(ctx context.Context, filter *models.SongFilter) (int, error) {
+func (r *PostgresSongRepository) countTotalSongs(ctx context.Context, db querier, filter *models.SongFilter) (int, error) {
 	var totalItems int
-	err := r.db.QueryRowContext(
-		ctx,
-		countSongsQuery,
-		filter.GroupName,
-		filter.SongName,
-		filter.FromDate,
-		filter.ToDate,
-		filter.Text,
-		filter.Link).Scan(&totalItems)
+	query, args := CountSongsQuery(filter)
+	err := db.QueryRowContext(ctx, query, args...).Scan(&totalItems)
 	if err != nil {
-		return 0, errors.NewInternal("failed to count songs", err)
+		return 0, mapError("failed to count songs", err)
 	}
 	return totalItems, nil
 }
 
-// getSongsByPage получает список песен для заданной страницы
-func (r *PostgresSongRepository) getSongsByPage(ctx context.Context, filter *models.SongFilter, offset int) ([]models.Song, error) {
-	rows, err := r.db.QueryContext(ctx, getAllSongsQuery,
-		filter.GroupName,
-		filter.SongName,
-		filter.FromDate,
-		filter.ToDate,
-		filter.Text,
-		filter.Link,
-		filter.PageSize,
-		offset,
-	)
+// estimateTotalSongs оценивает количество песен, соответствующих фильтру. Для списка без фильтра
+// используется pg_class.reltuples, иначе - оценка количества строк в плане запроса.
+// reltuples включает песни в корзине, что допустимо для оценки.
+func (r *PostgresSongRepository) estimateTotalSongs(ctx context.Context, db querier, filter *models.SongFilter) (int, error) {
+	if !hasFilter(filter) {
+		var reltuples float64
+		if err := db.QueryRowContext(ctx, estimateSongsTableQuery).Scan(&reltuples); err != nil {
+			return 0, mapError("failed to estimate songs count", err)
+		}
+		// Отрицательное значение означает, что статистика по таблице еще не собиралась
+		if reltuples >= 0 {
+			return int(reltuples), nil
+		}
+	}
+
+	query, args := estimateSongsQuery(filter)
+	var raw []byte
+	if err := db.QueryRowContext(ctx, query, args...).Scan(&raw); err != nil {
+		return 0, mapError("failed to estimate songs count", err)
+	}
+	var plan []struct {
+		Plan struct {
+			Rows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+	if err := json.Unmarshal(raw, &plan); err != nil || len(plan) == 0 {
+		return 0, errors.NewInternal("failed to parse query plan", err)
+	}
+	return int(plan[0].Plan.Rows), nil
+}
+
+// getSongsByPage получает не более limit песен, начиная с offset
+func (r *PostgresSongRepository) getSongsByPage(ctx context.Context, db querier, filter *models.SongFilter, limit, offset int) ([]models.Song, error) {
+	query, args := SongsQuery(filter, limit, offset)
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, errors.NewInternal("failed to query songs", err)
+		return nil, mapError("failed to query songs", err)
 	}
 	defer rows.Close()
 
@@ -208,24 +392,15 @@ func (r *PostgresSongRepository) getSongsByPage(ctx context.Context, filter *mod
 	var songs []models.Song
 	for rows.Next() {
 		var song models.Song
-		if err := rows.Scan(
-			&song.ID,
-			&song.GroupName,
-			&song.SongName,
-			&song.ReleaseDate,
-			&song.Text,
-			&song.Link,
-			&song.CreatedAt,
-			&song.UpdatedAt,
-		); err != nil {
-			return nil, errors.NewInternal("failed to scan song", err)
+		if err := scanSong(rows, &song); err != nil {
+			return nil, mapError("failed to scan song", err)
 		}
 		songs = append(songs, song)
 	}
 
 	// Проверка на ошибки после завершения перебора строк
 	if err := rows.Err(); err != nil {
-		return nil, errors.NewInternal("error occurred while iterating over songs", err)
+		return nil, mapError("error occurred while iterating over songs", err)
 	}
 
 	return songs, nil
@@ -234,58 +409,366 @@ func (r *PostgresSongRepository) getSongsByPage(ctx context.Context, filter *mod
 // GetSongByID запрашивает информацию о song по ее ID из базы данных PostgreSQL
 func (r *PostgresSongRepository) GetSongByID(ctx context.Context, id int) (*models.Song, error) {
 	var song models.Song
-	err := r.db.QueryRowContext(ctx, getSongByIDQuery, id).Scan(&song.ID,
-		&song.GroupName,
-		&song.SongName,
-		&song.ReleaseDate,
-		&song.Text,
-		&song.Link,
-		&song.CreatedAt,
-		&song.UpdatedAt)
-	if err == sql.ErrNoRows {
-		return nil, errors.NewNotFound("song not found", err)
-	} else if err != nil {
-		return nil, errors.NewInternal("failed to get song", err)
+	err := r.read(ctx, func(db querier) error {
+		err := scanSong(db.QueryRowContext(ctx, getSongByIDQuery, id), &song)
+		if err == sql.ErrNoRows {
+			return errors.NewNotFound("song not found", err)
+		}
+		return mapError("failed to get song", err)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return &song, nil
 }
 
-// updateSong обновляет информацию о песне в базе данных.
+// updateSong обновляет информацию о песне в базе данных, если ее версия не изменилась с момента чтения.
+// Вызывается внутри транзакции, которая при конфликте повторяется целиком.
 func (r *PostgresSongRepository) updateSong(ctx context.Context, song *models.Song) error {
-	err := r.db.QueryRowContext(ctx, updateSongQuery, song.GroupName, song.SongName, song.ReleaseDate, song.Text, song.Link, song.ID).Scan(&song.ID, &song.GroupName, &song.SongName, &song.ReleaseDate, &song.Text, &song.Link, &song.CreatedAt, &song.UpdatedAt)
+	row := r.q.QueryRowContext(ctx, updateSongQuery,
+		song.ArtistID,
+		song.SongName,
+		releaseDateValue(song.ReleaseDate),
+		releaseDatePrecision(song.ReleaseDate),
+		song.Text,
+		song.Link,
+		song.ID,
+		song.Version,
+	)
+	err := scanSong(row, song)
 	if err == sql.ErrNoRows {
-		return errors.NewNotFound("song not found", err)
+		return r.versionConflict(ctx, song.ID)
 	} else if err != nil {
-		return errors.NewInternal("failed to update song", err)
+		return mapError("failed to update song", err)
 	}
 	return nil
 }
 
-// UpdateSong обновляет информацию о песне в базе данных.
+// UpdateSong обновляет информацию о песне в базе данных. Проверка уникальности названия,
+// обновление и сохранение ревизии выполняются в одной транзакции.
 func (r *PostgresSongRepository) UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
-	if exists, err := r.songExists(ctx, song.GroupName, song.SongName, song.ID); err != nil || exists {
-		if err != nil {
-			return nil, err
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		if err := tx.resolveSongArtist(ctx, song); err != nil {
+			return err
 		}
-		return nil, errors.NewAlreadyExists("song already exists", nil)
-	}
-	if err := r.updateSong(ctx, song); err != nil {
+		if exists, err := tx.songExists(ctx, song.ArtistID, song.SongName, song.ID); err != nil || exists {
+			if err != nil {
+				return err
+			}
+			return errors.NewAlreadyExists("song already exists", nil)
+		}
+		if err := tx.updateSong(ctx, song); err != nil {
+			return err
+		}
+		return tx.insertRevision(ctx, song)
+	})
+	if err != nil {
 		return nil, err
 	}
+
+	r.log(ctx).Debug("Song updated", zap.Int("songId", song.ID), zap.Int("version", song.Version))
 	return song, nil
 }
 
-// DeleteSong удаляет песню по заданному идентификатору.
-func (r *PostgresSongRepository) DeleteSong(ctx context.Context, id int) error {
-	result, err := r.db.ExecContext(ctx, deleteSongQuery, id)
+// DeleteSong перемещает песню с заданным идентификатором в корзину.
+// Если version больше нуля, песня удаляется только при совпадении версии.
+func (r *PostgresSongRepository) DeleteSong(ctx context.Context, id, version int) error {
+	var result sql.Result
+	err := r.withRetry(ctx, func() (err error) {
+		result, err = r.q.ExecContext(ctx, deleteSongQuery, id, version)
+		return err
+	})
+	if err != nil {
+		return mapError("failed to execute delete query", err)
+	}
+
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return mapError("failed to retrieve affected rows after delete", err)
+	} else if rowsAffected == 0 {
+		return r.versionConflict(ctx, id)
+	}
+
+	r.log(ctx).Debug("Song moved to trash", zap.Int("songId", id))
+	return nil
+}
+
+// versionConflict определяет причину, по которой запись песни не была изменена:
+// песня отсутствует или ее версия изменилась с момента чтения.
+func (r *PostgresSongRepository) versionConflict(ctx context.Context, id int) error {
+	if _, err := r.GetSongByID(ctx, id); err != nil {
+		return err
+	}
+	return errors.NewPreconditionFailed("song has been modified by another request", nil)
+}
+
+// insertRevision сохраняет текущее состояние песни как новую ревизию.
+// Вызывается в той же транзакции, что и изменение песни.
+func (r *PostgresSongRepository) insertRevision(ctx context.Context, song *models.Song) error {
+	_, err := r.q.ExecContext(ctx, addRevisionQuery,
+		song.ID,
+		song.GroupName,
+		song.SongName,
+		releaseDateValue(song.ReleaseDate),
+		releaseDatePrecision(song.ReleaseDate),
+		song.Text,
+		song.Link,
+	)
+	if err != nil {
+		return mapError("failed to save song revision", err)
+	}
+	return nil
+}
+
+// GetRevisions получает все ревизии песни в порядке их создания.
+func (r *PostgresSongRepository) GetRevisions(ctx context.Context, songID int) ([]models.SongRevision, error) {
+	rows, err := r.q.QueryContext(ctx, getRevisionsQuery, songID)
+	if err != nil {
+		return nil, mapError("failed to query song revisions", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.SongRevision
+	for rows.Next() {
+		var revision models.SongRevision
+		if err := scanRevision(rows, &revision); err != nil {
+			return nil, mapError("failed to scan song revision", err)
+		}
+		revisions = append(revisions, revision)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, mapError("error occurred while iterating over song revisions", err)
+	}
+
+	return revisions, nil
+}
+
+// GetRevision получает ревизию песни по ее номеру.
+func (r *PostgresSongRepository) GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error) {
+	var rev models.SongRevision
+	err := scanRevision(r.q.QueryRowContext(ctx, getRevisionQuery, songID, revision), &rev)
+	if err == sql.ErrNoRows {
+		return nil, errors.NewNotFound(fmt.Sprintf("revision %d not found", revision), err)
+	} else if err != nil {
+		return nil, mapError("failed to get song revision", err)
+	}
+	return &rev, nil
+}
+
+// rowScanner общий интерфейс для *sql.Row и *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanRevision считывает ревизию песни из строки результата запроса.
+func scanRevision(row rowScanner, rev *models.SongRevision) error {
+	var releaseDate releaseDateColumns
+	if err := row.Scan(
+		&rev.SongID,
+		&rev.Revision,
+		&rev.GroupName,
+		&rev.SongName,
+		&releaseDate.date,
+		&releaseDate.precision,
+		&rev.Text,
+		&rev.Link,
+		&rev.CreatedAt,
+	); err != nil {
+		return err
+	}
+	rev.ReleaseDate = releaseDate.value()
+	return nil
+}
+
+// GetDeletedSongs получает список песен из корзины с постраничной навигацией.
+func (r *PostgresSongRepository) GetDeletedSongs(ctx context.Context, page, pageSize int) (*models.SongsResponse, error) {
+	filter := &models.SongFilter{Page: page, PageSize: pageSize}
+	setDefaultFilterValues(filter)
+
+	var totalItems int
+	if err := r.q.QueryRowContext(ctx, countDeletedSongsQuery).Scan(&totalItems); err != nil {
+		return nil, mapError("failed to count deleted songs", err)
+	}
+
+	totalPages := (totalItems + filter.PageSize - 1) / filter.PageSize
+	if totalItems > 0 && filter.Page > totalPages {
+		return nil, errors.NewNotFound(fmt.Sprintf("page %d does not exist, total pages: %d", filter.Page, totalPages), nil)
+	}
+
+	offset := (filter.Page - 1) * filter.PageSize
+	rows, err := r.q.QueryContext(ctx, getDeletedSongsQuery, filter.PageSize, offset)
+	if err != nil {
+		return nil, mapError("failed to query deleted songs", err)
+	}
+	defer rows.Close()
+
+	songs := []models.Song{}
+	for rows.Next() {
+		var song models.Song
+		if err := scanDeletedSong(rows, &song); err != nil {
+			return nil, mapError("failed to scan deleted song", err)
+		}
+		songs = append(songs, song)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, mapError("error occurred while iterating over deleted songs", err)
+	}
+
+	response := &models.SongsResponse{
+		Page:     filter.Page,
+		PageSize: filter.PageSize,
+		Songs:    songs,
+		HasNext:  filter.Page < totalPages,
+	}
+	setTotal(response, totalItems, true)
+	return response, nil
+}
+
+// RestoreSong восстанавливает песню из корзины, если это не нарушает уникальность названия.
+// Проверка и восстановление выполняются в одной транзакции.
+func (r *PostgresSongRepository) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	var song models.Song
+	err := r.transaction(ctx, func(tx *PostgresSongRepository) error {
+		var deleted models.Song
+		err := scanDeletedSong(tx.q.QueryRowContext(ctx, getDeletedSongByIDQuery, id), &deleted)
+		if err == sql.ErrNoRows {
+			return errors.NewNotFound("song not found in trash", err)
+		} else if err != nil {
+			return mapError("failed to get deleted song", err)
+		}
+
+		if exists, err := tx.songExists(ctx, deleted.ArtistID, deleted.SongName, id); err != nil || exists {
+			if err != nil {
+				return err
+			}
+			return errors.NewAlreadyExists("song with the same name already exists", nil)
+		}
+
+		err = scanSong(tx.q.QueryRowContext(ctx, restoreSongQuery, id), &song)
+		if err == sql.ErrNoRows {
+			return errors.NewNotFound("song not found in trash", err)
+		} else if err != nil {
+			return mapError("failed to restore song", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &song, nil
+}
+
+// PurgeSong окончательно удаляет песню из корзины.
+func (r *PostgresSongRepository) PurgeSong(ctx context.Context, id int) error {
+	var result sql.Result
+	err := r.withRetry(ctx, func() (err error) {
+		result, err = r.q.ExecContext(ctx, purgeSongQuery, id)
+		return err
+	})
 	if err != nil {
-		return errors.NewInternal("failed to execute delete query", err)
+		return mapError("failed to execute purge query", err)
 	}
 
 	if rowsAffected, err := result.RowsAffected(); err != nil {
-		return errors.NewInternal("failed to retrieve affected rows after delete", err)
+		return mapError("failed to retrieve affected rows after purge", err)
 	} else if rowsAffected == 0 {
-		return errors.NewNotFound("song not found", nil)
+		return errors.NewNotFound("song not found in trash", nil)
+	}
+
+	r.log(ctx).Debug("Song purged", zap.Int("songId", id))
+	return nil
+}
+
+// PurgeExpiredSongs окончательно удаляет песни, перемещенные в корзину раньше указанного момента.
+func (r *PostgresSongRepository) PurgeExpiredSongs(ctx context.Context, before time.Time) (int64, error) {
+	var result sql.Result
+	err := r.withRetry(ctx, func() (err error) {
+		result, err = r.q.ExecContext(ctx, purgeExpiredSongsQuery, before)
+		return err
+	})
+	if err != nil {
+		return 0, mapError("failed to purge expired songs", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, mapError("failed to retrieve affected rows after purge", err)
+	}
+	return purged, nil
+}
+
+// scanSong считывает песню из строки результата запроса.
+func scanSong(row rowScanner, song *models.Song) error {
+	var releaseDate releaseDateColumns
+	if err := row.Scan(
+		&song.ID,
+		&song.ArtistID,
+		&song.GroupName,
+		&song.SongName,
+		&releaseDate.date,
+		&releaseDate.precision,
+		&song.Text,
+		&song.Link,
+		&song.CreatedAt,
+		&song.UpdatedAt,
+		&song.Version,
+	); err != nil {
+		return err
 	}
+	song.ReleaseDate = releaseDate.value()
 	return nil
 }
+
+// scanDeletedSong считывает песню из корзины вместе с датой удаления.
+func scanDeletedSong(row rowScanner, song *models.Song) error {
+	var releaseDate releaseDateColumns
+	if err := row.Scan(
+		&song.ID,
+		&song.ArtistID,
+		&song.GroupName,
+		&song.SongName,
+		&releaseDate.date,
+		&releaseDate.precision,
+		&song.Text,
+		&song.Link,
+		&song.CreatedAt,
+		&song.UpdatedAt,
+		&song.Version,
+		&song.DeletedAt,
+	); err != nil {
+		return err
+	}
+	song.ReleaseDate = releaseDate.value()
+	return nil
+}
+
+// releaseDateColumns содержит значения колонок даты выпуска и ее точности.
+type releaseDateColumns struct {
+	date      sql.NullTime
+	precision string
+}
+
+// value собирает дату выпуска из значений колонок.
+func (c releaseDateColumns) value() *models.ReleaseDate {
+	if !c.date.Valid {
+		return nil
+	}
+	return models.NewReleaseDate(c.date.Time, models.DatePrecision(c.precision))
+}
+
+// releaseDateValue возвращает значение для колонки release_date.
+func releaseDateValue(date *models.ReleaseDate) interface{} {
+	if date == nil {
+		return nil
+	}
+	return date.Time
+}
+
+// releaseDatePrecision возвращает значение для колонки release_date_precision.
+func releaseDatePrecision(date *models.ReleaseDate) string {
+	if date == nil {
+		return string(models.PrecisionDay)
+	}
+	return string(date.Precision)
+}
@@ -0,0 +1,127 @@
+package database
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ZnNr/songs-library/internal/models"
+)
+
+// Столбцы песни в порядке, ожидаемом scanSong. Имя группы берется из таблицы исполнителей.
+const songColumns = `s.id, s.artist_id, a.name, s.song_name, s.release_date, s.release_date_precision, s.text, s.link, s.created_at, s.updated_at, s.version`
+
+// songsFrom - источник строк песен вместе с их исполнителями.
+const songsFrom = `songs s JOIN artists a ON a.id = s.artist_id`
+
+// releaseDateEndExpr вычисляет последний день периода выпуска с учетом точности даты.
+// Песня с частичной датой попадает в фильтр по датам, если ее период пересекается с интервалом фильтра.
+const releaseDateEndExpr = `(s.release_date + CASE s.release_date_precision
+			WHEN 'year' THEN INTERVAL '1 year'
+			WHEN 'month' THEN INTERVAL '1 month'
+			ELSE INTERVAL '1 day' END - INTERVAL '1 day')`
+
+// queryBuilder собирает условия WHERE и нумерует их параметры.
+type queryBuilder struct {
+	conditions []string
+	args       []interface{}
+	joins      bool // условия ссылаются на таблицу исполнителей
+}
+
+// arg добавляет параметр запроса и возвращает его плейсхолдер.
+func (b *queryBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// where добавляет условие, объединяемое с остальными через AND.
+func (b *queryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// clause возвращает WHERE со всеми добавленными условиями или пустую строку, если условий нет.
+func (b *queryBuilder) clause() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// from возвращает источник строк песен для подсчета: таблица исполнителей присоединяется,
+// только если на нее ссылаются условия.
+func (b *queryBuilder) from() string {
+	if b.joins {
+		return songsFrom
+	}
+	return "songs s"
+}
+
+// songFilterQuery формирует условия выборки неудаленных песен только по заполненным полям фильтра,
+// чтобы планировщик получал конкретные предикаты и мог использовать индексы.
+func songFilterQuery(filter *models.SongFilter) *queryBuilder {
+	b := &queryBuilder{}
+	b.where("s.deleted_at IS NULL")
+	if filter.ArtistID != 0 {
+		b.where("s.artist_id = " + b.arg(filter.ArtistID))
+	}
+	if filter.AlbumID != 0 {
+		b.where("EXISTS (SELECT 1 FROM album_tracks t WHERE t.song_id = s.id AND t.album_id = " + b.arg(filter.AlbumID) + ")")
+	}
+	if filter.GroupName != "" {
+		// Имя группы ищется и среди псевдонимов, чтобы любое написание находило песни канонического исполнителя
+		b.joins = true
+		pattern := b.arg(containsPattern(filter.GroupName))
+		b.where("(a.name ILIKE " + pattern +
+			" OR EXISTS (SELECT 1 FROM artist_aliases al WHERE al.artist_id = s.artist_id AND al.name ILIKE " + pattern + "))")
+	}
+	if filter.SongName != "" {
+		b.where("s.song_name ILIKE " + b.arg(containsPattern(filter.SongName)))
+	}
+	if filter.FromDate != nil {
+		from := b.arg(*filter.FromDate)
+		// Период выпуска не длиннее года, поэтому первое условие отсекает строки по индексу release_date,
+		// а второе точно проверяет пересечение периода с фильтром
+		b.where("s.release_date > " + from + "::timestamp - INTERVAL '1 year'")
+		b.where(releaseDateEndExpr + " >= " + from)
+	}
+	if filter.ToDate != nil {
+		b.where("s.release_date <= " + b.arg(*filter.ToDate))
+	}
+	if filter.Text != "" {
+		b.where("s.text ILIKE " + b.arg(containsPattern(filter.Text)))
+	}
+	if filter.Link != "" {
+		b.where("s.link ILIKE " + b.arg(containsPattern(filter.Link)))
+	}
+	return b
+}
+
+// containsPattern формирует шаблон ILIKE для поиска подстроки.
+func containsPattern(value string) string {
+	return "%" + value + "%"
+}
+
+// hasFilter проверяет, задано ли в фильтре хотя бы одно условие отбора песен.
+func hasFilter(filter *models.SongFilter) bool {
+	return filter.ArtistID != 0 || filter.AlbumID != 0 || filter.GroupName != "" || filter.SongName != "" || filter.FromDate != nil || filter.ToDate != nil ||
+		filter.Text != "" || filter.Link != ""
+}
+
+// SongsQuery формирует запрос не более limit песен, соответствующих фильтру, начиная с offset, и его параметры.
+func SongsQuery(filter *models.SongFilter, limit, offset int) (string, []interface{}) {
+	b := songFilterQuery(filter)
+	query := "SELECT " + songColumns + " FROM " + songsFrom + " " + b.clause() +
+		" ORDER BY s.created_at DESC LIMIT " + b.arg(limit) + " OFFSET " + b.arg(offset)
+	return query, b.args
+}
+
+// CountSongsQuery формирует запрос количества песен, соответствующих фильтру, и его параметры.
+func CountSongsQuery(filter *models.SongFilter) (string, []interface{}) {
+	b := songFilterQuery(filter)
+	return "SELECT COUNT(*) FROM " + b.from() + " " + b.clause(), b.args
+}
+
+// estimateSongsQuery формирует запрос плана выборки песен по фильтру, содержащего оценку количества строк.
+func estimateSongsQuery(filter *models.SongFilter) (string, []interface{}) {
+	b := songFilterQuery(filter)
+	return "EXPLAIN (FORMAT JSON) SELECT 1 FROM " + b.from() + " " + b.clause(), b.args
+}
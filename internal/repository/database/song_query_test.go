@@ -0,0 +1,105 @@
+package database
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/models"
+)
+
+var placeholderRe = regexp.MustCompile(`\$(\d+)`)
+
+// checkPlaceholders проверяет, что плейсхолдеры запроса пронумерованы подряд с $1 и соответствуют параметрам.
+func checkPlaceholders(t *testing.T, query string, args []interface{}) {
+	t.Helper()
+	used := make(map[int]bool)
+	for _, match := range placeholderRe.FindAllStringSubmatch(query, -1) {
+		n, _ := strconv.Atoi(match[1])
+		used[n] = true
+	}
+	if len(used) != len(args) {
+		t.Errorf("query uses %d distinct placeholders for %d args: %s", len(used), len(args), query)
+	}
+	for n := 1; n <= len(args); n++ {
+		if !used[n] {
+			t.Errorf("placeholder $%d is not used: %s", n, query)
+		}
+	}
+}
+
+func TestSongsQueryWithoutFilters(t *testing.T) {
+	query, args := SongsQuery(&models.SongFilter{Page: 3, PageSize: 20}, 20, 40)
+
+	want := "SELECT " + songColumns + " FROM " + songsFrom + " WHERE s.deleted_at IS NULL ORDER BY s.created_at DESC LIMIT $1 OFFSET $2"
+	if query != want {
+		t.Errorf("query =\n%s\nwant\n%s", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{20, 40}) {
+		t.Errorf("args = %v, want [20 40]", args)
+	}
+
+	// Без фильтра по исполнителю количество считается без соединения с artists
+	count, countArgs := CountSongsQuery(&models.SongFilter{})
+	if count != "SELECT COUNT(*) FROM songs s WHERE s.deleted_at IS NULL" || len(countArgs) != 0 {
+		t.Errorf("count query = %q, args %v", count, countArgs)
+	}
+}
+
+func TestSongsQueryOnlyFilledFields(t *testing.T) {
+	filter := &models.SongFilter{SongName: "Hysteria", Link: "youtube", PageSize: 10}
+	query, args := SongsQuery(filter, filter.PageSize, 0)
+	checkPlaceholders(t, query, args)
+
+	want := "WHERE s.deleted_at IS NULL AND s.song_name ILIKE $1 AND s.link ILIKE $2 ORDER BY"
+	if !strings.Contains(query, want) {
+		t.Errorf("query =\n%s\nwant it to contain\n%s", query, want)
+	}
+	if !reflect.DeepEqual(args, []interface{}{"%Hysteria%", "%youtube%", 10, 0}) {
+		t.Errorf("args = %v", args)
+	}
+}
+
+func TestSongsQueryAllFilters(t *testing.T) {
+	from := time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2006, 12, 31, 0, 0, 0, 0, time.UTC)
+	filter := &models.SongFilter{
+		GroupName: "Muse",
+		SongName:  "Hysteria",
+		FromDate:  &from,
+		ToDate:    &to,
+		Text:      "love",
+		Link:      "youtube",
+		PageSize:  10,
+	}
+
+	query, args := SongsQuery(filter, filter.PageSize, 10)
+	checkPlaceholders(t, query, args)
+	count, countArgs := CountSongsQuery(filter)
+	checkPlaceholders(t, count, countArgs)
+
+	// Запрос количества использует те же условия, что и запрос страницы, но без LIMIT и OFFSET
+	if !reflect.DeepEqual(countArgs, args[:len(args)-2]) {
+		t.Errorf("count args = %v, want prefix of page args %v", countArgs, args)
+	}
+	where := strings.TrimPrefix(count, "SELECT COUNT(*) FROM "+songsFrom+" ")
+	if !strings.Contains(query, where+" ORDER BY") {
+		t.Errorf("page query does not share the count WHERE clause:\n%s\n%s", query, where)
+	}
+	estimate, estimateArgs := estimateSongsQuery(filter)
+	if !strings.HasSuffix(estimate, where) || !reflect.DeepEqual(estimateArgs, countArgs) {
+		t.Errorf("estimate query does not share the count WHERE clause: %s", estimate)
+	}
+
+	// Начальная дата используется дважды: для отсечения по индексу и для точной проверки периода
+	if !strings.Contains(where, "s.release_date > $3::timestamp - INTERVAL '1 year'") ||
+		!strings.Contains(where, releaseDateEndExpr+" >= $3") {
+		t.Errorf("from date conditions are missing: %s", where)
+	}
+	if !strings.Contains(where, "s.release_date <= $4") {
+		t.Errorf("to date condition is missing: %s", where)
+	}
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/ZnNr/songs-library/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// stringLiteral находит строковые литералы SQL, которые не должны попадать в трассировки.
+var stringLiteral = regexp.MustCompile(`'(?:[^']|'')*'`)
+
+// querier выполняет SQL-запросы. Реализуется основной базой данных, репликой и транзакцией,
+// что позволяет одним и тем же методам репозитория работать как вне транзакции, так и внутри нее.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// tracedQuerier оборачивает querier и создает спан на каждый SQL-запрос.
+type tracedQuerier struct {
+	next   querier
+	tracer trace.Tracer
+}
+
+// QueryContext выполняет запрос, возвращающий строки.
+func (q tracedQuerier) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	ctx, span := start(ctx, q.tracer, query)
+	rows, err := q.next.QueryContext(ctx, query, args...)
+	tracing.End(span, err)
+	return rows, err
+}
+
+// QueryRowContext выполняет запрос, возвращающий не более одной строки.
+func (q tracedQuerier) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	ctx, span := start(ctx, q.tracer, query)
+	row := q.next.QueryRowContext(ctx, query, args...)
+	tracing.End(span, row.Err())
+	return row
+}
+
+// ExecContext выполняет запрос, не возвращающий строк.
+func (q tracedQuerier) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	ctx, span := start(ctx, q.tracer, query)
+	result, err := q.next.ExecContext(ctx, query, args...)
+	tracing.End(span, err)
+	return result, err
+}
+
+// tracedDB - база данных с трассировкой запросов.
+type tracedDB struct {
+	tracedQuerier
+	*sql.DB
+}
+
+func newTracedDB(db *sql.DB) *tracedDB {
+	return &tracedDB{
+		tracedQuerier: tracedQuerier{next: db, tracer: tracing.Tracer("database")},
+		DB:            db,
+	}
+}
+
+// QueryContext выполняет запрос, возвращающий строки.
+func (db *tracedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return db.tracedQuerier.QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext выполняет запрос, возвращающий не более одной строки.
+func (db *tracedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return db.tracedQuerier.QueryRowContext(ctx, query, args...)
+}
+
+// ExecContext выполняет запрос, не возвращающий строк.
+func (db *tracedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return db.tracedQuerier.ExecContext(ctx, query, args...)
+}
+
+// beginTx начинает транзакцию, запросы которой также трассируются.
+func (db *tracedDB) beginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, querier, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tx, tracedQuerier{next: tx, tracer: db.tracer}, nil
+}
+
+// start создает спан запроса. Текст запроса очищается от строковых литералов;
+// значения параметров передаются отдельно и в спан не попадают.
+func start(ctx context.Context, tracer trace.Tracer, query string) (context.Context, trace.Span) {
+	statement := sanitizeStatement(query)
+	operation := statement
+	if i := strings.IndexByte(statement, ' '); i > 0 {
+		operation = statement[:i]
+	}
+	operation = strings.ToUpper(operation)
+
+	return tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+			attribute.String("db.statement", statement),
+		))
+}
+
+// sanitizeStatement заменяет строковые литералы на '?' и схлопывает пробельные символы.
+func sanitizeStatement(query string) string {
+	return strings.Join(strings.Fields(stringLiteral.ReplaceAllString(query, "'?'")), " ")
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// WithTx выполняет fn в транзакции на основной базе данных.
+// Транзакция повторяется целиком при конфликтах сериализации и взаимоблокировках,
+// поэтому fn не должна иметь побочных эффектов вне репозитория.
+func (r *PostgresSongRepository) WithTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	return r.transaction(ctx, func(tx *PostgresSongRepository) error { return fn(tx) })
+}
+
+// transaction выполняет fn с репозиторием, привязанным к транзакции.
+// Если репозиторий уже привязан к транзакции, fn выполняется в ней.
+func (r *PostgresSongRepository) transaction(ctx context.Context, fn func(tx *PostgresSongRepository) error) error {
+	if r.inTx {
+		return fn(r)
+	}
+	opts := &sql.TxOptions{Isolation: sqlIsolationLevel(r.isolationLevel(ctx))}
+	return r.withRetry(ctx, func() error { return r.runTx(ctx, opts, fn) })
+}
+
+// runTx выполняет одну попытку транзакции: фиксирует ее при успехе fn и откатывает при ошибке или панике.
+func (r *PostgresSongRepository) runTx(ctx context.Context, opts *sql.TxOptions, fn func(tx *PostgresSongRepository) error) (err error) {
+	tx, q, err := r.db.beginTx(ctx, opts)
+	if err != nil {
+		return mapError("failed to begin transaction", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	txRepo := &PostgresSongRepository{db: r.db, q: q, inTx: true, isolation: r.isolation, logger: r.logger}
+	if err := fn(txRepo); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			r.log(ctx).Warn("Failed to roll back transaction", zap.Error(rbErr))
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return mapError("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// isolationLevel возвращает уровень изоляции из контекста или уровень по умолчанию.
+func (r *PostgresSongRepository) isolationLevel(ctx context.Context) repository.IsolationLevel {
+	if level := repository.IsolationLevelFrom(ctx); level != "" {
+		return level
+	}
+	return r.isolation
+}
+
+// sqlIsolationLevel преобразует уровень изоляции репозитория в уровень database/sql.
+// Пустое значение оставляет уровень по умолчанию сервера PostgreSQL (READ COMMITTED).
+func sqlIsolationLevel(level repository.IsolationLevel) sql.IsolationLevel {
+	switch level {
+	case repository.IsolationReadCommitted:
+		return sql.LevelReadCommitted
+	case repository.IsolationRepeatableRead:
+		return sql.LevelRepeatableRead
+	case repository.IsolationSerializable:
+		return sql.LevelSerializable
+	}
+	return sql.LevelDefault
+}
@@ -0,0 +1,91 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// AlbumRepository - декоратор репозитория альбомов, замеряющий длительность каждого метода.
+type AlbumRepository struct {
+	next     repository.AlbumRepository
+	observer QueryObserver
+}
+
+var _ repository.AlbumRepository = (*AlbumRepository)(nil)
+
+// NewAlbumRepository создает декоратор над репозиторием next.
+func NewAlbumRepository(next repository.AlbumRepository, observer QueryObserver) *AlbumRepository {
+	return &AlbumRepository{next: next, observer: observer}
+}
+
+// observe фиксирует длительность вызова метода, начатого в момент start.
+func (r *AlbumRepository) observe(method string, start time.Time, err error) {
+	r.observer.ObserveQuery(method, time.Since(start), err)
+}
+
+// GetAlbums получает список альбомов.
+func (r *AlbumRepository) GetAlbums(ctx context.Context, filter *models.AlbumFilter) (*models.AlbumsResponse, error) {
+	start := time.Now()
+	resp, err := r.next.GetAlbums(ctx, filter)
+	r.observe("GetAlbums", start, err)
+	return resp, err
+}
+
+// GetAlbumByID получает альбом с треками по идентификатору.
+func (r *AlbumRepository) GetAlbumByID(ctx context.Context, id int) (*models.Album, error) {
+	start := time.Now()
+	album, err := r.next.GetAlbumByID(ctx, id)
+	r.observe("GetAlbumByID", start, err)
+	return album, err
+}
+
+// CreateAlbum создает альбом.
+func (r *AlbumRepository) CreateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	start := time.Now()
+	created, err := r.next.CreateAlbum(ctx, album)
+	r.observe("CreateAlbum", start, err)
+	return created, err
+}
+
+// UpdateAlbum обновляет альбом.
+func (r *AlbumRepository) UpdateAlbum(ctx context.Context, album *models.Album) (*models.Album, error) {
+	start := time.Now()
+	updated, err := r.next.UpdateAlbum(ctx, album)
+	r.observe("UpdateAlbum", start, err)
+	return updated, err
+}
+
+// DeleteAlbum удаляет альбом.
+func (r *AlbumRepository) DeleteAlbum(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteAlbum(ctx, id)
+	r.observe("DeleteAlbum", start, err)
+	return err
+}
+
+// AddAlbumTrack добавляет песню в альбом.
+func (r *AlbumRepository) AddAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	start := time.Now()
+	album, err := r.next.AddAlbumTrack(ctx, albumID, songID, trackNumber)
+	r.observe("AddAlbumTrack", start, err)
+	return album, err
+}
+
+// MoveAlbumTrack переносит трек альбома на другой номер.
+func (r *AlbumRepository) MoveAlbumTrack(ctx context.Context, albumID, songID, trackNumber int) (*models.Album, error) {
+	start := time.Now()
+	album, err := r.next.MoveAlbumTrack(ctx, albumID, songID, trackNumber)
+	r.observe("MoveAlbumTrack", start, err)
+	return album, err
+}
+
+// RemoveAlbumTrack удаляет песню из альбома.
+func (r *AlbumRepository) RemoveAlbumTrack(ctx context.Context, albumID, songID int) error {
+	start := time.Now()
+	err := r.next.RemoveAlbumTrack(ctx, albumID, songID)
+	r.observe("RemoveAlbumTrack", start, err)
+	return err
+}
@@ -0,0 +1,91 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// ArtistRepository - декоратор репозитория исполнителей, замеряющий длительность каждого метода.
+type ArtistRepository struct {
+	next     repository.ArtistRepository
+	observer QueryObserver
+}
+
+var _ repository.ArtistRepository = (*ArtistRepository)(nil)
+
+// NewArtistRepository создает декоратор над репозиторием next.
+func NewArtistRepository(next repository.ArtistRepository, observer QueryObserver) *ArtistRepository {
+	return &ArtistRepository{next: next, observer: observer}
+}
+
+// observe фиксирует длительность вызова метода, начатого в момент start.
+func (r *ArtistRepository) observe(method string, start time.Time, err error) {
+	r.observer.ObserveQuery(method, time.Since(start), err)
+}
+
+// GetArtists получает список исполнителей.
+func (r *ArtistRepository) GetArtists(ctx context.Context, filter *models.ArtistFilter) (*models.ArtistsResponse, error) {
+	start := time.Now()
+	resp, err := r.next.GetArtists(ctx, filter)
+	r.observe("GetArtists", start, err)
+	return resp, err
+}
+
+// GetArtistByID получает исполнителя по идентификатору.
+func (r *ArtistRepository) GetArtistByID(ctx context.Context, id int) (*models.Artist, error) {
+	start := time.Now()
+	artist, err := r.next.GetArtistByID(ctx, id)
+	r.observe("GetArtistByID", start, err)
+	return artist, err
+}
+
+// CreateArtist создает исполнителя.
+func (r *ArtistRepository) CreateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	start := time.Now()
+	created, err := r.next.CreateArtist(ctx, artist)
+	r.observe("CreateArtist", start, err)
+	return created, err
+}
+
+// UpdateArtist переименовывает исполнителя.
+func (r *ArtistRepository) UpdateArtist(ctx context.Context, artist *models.Artist) (*models.Artist, error) {
+	start := time.Now()
+	updated, err := r.next.UpdateArtist(ctx, artist)
+	r.observe("UpdateArtist", start, err)
+	return updated, err
+}
+
+// DeleteArtist удаляет исполнителя.
+func (r *ArtistRepository) DeleteArtist(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeleteArtist(ctx, id)
+	r.observe("DeleteArtist", start, err)
+	return err
+}
+
+// AddArtistAlias добавляет исполнителю псевдоним.
+func (r *ArtistRepository) AddArtistAlias(ctx context.Context, artistID int, name string) (*models.Artist, error) {
+	start := time.Now()
+	artist, err := r.next.AddArtistAlias(ctx, artistID, name)
+	r.observe("AddArtistAlias", start, err)
+	return artist, err
+}
+
+// DeleteArtistAlias удаляет псевдоним исполнителя.
+func (r *ArtistRepository) DeleteArtistAlias(ctx context.Context, artistID, aliasID int) error {
+	start := time.Now()
+	err := r.next.DeleteArtistAlias(ctx, artistID, aliasID)
+	r.observe("DeleteArtistAlias", start, err)
+	return err
+}
+
+// MergeArtists объединяет двух исполнителей.
+func (r *ArtistRepository) MergeArtists(ctx context.Context, sourceID, targetID int) (*models.Artist, error) {
+	start := time.Now()
+	merged, err := r.next.MergeArtists(ctx, sourceID, targetID)
+	r.observe("MergeArtists", start, err)
+	return merged, err
+}
@@ -0,0 +1,118 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// PlaylistRepository - декоратор репозитория плейлистов, замеряющий длительность каждого метода.
+type PlaylistRepository struct {
+	next     repository.PlaylistRepository
+	observer QueryObserver
+}
+
+var _ repository.PlaylistRepository = (*PlaylistRepository)(nil)
+
+// NewPlaylistRepository создает декоратор над репозиторием next.
+func NewPlaylistRepository(next repository.PlaylistRepository, observer QueryObserver) *PlaylistRepository {
+	return &PlaylistRepository{next: next, observer: observer}
+}
+
+// observe фиксирует длительность вызова метода, начатого в момент start.
+func (r *PlaylistRepository) observe(method string, start time.Time, err error) {
+	r.observer.ObserveQuery(method, time.Since(start), err)
+}
+
+// GetPlaylists получает список плейлистов пользователя.
+func (r *PlaylistRepository) GetPlaylists(ctx context.Context, owner string, page, pageSize int) (*models.PlaylistsResponse, error) {
+	start := time.Now()
+	resp, err := r.next.GetPlaylists(ctx, owner, page, pageSize)
+	r.observe("GetPlaylists", start, err)
+	return resp, err
+}
+
+// GetPlaylistByID получает плейлист по идентификатору.
+func (r *PlaylistRepository) GetPlaylistByID(ctx context.Context, id int) (*models.Playlist, error) {
+	start := time.Now()
+	playlist, err := r.next.GetPlaylistByID(ctx, id)
+	r.observe("GetPlaylistByID", start, err)
+	return playlist, err
+}
+
+// GetPlaylistEntries получает страницу элементов плейлиста.
+func (r *PlaylistRepository) GetPlaylistEntries(ctx context.Context, playlistID, page, pageSize int) (*models.PlaylistEntries, error) {
+	start := time.Now()
+	entries, err := r.next.GetPlaylistEntries(ctx, playlistID, page, pageSize)
+	r.observe("GetPlaylistEntries", start, err)
+	return entries, err
+}
+
+// CreatePlaylist создает плейлист.
+func (r *PlaylistRepository) CreatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error) {
+	start := time.Now()
+	created, err := r.next.CreatePlaylist(ctx, playlist)
+	r.observe("CreatePlaylist", start, err)
+	return created, err
+}
+
+// UpdatePlaylist обновляет плейлист.
+func (r *PlaylistRepository) UpdatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error) {
+	start := time.Now()
+	updated, err := r.next.UpdatePlaylist(ctx, playlist)
+	r.observe("UpdatePlaylist", start, err)
+	return updated, err
+}
+
+// DeletePlaylist удаляет плейлист.
+func (r *PlaylistRepository) DeletePlaylist(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.DeletePlaylist(ctx, id)
+	r.observe("DeletePlaylist", start, err)
+	return err
+}
+
+// AddPlaylistEntry добавляет песню в плейлист.
+func (r *PlaylistRepository) AddPlaylistEntry(ctx context.Context, playlistID, songID, position int) (*models.PlaylistEntry, error) {
+	start := time.Now()
+	entry, err := r.next.AddPlaylistEntry(ctx, playlistID, songID, position)
+	r.observe("AddPlaylistEntry", start, err)
+	return entry, err
+}
+
+// MovePlaylistEntry перемещает элемент плейлиста.
+func (r *PlaylistRepository) MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) (*models.PlaylistEntry, error) {
+	start := time.Now()
+	entry, err := r.next.MovePlaylistEntry(ctx, playlistID, entryID, position)
+	r.observe("MovePlaylistEntry", start, err)
+	return entry, err
+}
+
+// RemovePlaylistEntry удаляет элемент плейлиста.
+func (r *PlaylistRepository) RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error {
+	start := time.Now()
+	err := r.next.RemovePlaylistEntry(ctx, playlistID, entryID)
+	r.observe("RemovePlaylistEntry", start, err)
+	return err
+}
+
+// MatchSong находит песню для импорта плейлиста.
+func (r *PlaylistRepository) MatchSong(ctx context.Context, artist, title string) (*models.SongMatch, error) {
+	start := time.Now()
+	match, err := r.next.MatchSong(ctx, artist, title)
+	r.observe("MatchSong", start, err)
+	return match, err
+}
+
+// WithTx выполняет fn в транзакции. Длительность учитывается как для транзакции целиком,
+// так и для каждого метода репозитория внутри нее.
+func (r *PlaylistRepository) WithTx(ctx context.Context, fn func(repo repository.PlaylistRepository) error) error {
+	start := time.Now()
+	err := r.next.WithTx(ctx, func(tx repository.PlaylistRepository) error {
+		return fn(NewPlaylistRepository(tx, r.observer))
+	})
+	r.observe("WithTx", start, err)
+	return err
+}
@@ -0,0 +1,140 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+)
+
+// QueryObserver учитывает длительность и результат вызовов методов репозитория.
+type QueryObserver interface {
+	ObserveQuery(method string, duration time.Duration, err error)
+}
+
+// SongRepository - декоратор репозитория песен, замеряющий длительность каждого метода.
+type SongRepository struct {
+	next     repository.SongRepository
+	observer QueryObserver
+}
+
+var _ repository.SongRepository = (*SongRepository)(nil)
+
+// NewSongRepository создает декоратор над репозиторием next.
+func NewSongRepository(next repository.SongRepository, observer QueryObserver) *SongRepository {
+	return &SongRepository{next: next, observer: observer}
+}
+
+// observe фиксирует длительность вызова метода, начатого в момент start.
+func (r *SongRepository) observe(method string, start time.Time, err error) {
+	r.observer.ObserveQuery(method, time.Since(start), err)
+}
+
+// GetSongs получает список песен с фильтрацией и пагинацией.
+func (r *SongRepository) GetSongs(ctx context.Context, filter *models.SongFilter) (*models.SongsResponse, error) {
+	start := time.Now()
+	resp, err := r.next.GetSongs(ctx, filter)
+	r.observe("GetSongs", start, err)
+	return resp, err
+}
+
+// GetSongByID получает песню по идентификатору.
+func (r *SongRepository) GetSongByID(ctx context.Context, id int) (*models.Song, error) {
+	start := time.Now()
+	song, err := r.next.GetSongByID(ctx, id)
+	r.observe("GetSongByID", start, err)
+	return song, err
+}
+
+// CreateSong создает новую песню.
+func (r *SongRepository) CreateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	start := time.Now()
+	created, err := r.next.CreateSong(ctx, song)
+	r.observe("CreateSong", start, err)
+	return created, err
+}
+
+// UpdateSong обновляет песню.
+func (r *SongRepository) UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error) {
+	start := time.Now()
+	updated, err := r.next.UpdateSong(ctx, song)
+	r.observe("UpdateSong", start, err)
+	return updated, err
+}
+
+// DeleteSong помещает песню в корзину.
+func (r *SongRepository) DeleteSong(ctx context.Context, id, version int) error {
+	start := time.Now()
+	err := r.next.DeleteSong(ctx, id, version)
+	r.observe("DeleteSong", start, err)
+	return err
+}
+
+// GetRevisions получает историю ревизий песни.
+func (r *SongRepository) GetRevisions(ctx context.Context, songID int) ([]models.SongRevision, error) {
+	start := time.Now()
+	revisions, err := r.next.GetRevisions(ctx, songID)
+	r.observe("GetRevisions", start, err)
+	return revisions, err
+}
+
+// GetRevision получает ревизию песни по номеру.
+func (r *SongRepository) GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error) {
+	start := time.Now()
+	rev, err := r.next.GetRevision(ctx, songID, revision)
+	r.observe("GetRevision", start, err)
+	return rev, err
+}
+
+// GetDeletedSongs получает список песен в корзине.
+func (r *SongRepository) GetDeletedSongs(ctx context.Context, page, pageSize int) (*models.SongsResponse, error) {
+	start := time.Now()
+	resp, err := r.next.GetDeletedSongs(ctx, page, pageSize)
+	r.observe("GetDeletedSongs", start, err)
+	return resp, err
+}
+
+// RestoreSong восстанавливает песню из корзины.
+func (r *SongRepository) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	start := time.Now()
+	song, err := r.next.RestoreSong(ctx, id)
+	r.observe("RestoreSong", start, err)
+	return song, err
+}
+
+// PurgeSong окончательно удаляет песню из корзины.
+func (r *SongRepository) PurgeSong(ctx context.Context, id int) error {
+	start := time.Now()
+	err := r.next.PurgeSong(ctx, id)
+	r.observe("PurgeSong", start, err)
+	return err
+}
+
+// PurgeExpiredSongs окончательно удаляет песни, помещенные в корзину раньше before.
+func (r *SongRepository) PurgeExpiredSongs(ctx context.Context, before time.Time) (int64, error) {
+	start := time.Now()
+	purged, err := r.next.PurgeExpiredSongs(ctx, before)
+	r.observe("PurgeExpiredSongs", start, err)
+	return purged, err
+}
+
+// WithTx выполняет fn в транзакции. Длительность учитывается как для транзакции целиком,
+// так и для каждого метода репозитория внутри нее.
+func (r *SongRepository) WithTx(ctx context.Context, fn func(tx repository.Tx) error) error {
+	start := time.Now()
+	err := r.next.WithTx(ctx, func(tx repository.Tx) error {
+		return fn(txRepository{
+			SongRepository:  NewSongRepository(tx, r.observer),
+			AlbumRepository: NewAlbumRepository(tx, r.observer),
+		})
+	})
+	r.observe("WithTx", start, err)
+	return err
+}
+
+// txRepository - репозиторий, привязанный к транзакции, замеряющий длительность каждого метода.
+type txRepository struct {
+	*SongRepository
+	*AlbumRepository
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/models"
+)
+
+// PlaylistRepository методы для взаимодействия с данными плейлистов в базе данных.
+// Проверка владельца и видимости плейлиста выполняется на уровне сервиса.
+type PlaylistRepository interface {
+	GetPlaylists(ctx context.Context, owner string, page, pageSize int) (*models.PlaylistsResponse, error)
+	GetPlaylistByID(ctx context.Context, id int) (*models.Playlist, error)
+	GetPlaylistEntries(ctx context.Context, playlistID, page, pageSize int) (*models.PlaylistEntries, error)
+	CreatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error)
+	UpdatePlaylist(ctx context.Context, playlist *models.Playlist) (*models.Playlist, error)
+	DeletePlaylist(ctx context.Context, id int) error
+	AddPlaylistEntry(ctx context.Context, playlistID, songID, position int) (*models.PlaylistEntry, error)
+	MovePlaylistEntry(ctx context.Context, playlistID, entryID, position int) (*models.PlaylistEntry, error)
+	RemovePlaylistEntry(ctx context.Context, playlistID, entryID int) error
+
+	// MatchSong находит активную песню по имени исполнителя или его псевдониму и названию для импорта
+	// плейлиста. Возвращает nil, если подходящей песни нет.
+	MatchSong(ctx context.Context, artist, title string) (*models.SongMatch, error)
+
+	// WithTx выполняет fn в транзакции по правилам SongRepository.WithTx.
+	WithTx(ctx context.Context, fn func(repo PlaylistRepository) error) error
+}
@@ -2,14 +2,34 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/ZnNr/songs-library/internal/models"
 )
 
-// SongRepository  методы для взаимодействия с данными песен в базе данных
+// SongRepository  методы для взаимодействия с данными песен в базе данных.
 type SongRepository interface {
 	GetSongs(ctx context.Context, filter *models.SongFilter) (*models.SongsResponse, error)
 	GetSongByID(ctx context.Context, id int) (*models.Song, error)
 	CreateSong(ctx context.Context, song *models.Song) (*models.Song, error)
 	UpdateSong(ctx context.Context, song *models.Song) (*models.Song, error)
-	DeleteSong(ctx context.Context, id int) error
+	DeleteSong(ctx context.Context, id, version int) error
+	GetRevisions(ctx context.Context, songID int) ([]models.SongRevision, error)
+	GetRevision(ctx context.Context, songID, revision int) (*models.SongRevision, error)
+	GetDeletedSongs(ctx context.Context, page, pageSize int) (*models.SongsResponse, error)
+	RestoreSong(ctx context.Context, id int) (*models.Song, error)
+	PurgeSong(ctx context.Context, id int) error
+	PurgeExpiredSongs(ctx context.Context, before time.Time) (int64, error)
+
+	// WithTx выполняет fn в транзакции. Все операции над tx внутри fn выполняются в этой транзакции,
+	// которая фиксируется, если fn вернула nil, и откатывается иначе.
+	// Вложенный вызов WithTx присоединяется к уже открытой транзакции.
+	WithTx(ctx context.Context, fn func(tx Tx) error) error
+}
+
+// Tx - репозиторий, привязанный к транзакции. Помимо песен дает доступ к альбомам,
+// так как песня может создаваться сразу треком альбома.
+type Tx interface {
+	SongRepository
+	AlbumRepository
 }
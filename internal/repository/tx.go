@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// IsolationLevel - уровень изоляции транзакции.
+type IsolationLevel string
+
+// Поддерживаемые уровни изоляции транзакций.
+const (
+	IsolationReadCommitted  IsolationLevel = "read_committed"
+	IsolationRepeatableRead IsolationLevel = "repeatable_read"
+	IsolationSerializable   IsolationLevel = "serializable"
+)
+
+// ParseIsolationLevel разбирает уровень изоляции из конфигурации.
+// Допускаются как "repeatable_read", так и "repeatable read" в любом регистре.
+func ParseIsolationLevel(value string) (IsolationLevel, error) {
+	level := IsolationLevel(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(value)), " ", "_"))
+	switch level {
+	case IsolationReadCommitted, IsolationRepeatableRead, IsolationSerializable:
+		return level, nil
+	}
+	return "", fmt.Errorf("unsupported transaction isolation level %q", value)
+}
+
+// WithIsolationLevel задает уровень изоляции для транзакций, начатых через WithTx в рамках контекста,
+// вместо уровня по умолчанию из конфигурации.
+func WithIsolationLevel(ctx context.Context, level IsolationLevel) context.Context {
+	return context.WithValue(ctx, isolationLevelKey, level)
+}
+
+// IsolationLevelFrom возвращает уровень изоляции, заданный в контексте, или пустую строку.
+func IsolationLevelFrom(ctx context.Context) IsolationLevel {
+	level, _ := ctx.Value(isolationLevelKey).(IsolationLevel)
+	return level
+}
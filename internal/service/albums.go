@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"github.com/ZnNr/songs-library/internal/tracing"
+	"github.com/ZnNr/songs-library/internal/validation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type AlbumService struct {
+	repo   repository.AlbumRepository
+	logger *zap.Logger
+	tracer trace.Tracer
+}
+
+func NewAlbumService(repo repository.AlbumRepository, logger *zap.Logger) *AlbumService {
+	return &AlbumService{
+		repo:   repo,
+		logger: logger,
+		tracer: tracing.Tracer("service"),
+	}
+}
+
+// log возвращает логгер запроса из контекста или логгер сервиса.
+func (s *AlbumService) log(ctx context.Context) *zap.Logger {
+	return logctx.From(ctx, s.logger)
+}
+
+// GetAlbums получает список альбомов, отфильтрованный по исполнителю и подстроке названия.
+func (s *AlbumService) GetAlbums(ctx context.Context, filter *models.AlbumFilter) (*models.AlbumsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.GetAlbums")
+	defer span.End()
+
+	s.log(ctx).Info("Getting albums",
+		zap.Int("artistId", filter.ArtistID),
+		zap.String("search", filter.Search),
+		zap.Int("page", filter.Page),
+		zap.Int("pageSize", filter.PageSize))
+
+	filter.Search = validation.NormalizeName(filter.Search)
+	v := validation.New()
+	v.Check("artist_id", filter.ArtistID >= 0, "must not be negative")
+	v.Field("search", filter.Search, validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	validatePagination(v, filter.Page, filter.PageSize)
+	if err := v.Err("invalid album filter"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAlbums(ctx, filter)
+}
+
+// GetAlbum получает альбом с треками по порядку.
+func (s *AlbumService) GetAlbum(ctx context.Context, id int) (*models.Album, error) {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.GetAlbum")
+	defer span.End()
+
+	s.log(ctx).Info("Getting album", zap.Int("id", id))
+	return s.repo.GetAlbumByID(ctx, id)
+}
+
+// CreateAlbum создает альбом исполнителя.
+func (s *AlbumService) CreateAlbum(ctx context.Context, req *models.AlbumRequest) (*models.Album, error) {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.CreateAlbum")
+	defer span.End()
+
+	s.log(ctx).Info("Creating album", zap.Int("artistId", req.ArtistID), zap.String("title", req.Title))
+
+	album, err := albumFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.CreateAlbum(ctx, album)
+}
+
+// UpdateAlbum полностью заменяет исполнителя, название, дату выпуска и обложку альбома. Треки не меняются.
+func (s *AlbumService) UpdateAlbum(ctx context.Context, id int, req *models.AlbumRequest) (*models.Album, error) {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.UpdateAlbum")
+	defer span.End()
+
+	s.log(ctx).Info("Updating album", zap.Int("id", id), zap.String("title", req.Title))
+
+	album, err := albumFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	album.ID = id
+	return s.repo.UpdateAlbum(ctx, album)
+}
+
+// DeleteAlbum удаляет альбом и его треки. Песни альбома не удаляются.
+func (s *AlbumService) DeleteAlbum(ctx context.Context, id int) error {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.DeleteAlbum")
+	defer span.End()
+
+	s.log(ctx).Info("Deleting album", zap.Int("id", id))
+	return s.repo.DeleteAlbum(ctx, id)
+}
+
+// AddAlbumTrack добавляет песню в альбом под указанным номером или в конец альбома.
+func (s *AlbumService) AddAlbumTrack(ctx context.Context, id int, req *models.AlbumTrackRequest) (*models.Album, error) {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.AddAlbumTrack")
+	defer span.End()
+
+	s.log(ctx).Info("Adding album track",
+		zap.Int("id", id),
+		zap.Int("songId", req.SongID),
+		zap.Int("trackNumber", req.TrackNumber))
+
+	v := validation.New()
+	v.Check("song_id", req.SongID > 0, "must be positive")
+	v.Check("track_number", req.TrackNumber >= 0, "must not be negative")
+	if err := v.Err("invalid album track request"); err != nil {
+		return nil, err
+	}
+	return s.repo.AddAlbumTrack(ctx, id, req.SongID, req.TrackNumber)
+}
+
+// MoveAlbumTrack переносит трек альбома на другой номер.
+func (s *AlbumService) MoveAlbumTrack(ctx context.Context, id, songID int, req *models.AlbumTrackPositionRequest) (*models.Album, error) {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.MoveAlbumTrack")
+	defer span.End()
+
+	s.log(ctx).Info("Moving album track",
+		zap.Int("id", id),
+		zap.Int("songId", songID),
+		zap.Int("trackNumber", req.TrackNumber))
+
+	v := validation.New()
+	v.Check("track_number", req.TrackNumber > 0, "must be positive")
+	if err := v.Err("invalid album track request"); err != nil {
+		return nil, err
+	}
+	return s.repo.MoveAlbumTrack(ctx, id, songID, req.TrackNumber)
+}
+
+// RemoveAlbumTrack удаляет песню из альбома.
+func (s *AlbumService) RemoveAlbumTrack(ctx context.Context, id, songID int) error {
+	ctx, span := s.tracer.Start(ctx, "AlbumService.RemoveAlbumTrack")
+	defer span.End()
+
+	s.log(ctx).Info("Removing album track", zap.Int("id", id), zap.Int("songId", songID))
+	return s.repo.RemoveAlbumTrack(ctx, id, songID)
+}
+
+// albumFromRequest нормализует и проверяет запрос на создание или обновление альбома.
+// Все нарушения возвращаются одной ошибкой, пустая дата выпуска означает, что дата неизвестна.
+func albumFromRequest(req *models.AlbumRequest) (*models.Album, error) {
+	req.Title = validation.NormalizeName(req.Title)
+	req.ReleaseDate = strings.TrimSpace(req.ReleaseDate)
+	req.CoverURL = strings.TrimSpace(req.CoverURL)
+
+	v := validation.New()
+	v.Check("artist_id", req.ArtistID > 0, "must be positive")
+	v.Field("title", req.Title,
+		validation.Required(), validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Field("release_date", req.ReleaseDate, releaseDateRule)
+	v.Field("cover_url", req.CoverURL,
+		validation.MaxLength(maxFieldLength), validation.NoControlChars(), validation.URL())
+	if err := v.Err("invalid album request"); err != nil {
+		return nil, err
+	}
+
+	album := &models.Album{
+		ArtistID: req.ArtistID,
+		Title:    req.Title,
+		CoverURL: req.CoverURL,
+	}
+	if req.ReleaseDate != "" {
+		releaseDate, err := models.ParseReleaseDate(req.ReleaseDate)
+		if err != nil {
+			return nil, errors.NewValidation("invalid release_date", err)
+		}
+		album.ReleaseDate = releaseDate
+	}
+	return album, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"github.com/ZnNr/songs-library/internal/tracing"
+	"github.com/ZnNr/songs-library/internal/validation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type ArtistService struct {
+	repo   repository.ArtistRepository
+	songs  repository.SongRepository
+	logger *zap.Logger
+	tracer trace.Tracer
+}
+
+// NewArtistService создает сервис исполнителей. Репозиторий песен используется только для списка песен исполнителя.
+func NewArtistService(repo repository.ArtistRepository, songs repository.SongRepository, logger *zap.Logger) *ArtistService {
+	return &ArtistService{
+		repo:   repo,
+		songs:  songs,
+		logger: logger,
+		tracer: tracing.Tracer("service"),
+	}
+}
+
+// log возвращает логгер запроса из контекста или логгер сервиса.
+func (s *ArtistService) log(ctx context.Context) *zap.Logger {
+	return logctx.From(ctx, s.logger)
+}
+
+// GetArtists получает список исполнителей с количеством песен, отфильтрованный по подстроке имени или псевдонима.
+func (s *ArtistService) GetArtists(ctx context.Context, filter *models.ArtistFilter) (*models.ArtistsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.GetArtists")
+	defer span.End()
+
+	s.log(ctx).Info("Getting artists",
+		zap.String("search", filter.Search),
+		zap.Int("page", filter.Page),
+		zap.Int("pageSize", filter.PageSize))
+
+	filter.Search = validation.NormalizeName(filter.Search)
+	v := validation.New()
+	v.Field("search", filter.Search, validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	validatePagination(v, filter.Page, filter.PageSize)
+	if err := v.Err("invalid artist filter"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetArtists(ctx, filter)
+}
+
+// GetArtist получает исполнителя по идентификатору.
+func (s *ArtistService) GetArtist(ctx context.Context, id int) (*models.Artist, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.GetArtist")
+	defer span.End()
+
+	s.log(ctx).Info("Getting artist", zap.Int("id", id))
+	return s.repo.GetArtistByID(ctx, id)
+}
+
+// CreateArtist создает исполнителя с уникальным без учета регистра именем.
+func (s *ArtistService) CreateArtist(ctx context.Context, req *models.ArtistRequest) (*models.Artist, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.CreateArtist")
+	defer span.End()
+
+	s.log(ctx).Info("Creating artist", zap.String("name", req.Name))
+
+	if err := validateArtistRequest(req); err != nil {
+		return nil, err
+	}
+	return s.repo.CreateArtist(ctx, &models.Artist{Name: req.Name})
+}
+
+// UpdateArtist переименовывает исполнителя. Новое имя группы получают все его песни.
+func (s *ArtistService) UpdateArtist(ctx context.Context, id int, req *models.ArtistRequest) (*models.Artist, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.UpdateArtist")
+	defer span.End()
+
+	s.log(ctx).Info("Updating artist", zap.Int("id", id), zap.String("name", req.Name))
+
+	if err := validateArtistRequest(req); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdateArtist(ctx, &models.Artist{ID: id, Name: req.Name})
+}
+
+// DeleteArtist удаляет исполнителя без песен.
+func (s *ArtistService) DeleteArtist(ctx context.Context, id int) error {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.DeleteArtist")
+	defer span.End()
+
+	s.log(ctx).Info("Deleting artist", zap.Int("id", id))
+	return s.repo.DeleteArtist(ctx, id)
+}
+
+// AddArtistAlias добавляет исполнителю псевдоним, по которому имя группы будет сопоставляться с ним.
+func (s *ArtistService) AddArtistAlias(ctx context.Context, id int, req *models.ArtistAliasRequest) (*models.Artist, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.AddArtistAlias")
+	defer span.End()
+
+	s.log(ctx).Info("Adding artist alias", zap.Int("id", id), zap.String("name", req.Name))
+
+	req.Name = validation.NormalizeName(req.Name)
+	v := validation.New()
+	v.Field("name", req.Name,
+		validation.Required(), validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	if err := v.Err("invalid artist alias request"); err != nil {
+		return nil, err
+	}
+	return s.repo.AddArtistAlias(ctx, id, req.Name)
+}
+
+// DeleteArtistAlias удаляет псевдоним исполнителя.
+func (s *ArtistService) DeleteArtistAlias(ctx context.Context, id, aliasID int) error {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.DeleteArtistAlias")
+	defer span.End()
+
+	s.log(ctx).Info("Deleting artist alias", zap.Int("id", id), zap.Int("aliasId", aliasID))
+	return s.repo.DeleteArtistAlias(ctx, id, aliasID)
+}
+
+// MergeArtists переносит песни и псевдонимы исходного исполнителя к целевому и удаляет исходного,
+// сохраняя его имя псевдонимом целевого.
+func (s *ArtistService) MergeArtists(ctx context.Context, req *models.MergeArtistsRequest) (*models.Artist, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.MergeArtists")
+	defer span.End()
+
+	s.log(ctx).Info("Merging artists",
+		zap.Int("sourceId", req.SourceID),
+		zap.Int("targetId", req.TargetID),
+		zap.String("user", logctx.User(ctx)))
+
+	v := validation.New()
+	v.Check("source_id", req.SourceID > 0, "must be positive")
+	v.Check("target_id", req.TargetID > 0, "must be positive")
+	v.Check("target_id", req.TargetID != req.SourceID, "must differ from source_id")
+	if err := v.Err("invalid merge request"); err != nil {
+		return nil, err
+	}
+	return s.repo.MergeArtists(ctx, req.SourceID, req.TargetID)
+}
+
+// GetArtistSongs получает страницу песен исполнителя.
+func (s *ArtistService) GetArtistSongs(ctx context.Context, id, page, pageSize int) (*models.SongsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "ArtistService.GetArtistSongs")
+	defer span.End()
+
+	s.log(ctx).Info("Getting artist songs",
+		zap.Int("id", id),
+		zap.Int("page", page),
+		zap.Int("pageSize", pageSize))
+
+	v := validation.New()
+	validatePagination(v, page, pageSize)
+	if err := v.Err("invalid pagination"); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetArtistByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.songs.GetSongs(ctx, &models.SongFilter{ArtistID: id, Page: page, PageSize: pageSize})
+}
+
+// validateArtistRequest нормализует и проверяет запрос на создание или переименование исполнителя.
+func validateArtistRequest(req *models.ArtistRequest) error {
+	req.Name = validation.NormalizeName(req.Name)
+
+	v := validation.New()
+	v.Field("name", req.Name,
+		validation.Required(), validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	return v.Err("invalid artist request")
+}
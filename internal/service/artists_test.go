@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// artistRepoStub реализует только объединение исполнителей.
+// Вызов остальных методов встроенного nil-интерфейса завершает тест паникой.
+type artistRepoStub struct {
+	repository.ArtistRepository
+	merged bool
+}
+
+func (r *artistRepoStub) MergeArtists(ctx context.Context, sourceID, targetID int) (*models.Artist, error) {
+	r.merged = true
+	return &models.Artist{ID: targetID}, nil
+}
+
+func TestMergeArtistsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.MergeArtistsRequest
+		wantErr errors.ErrorType // пустой тип - объединение выполняется
+	}{
+		{name: "different artists", req: models.MergeArtistsRequest{SourceID: 1, TargetID: 2}},
+		{name: "same artist", req: models.MergeArtistsRequest{SourceID: 1, TargetID: 1}, wantErr: errors.Validation},
+		{name: "missing source", req: models.MergeArtistsRequest{TargetID: 2}, wantErr: errors.Validation},
+		{name: "negative target", req: models.MergeArtistsRequest{SourceID: 1, TargetID: -2}, wantErr: errors.Validation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &artistRepoStub{}
+			svc := NewArtistService(repo, nil, zap.NewNop())
+
+			_, err := svc.MergeArtists(context.Background(), &tt.req)
+			checkErrorType(t, "MergeArtists", err, tt.wantErr)
+			if repo.merged != (tt.wantErr == "") {
+				t.Errorf("merged = %v, want %v", repo.merged, tt.wantErr == "")
+			}
+		})
+	}
+}
@@ -0,0 +1,448 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"strings"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/playlistfmt"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"github.com/ZnNr/songs-library/internal/tracing"
+	"github.com/ZnNr/songs-library/internal/validation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	shareTokenBytes = 16   // Количество случайных байт токена ссылки на плейлист
+	maxImportTracks = 1000 // Максимальное количество треков в импортируемом файле плейлиста
+)
+
+// Причины, по которым трек импортируемого файла не добавлен в плейлист.
+const (
+	importMissingMetadata = "missing_metadata"
+	importNotFound        = "not_found"
+)
+
+type PlaylistService struct {
+	repo   repository.PlaylistRepository
+	logger *zap.Logger
+	tracer trace.Tracer
+}
+
+func NewPlaylistService(repo repository.PlaylistRepository, logger *zap.Logger) *PlaylistService {
+	return &PlaylistService{
+		repo:   repo,
+		logger: logger,
+		tracer: tracing.Tracer("service"),
+	}
+}
+
+// log возвращает логгер запроса из контекста или логгер сервиса.
+func (s *PlaylistService) log(ctx context.Context) *zap.Logger {
+	return logctx.From(ctx, s.logger)
+}
+
+// GetPlaylists получает список плейлистов текущего пользователя.
+func (s *PlaylistService) GetPlaylists(ctx context.Context, page, pageSize int) (*models.PlaylistsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.GetPlaylists")
+	defer span.End()
+
+	owner, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.log(ctx).Info("Getting playlists", zap.Int("page", page), zap.Int("pageSize", pageSize))
+
+	v := validation.New()
+	validatePagination(v, page, pageSize)
+	if err := v.Err("invalid pagination"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPlaylists(ctx, owner, page, pageSize)
+}
+
+// GetPlaylist получает плейлист и страницу его элементов. Чужой плейлист доступен, только если он
+// публичный или доступен по ссылке и передан верный токен; иначе плейлист считается несуществующим.
+func (s *PlaylistService) GetPlaylist(ctx context.Context, id int, token string, page, pageSize int) (*models.PlaylistResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.GetPlaylist")
+	defer span.End()
+
+	s.log(ctx).Info("Getting playlist", zap.Int("id", id), zap.Int("page", page), zap.Int("pageSize", pageSize))
+
+	v := validation.New()
+	validatePagination(v, page, pageSize)
+	if err := v.Err("invalid pagination"); err != nil {
+		return nil, err
+	}
+
+	playlist, err := s.visiblePlaylist(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.GetPlaylistEntries(ctx, id, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &models.PlaylistResponse{Playlist: *playlist, PlaylistEntries: *entries}, nil
+}
+
+// CreatePlaylist создает плейлист текущего пользователя. Для плейлиста, доступного по ссылке,
+// генерируется токен ссылки.
+func (s *PlaylistService) CreatePlaylist(ctx context.Context, req *models.PlaylistRequest) (*models.Playlist, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.CreatePlaylist")
+	defer span.End()
+
+	owner, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.log(ctx).Info("Creating playlist", zap.String("name", req.Name), zap.String("visibility", string(req.Visibility)))
+
+	if err := validatePlaylistRequest(req); err != nil {
+		return nil, err
+	}
+	playlist := &models.Playlist{Owner: owner, Name: req.Name, Visibility: req.Visibility}
+	if err := assignShareToken(playlist, ""); err != nil {
+		return nil, err
+	}
+	return s.repo.CreatePlaylist(ctx, playlist)
+}
+
+// UpdatePlaylist переименовывает плейлист и меняет его видимость. Токен ссылки сохраняется, пока
+// плейлист остается доступным по ссылке, и отзывается при смене видимости.
+func (s *PlaylistService) UpdatePlaylist(ctx context.Context, id int, req *models.PlaylistRequest) (*models.Playlist, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.UpdatePlaylist")
+	defer span.End()
+
+	s.log(ctx).Info("Updating playlist",
+		zap.Int("id", id),
+		zap.String("name", req.Name),
+		zap.String("visibility", string(req.Visibility)))
+
+	if err := validatePlaylistRequest(req); err != nil {
+		return nil, err
+	}
+	playlist, err := s.ownedPlaylist(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token := playlist.ShareToken
+	playlist.Name = req.Name
+	playlist.Visibility = req.Visibility
+	if err := assignShareToken(playlist, token); err != nil {
+		return nil, err
+	}
+	return s.repo.UpdatePlaylist(ctx, playlist)
+}
+
+// DeletePlaylist удаляет плейлист текущего пользователя. Песни плейлиста не удаляются.
+func (s *PlaylistService) DeletePlaylist(ctx context.Context, id int) error {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.DeletePlaylist")
+	defer span.End()
+
+	s.log(ctx).Info("Deleting playlist", zap.Int("id", id))
+
+	if _, err := s.ownedPlaylist(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.DeletePlaylist(ctx, id)
+}
+
+// AddPlaylistEntry добавляет песню в плейлист на указанный порядковый номер или в конец.
+func (s *PlaylistService) AddPlaylistEntry(ctx context.Context, id int, req *models.PlaylistEntryRequest) (*models.PlaylistEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.AddPlaylistEntry")
+	defer span.End()
+
+	s.log(ctx).Info("Adding playlist entry",
+		zap.Int("id", id),
+		zap.Int("songId", req.SongID),
+		zap.Int("position", req.Position))
+
+	v := validation.New()
+	v.Check("song_id", req.SongID > 0, "must be positive")
+	v.Check("position", req.Position >= 0, "must not be negative")
+	if err := v.Err("invalid playlist entry request"); err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedPlaylist(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.AddPlaylistEntry(ctx, id, req.SongID, req.Position)
+}
+
+// MovePlaylistEntry перемещает элемент плейлиста на другой порядковый номер.
+func (s *PlaylistService) MovePlaylistEntry(ctx context.Context, id, entryID int, req *models.PlaylistPositionRequest) (*models.PlaylistEntry, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.MovePlaylistEntry")
+	defer span.End()
+
+	s.log(ctx).Info("Moving playlist entry",
+		zap.Int("id", id),
+		zap.Int("entryId", entryID),
+		zap.Int("position", req.Position))
+
+	v := validation.New()
+	v.Check("position", req.Position > 0, "must be positive")
+	if err := v.Err("invalid playlist entry request"); err != nil {
+		return nil, err
+	}
+	if _, err := s.ownedPlaylist(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.repo.MovePlaylistEntry(ctx, id, entryID, req.Position)
+}
+
+// RemovePlaylistEntry удаляет элемент из плейлиста.
+func (s *PlaylistService) RemovePlaylistEntry(ctx context.Context, id, entryID int) error {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.RemovePlaylistEntry")
+	defer span.End()
+
+	s.log(ctx).Info("Removing playlist entry", zap.Int("id", id), zap.Int("entryId", entryID))
+
+	if _, err := s.ownedPlaylist(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.RemovePlaylistEntry(ctx, id, entryID)
+}
+
+// ExportPlaylist кодирует плейлист в файл формата format. Права на просмотр те же, что у GetPlaylist.
+// Песни в корзине в файл не попадают, ссылкой трека служит ссылка песни.
+func (s *PlaylistService) ExportPlaylist(ctx context.Context, id int, token, format string) ([]byte, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.ExportPlaylist")
+	defer span.End()
+
+	s.log(ctx).Info("Exporting playlist", zap.Int("id", id), zap.String("format", format))
+
+	if _, err := playlistfmt.ContentType(format); err != nil {
+		return nil, errors.NewBadRequest(unsupportedFormatMessage(format), err)
+	}
+
+	playlist, err := s.visiblePlaylist(ctx, id, token)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &playlistfmt.Playlist{Title: playlist.Name}
+	for page := 1; ; page++ {
+		entries, err := s.repo.GetPlaylistEntries(ctx, id, page, maxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries.Entries {
+			if entry.Song == nil {
+				continue
+			}
+			file.Tracks = append(file.Tracks, playlistfmt.Track{
+				Creator:  entry.Song.GroupName,
+				Title:    entry.Song.SongName,
+				Location: entry.Song.Link,
+			})
+		}
+		if page >= entries.TotalPages {
+			break
+		}
+	}
+
+	data, err := playlistfmt.Encode(format, file)
+	if err != nil {
+		return nil, errors.NewInternal("failed to encode playlist", err)
+	}
+	return data, nil
+}
+
+// unsupportedFormatMessage возвращает сообщение о неподдерживаемом формате со списком поддерживаемых.
+func unsupportedFormatMessage(format string) string {
+	return fmt.Sprintf("unsupported playlist format %q, supported formats: %s", format, strings.Join(playlistfmt.Formats(), ", "))
+}
+
+// ImportPlaylist создает плейлист текущего пользователя из файла формата format. Треки сопоставляются
+// песням библиотеки по исполнителю и названию, сначала точно, затем нечетко; несопоставленные треки
+// возвращаются в ответе. Если название не задано в запросе, используется название из файла.
+func (s *PlaylistService) ImportPlaylist(ctx context.Context, format string, data []byte, req *models.PlaylistRequest) (*models.PlaylistImportResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "PlaylistService.ImportPlaylist")
+	defer span.End()
+
+	owner, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.log(ctx).Info("Importing playlist", zap.String("format", format), zap.Int("size", len(data)))
+
+	file, err := playlistfmt.Decode(format, data)
+	if stderrors.Is(err, playlistfmt.ErrUnsupportedFormat) {
+		return nil, errors.NewUnsupportedMediaType(unsupportedFormatMessage(format), err)
+	} else if err != nil {
+		return nil, errors.NewBadRequest("invalid playlist file", err)
+	}
+
+	if validation.NormalizeName(req.Name) == "" {
+		req.Name = file.Title
+	}
+	if err := validatePlaylistRequest(req); err != nil {
+		return nil, err
+	}
+	v := validation.New()
+	v.Check("track", len(file.Tracks) <= maxImportTracks, fmt.Sprintf("must not contain more than %d tracks", maxImportTracks))
+	if err := v.Err("invalid playlist file"); err != nil {
+		return nil, err
+	}
+
+	playlist := &models.Playlist{Owner: owner, Name: req.Name, Visibility: req.Visibility}
+	if err := assignShareToken(playlist, ""); err != nil {
+		return nil, err
+	}
+
+	var response *models.PlaylistImportResponse
+	err = s.repo.WithTx(ctx, func(repo repository.PlaylistRepository) error {
+		response = &models.PlaylistImportResponse{
+			Matched:   []models.PlaylistImportMatch{},
+			Unmatched: []models.PlaylistImportMiss{},
+		}
+		created, err := repo.CreatePlaylist(ctx, playlist)
+		if err != nil {
+			return err
+		}
+
+		for i, track := range file.Tracks {
+			creator, title := validation.NormalizeName(track.Creator), validation.NormalizeName(track.Title)
+			miss := models.PlaylistImportMiss{Index: i + 1, Creator: creator, Title: title, Location: track.Location}
+			if creator == "" || title == "" {
+				miss.Reason = importMissingMetadata
+				response.Unmatched = append(response.Unmatched, miss)
+				continue
+			}
+
+			match, err := repo.MatchSong(ctx, creator, title)
+			if err != nil {
+				return err
+			}
+			if match == nil {
+				miss.Reason = importNotFound
+				response.Unmatched = append(response.Unmatched, miss)
+				continue
+			}
+
+			entry, err := repo.AddPlaylistEntry(ctx, created.ID, match.SongID, 0)
+			if err != nil {
+				return err
+			}
+			response.Matched = append(response.Matched, models.PlaylistImportMatch{
+				Index:   i + 1,
+				Creator: creator,
+				Title:   title,
+				SongID:  match.SongID,
+				EntryID: entry.ID,
+				Fuzzy:   !match.Exact,
+			})
+		}
+
+		created, err = repo.GetPlaylistByID(ctx, created.ID)
+		if err != nil {
+			return err
+		}
+		response.Playlist = *created
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).Info("Playlist imported",
+		zap.Int("id", response.Playlist.ID),
+		zap.Int("matched", len(response.Matched)),
+		zap.Int("unmatched", len(response.Unmatched)))
+	return response, nil
+}
+
+// visiblePlaylist получает плейлист, если текущий пользователь может его просматривать.
+// Токен ссылки возвращается только владельцу.
+func (s *PlaylistService) visiblePlaylist(ctx context.Context, id int, token string) (*models.Playlist, error) {
+	playlist, err := s.repo.GetPlaylistByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if owner := logctx.User(ctx); owner != "" && owner == playlist.Owner {
+		return playlist, nil
+	}
+
+	switch {
+	case playlist.Visibility == models.VisibilityPublic:
+	case playlist.Visibility == models.VisibilityUnlisted && token != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(playlist.ShareToken)) == 1:
+	default:
+		return nil, errors.NewNotFound("playlist not found", nil)
+	}
+	playlist.ShareToken = ""
+	return playlist, nil
+}
+
+// ownedPlaylist получает плейлист, если он принадлежит текущему пользователю. Существование чужих
+// плейлистов не раскрывается.
+func (s *PlaylistService) ownedPlaylist(ctx context.Context, id int) (*models.Playlist, error) {
+	owner, err := authenticatedUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	playlist, err := s.repo.GetPlaylistByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.Owner != owner {
+		return nil, errors.NewNotFound("playlist not found", nil)
+	}
+	return playlist, nil
+}
+
+// authenticatedUser возвращает пользователя, аутентифицированного AuthMiddleware, или ошибку для
+// анонимного запроса. Заявленное в заголовках имя без проверки владельцем не считается.
+func authenticatedUser(ctx context.Context) (string, error) {
+	owner := logctx.User(ctx)
+	if owner == "" {
+		return "", errors.NewUnauthorized("authentication required", nil)
+	}
+	return owner, nil
+}
+
+// validatePlaylistRequest нормализует и проверяет запрос на создание или изменение плейлиста.
+// Пустая видимость означает приватный плейлист.
+func validatePlaylistRequest(req *models.PlaylistRequest) error {
+	req.Name = validation.NormalizeName(req.Name)
+	if req.Visibility == "" {
+		req.Visibility = models.VisibilityPrivate
+	}
+
+	v := validation.New()
+	v.Field("name", req.Name, validation.Required(), validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Check("visibility", req.Visibility == models.VisibilityPrivate ||
+		req.Visibility == models.VisibilityUnlisted || req.Visibility == models.VisibilityPublic,
+		"must be one of private, unlisted, public")
+	return v.Err("invalid playlist request")
+}
+
+// assignShareToken назначает токен ссылки плейлисту, доступному по ссылке: сохраняет текущий токен
+// current или генерирует новый. У остальных плейлистов токен сбрасывается.
+func assignShareToken(playlist *models.Playlist, current string) error {
+	if playlist.Visibility != models.VisibilityUnlisted {
+		playlist.ShareToken = ""
+		return nil
+	}
+	if current != "" {
+		playlist.ShareToken = current
+		return nil
+	}
+
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return errors.NewInternal("failed to generate share token", err)
+	}
+	playlist.ShareToken = hex.EncodeToString(b)
+	return nil
+}
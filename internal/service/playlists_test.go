@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	stderrors "errors"
+	"strings"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// playlistRepoStub реализует только методы плейлистов, используемые проверками доступа.
+// Вызов остальных методов встроенного nil-интерфейса завершает тест паникой.
+type playlistRepoStub struct {
+	repository.PlaylistRepository
+	playlist *models.Playlist
+	deleted  bool
+}
+
+func (r *playlistRepoStub) GetPlaylistByID(ctx context.Context, id int) (*models.Playlist, error) {
+	playlist := *r.playlist
+	return &playlist, nil
+}
+
+func (r *playlistRepoStub) GetPlaylistEntries(ctx context.Context, playlistID, page, pageSize int) (*models.PlaylistEntries, error) {
+	return &models.PlaylistEntries{Entries: []models.PlaylistEntry{}}, nil
+}
+
+func (r *playlistRepoStub) DeletePlaylist(ctx context.Context, id int) error {
+	r.deleted = true
+	return nil
+}
+
+func TestPlaylistAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		visibility models.PlaylistVisibility
+		ctx        func(ctx context.Context) context.Context
+		token      string
+		wantRead   errors.ErrorType // пустой тип - чтение разрешено
+		wantDelete errors.ErrorType // пустой тип - удаление разрешено
+	}{
+		{
+			name:       "owner",
+			visibility: models.VisibilityPrivate,
+			ctx:        func(ctx context.Context) context.Context { return logctx.WithUser(ctx, "alice") },
+		},
+		{
+			name:       "claimed principal is not the owner",
+			visibility: models.VisibilityPrivate,
+			ctx:        func(ctx context.Context) context.Context { return logctx.WithPrincipal(ctx, "alice") },
+			wantRead:   errors.NotFound,
+			wantDelete: errors.Unauthorized,
+		},
+		{
+			name:       "other user, private",
+			visibility: models.VisibilityPrivate,
+			ctx:        func(ctx context.Context) context.Context { return logctx.WithUser(ctx, "bob") },
+			wantRead:   errors.NotFound,
+			wantDelete: errors.NotFound,
+		},
+		{
+			name:       "other user, public",
+			visibility: models.VisibilityPublic,
+			ctx:        func(ctx context.Context) context.Context { return logctx.WithUser(ctx, "bob") },
+			wantDelete: errors.NotFound,
+		},
+		{
+			name:       "anonymous, unlisted with token",
+			visibility: models.VisibilityUnlisted,
+			ctx:        func(ctx context.Context) context.Context { return ctx },
+			token:      "token",
+			wantDelete: errors.Unauthorized,
+		},
+		{
+			name:       "anonymous, unlisted with wrong token",
+			visibility: models.VisibilityUnlisted,
+			ctx:        func(ctx context.Context) context.Context { return ctx },
+			token:      "guess",
+			wantRead:   errors.NotFound,
+			wantDelete: errors.Unauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			playlist := &models.Playlist{ID: 1, Owner: "alice", Name: "Mix", Visibility: tt.visibility}
+			if tt.visibility == models.VisibilityUnlisted {
+				playlist.ShareToken = "token"
+			}
+			repo := &playlistRepoStub{playlist: playlist}
+			svc := NewPlaylistService(repo, zap.NewNop())
+			ctx := tt.ctx(context.Background())
+
+			resp, err := svc.GetPlaylist(ctx, 1, tt.token, 1, 10)
+			checkErrorType(t, "GetPlaylist", err, tt.wantRead)
+			if err == nil && tt.name != "owner" && resp.ShareToken != "" {
+				t.Error("share token returned to a non-owner")
+			}
+
+			err = svc.DeletePlaylist(ctx, 1)
+			checkErrorType(t, "DeletePlaylist", err, tt.wantDelete)
+			if repo.deleted != (tt.wantDelete == "") {
+				t.Errorf("deleted = %v, want %v", repo.deleted, tt.wantDelete == "")
+			}
+		})
+	}
+}
+
+func TestPlaylistUnsupportedFormat(t *testing.T) {
+	ctx := logctx.WithUser(context.Background(), "alice")
+	// Формат проверяется до обращения к репозиторию
+	svc := NewPlaylistService(&playlistRepoStub{}, zap.NewNop())
+
+	_, err := svc.ExportPlaylist(ctx, 1, "", "pls")
+	checkErrorType(t, "ExportPlaylist", err, errors.BadRequest)
+	if err == nil || !strings.Contains(err.Error(), "m3u8, xspf, jspf") {
+		t.Errorf("ExportPlaylist: error %v does not list the supported formats", err)
+	}
+
+	_, err = svc.ImportPlaylist(ctx, "pls", []byte("[playlist]"), &models.PlaylistRequest{})
+	checkErrorType(t, "ImportPlaylist", err, errors.UnsupportedMediaType)
+	if err == nil || !strings.Contains(err.Error(), "m3u8, xspf, jspf") {
+		t.Errorf("ImportPlaylist: error %v does not list the supported formats", err)
+	}
+}
+
+// checkErrorType проверяет, что err - ошибка приложения типа want или nil, если want пуст.
+func checkErrorType(t *testing.T, op string, err error, want errors.ErrorType) {
+	t.Helper()
+	if want == "" {
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", op, err)
+		}
+		return
+	}
+	var appErr *errors.Error
+	if !stderrors.As(err, &appErr) || appErr.Type != want {
+		t.Errorf("%s: error = %v, want %s", op, err, want)
+	}
+}
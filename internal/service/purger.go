@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// TrashPurger периодически окончательно удаляет песни, срок хранения которых в корзине истек.
+type TrashPurger struct {
+	repo      repository.SongRepository
+	logger    *zap.Logger
+	retention time.Duration
+	interval  time.Duration
+	started   atomic.Bool
+	stopOnce  sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewTrashPurger создает очистку корзины, удаляющую песни старше retention каждые interval.
+// Очистка начинается после вызова Start.
+func NewTrashPurger(repo repository.SongRepository, logger *zap.Logger, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{
+		repo:      repo,
+		logger:    logger,
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Start запускает фоновую очистку корзины. Повторные вызовы игнорируются.
+func (p *TrashPurger) Start() {
+	if !p.started.CompareAndSwap(false, true) {
+		return
+	}
+	p.logger.Info("Starting trash purger",
+		zap.Duration("retention", p.retention),
+		zap.Duration("interval", p.interval))
+
+	go p.run()
+}
+
+// Stop останавливает фоновую очистку и ожидает завершения текущего прохода.
+// Если очистка не запускалась, возвращается сразу. Повторные вызовы безопасны.
+func (p *TrashPurger) Stop(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	if !p.started.Load() {
+		return nil
+	}
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run выполняет очистку сразу после запуска и затем с заданной периодичностью.
+func (p *TrashPurger) run() {
+	defer close(p.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// purge удаляет песни, перемещенные в корзину раньше срока хранения.
+func (p *TrashPurger) purge(ctx context.Context) {
+	before := time.Now().Add(-p.retention)
+	purged, err := p.repo.PurgeExpiredSongs(ctx, before)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.logger.Error("Failed to purge trash", zap.Error(err))
+		}
+		return
+	}
+	if purged > 0 {
+		p.logger.Info("Trash purged", zap.Int64("purged", purged), zap.Time("before", before))
+	}
+}
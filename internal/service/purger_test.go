@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZnNr/songs-library/internal/repository"
+	"go.uber.org/zap"
+)
+
+// purgeRepoStub считает проходы очистки и запоминает границу последнего из них.
+type purgeRepoStub struct {
+	repository.SongRepository
+	calls  atomic.Int32
+	before atomic.Value
+}
+
+func (r *purgeRepoStub) PurgeExpiredSongs(ctx context.Context, before time.Time) (int64, error) {
+	r.calls.Add(1)
+	r.before.Store(before)
+	return 0, ctx.Err()
+}
+
+func TestTrashPurgerStopWithoutStart(t *testing.T) {
+	p := NewTrashPurger(&purgeRepoStub{}, zap.NewNop(), time.Hour, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := p.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Stop() without Start took %v", elapsed)
+	}
+}
+
+func TestTrashPurgerPurgesUntilStopped(t *testing.T) {
+	repo := &purgeRepoStub{}
+	p := NewTrashPurger(repo, zap.NewNop(), time.Hour, time.Millisecond)
+	p.Start()
+	p.Start()
+
+	deadline := time.Now().Add(time.Second)
+	for repo.calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := p.Stop(context.Background()); err != nil {
+		t.Fatalf("second Stop() error = %v", err)
+	}
+
+	calls := repo.calls.Load()
+	if calls < 3 {
+		t.Fatalf("purged %d times, want at least 3", calls)
+	}
+	if before := repo.before.Load().(time.Time); time.Since(before) < time.Hour {
+		t.Errorf("purge boundary %v is newer than the retention period", before)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if after := repo.calls.Load(); after != calls {
+		t.Errorf("purger kept running after Stop: %d passes, then %d", calls, after)
+	}
+}
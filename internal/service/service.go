@@ -1,31 +1,51 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"github.com/ZnNr/songs-library/internal/diff"
 	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/logctx"
 	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/patch"
 	"github.com/ZnNr/songs-library/internal/repository"
+	"github.com/ZnNr/songs-library/internal/tracing"
+	"github.com/ZnNr/songs-library/internal/validation"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type SongService struct {
 	repo   repository.SongRepository
 	logger *zap.Logger
+	tracer trace.Tracer
 }
 
 func NewSongService(repo repository.SongRepository, logger *zap.Logger) *SongService {
 	return &SongService{
 		repo:   repo,
 		logger: logger,
+		tracer: tracing.Tracer("service"),
 	}
 }
 
+// log возвращает логгер запроса из контекста или логгер сервиса.
+func (s *SongService) log(ctx context.Context) *zap.Logger {
+	return logctx.From(ctx, s.logger)
+}
+
 // GetSongs получает список песен с опциональным фильтром.
 func (s *SongService) GetSongs(ctx context.Context, filter *models.SongFilter) (*models.SongsResponse, error) {
-	s.logger.Info("Getting songs with filter",
+	ctx, span := s.tracer.Start(ctx, "SongService.GetSongs")
+	defer span.End()
+
+	s.log(ctx).Info("Getting songs with filter",
 		zap.String("group", filter.GroupName),
 		zap.String("song", filter.SongName),
 		zap.Any("fromDate", filter.FromDate),
@@ -33,11 +53,12 @@ func (s *SongService) GetSongs(ctx context.Context, filter *models.SongFilter) (
 		zap.String("text", filter.Text),
 		zap.String("link", filter.Link),
 		zap.Int("page", filter.Page),
-		zap.Int("pageSize", filter.PageSize))
+		zap.Int("pageSize", filter.PageSize),
+		zap.String("count", string(filter.Count)))
 
 	// Валидация параметров фильтра
 	if err := validateFilter(filter); err != nil {
-		s.logger.Warn("Invalid filter", zap.Error(err))
+		s.log(ctx).Warn("Invalid filter", zap.Error(err))
 		return nil, err
 	}
 
@@ -46,14 +67,17 @@ func (s *SongService) GetSongs(ctx context.Context, filter *models.SongFilter) (
 
 // GetLyrics получает текст песни с опциональным фильтром.
 func (s *SongService) GetLyrics(ctx context.Context, id, page, pageSize int) (*models.LyricsResponse, error) {
-	s.logger.Info("Getting lyrics",
+	ctx, span := s.tracer.Start(ctx, "SongService.GetLyrics")
+	defer span.End()
+
+	s.log(ctx).Info("Getting lyrics",
 		zap.Int("songId", id),
 		zap.Int("page", page),
 		zap.Int("pageSize", pageSize))
 
 	song, err := s.repo.GetSongByID(ctx, id)
 	if err != nil {
-		return nil, errors.NewNotFound("song not found", err)
+		return nil, err
 	}
 
 	if song.Text == "" {
@@ -85,12 +109,17 @@ func (s *SongService) GetLyrics(ctx context.Context, id, page, pageSize int) (*m
 		CurrentPage: page,
 		TotalPages:  totalPages,
 		PageSize:    pageSize,
+		Version:     song.Version,
+		UpdatedAt:   song.UpdatedAt,
 	}, nil
 }
 
 // CreateSong создает новую песню.
 func (s *SongService) CreateSong(ctx context.Context, req *models.SongRequest) (*models.Song, error) {
-	s.logger.Info("Creating new song",
+	ctx, span := s.tracer.Start(ctx, "SongService.CreateSong")
+	defer span.End()
+
+	s.log(ctx).Info("Creating new song",
 		zap.String("group", req.GroupName),
 		zap.String("song", req.SongName))
 
@@ -98,69 +127,460 @@ func (s *SongService) CreateSong(ctx context.Context, req *models.SongRequest) (
 		return nil, err
 	}
 
-	song := &models.Song{
-		GroupName:   req.GroupName,
-		SongName:    req.SongName,
-		ReleaseDate: time.Now(),
-		Text:        req.Text,
-		Link:        req.Link,
+	song := &models.Song{}
+	if err := replaceSongFields(song, req); err != nil {
+		return nil, err
+	}
+
+	if req.AlbumID == 0 {
+		return s.repo.CreateSong(ctx, song)
 	}
 
-	return s.repo.CreateSong(ctx, song)
+	// Песня и трек альбома создаются атомарно, чтобы ошибка добавления в альбом не оставляла песню
+	var created *models.Song
+	err := s.repo.WithTx(ctx, func(repo repository.Tx) error {
+		var err error
+		if created, err = repo.CreateSong(ctx, song); err != nil {
+			return err
+		}
+		_, err = repo.AddAlbumTrack(ctx, req.AlbumID, created.ID, req.TrackNumber)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// GetSong получает песню по идентификатору.
+func (s *SongService) GetSong(ctx context.Context, id int) (*models.Song, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.GetSong")
+	defer span.End()
+
+	s.log(ctx).Info("Getting song", zap.Int("id", id))
+	return s.repo.GetSongByID(ctx, id)
 }
 
-// UpdateSong обновляет существующую песню.
-func (s *SongService) UpdateSong(ctx context.Context, id int, req *models.SongRequest) (*models.Song, error) {
-	s.logger.Info("Updating song",
+// UpdateSong полностью заменяет изменяемые поля существующей песни.
+// Если version больше нуля, обновление выполняется только при совпадении версии песни.
+func (s *SongService) UpdateSong(ctx context.Context, id int, req *models.SongRequest, version int) (*models.Song, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.UpdateSong")
+	defer span.End()
+
+	s.log(ctx).Info("Updating song",
 		zap.Int("id", id),
 		zap.String("group", req.GroupName),
-		zap.String("song", req.SongName))
+		zap.String("song", req.SongName),
+		zap.Int("version", version))
 
-	song, err := s.repo.GetSongByID(ctx, id)
+	if err := validateSongRequest(req); err != nil {
+		return nil, err
+	}
+	if req.AlbumID != 0 {
+		return nil, errors.NewValidationFields("invalid song request", []errors.FieldError{
+			{Field: "album_id", Message: "can only be set when creating a song, use the album tracks endpoints"},
+		})
+	}
+
+	// Чтение, проверка версии и обновление выполняются в одной транзакции на основной базе данных
+	var updated *models.Song
+	err := s.repo.WithTx(ctx, func(repo repository.Tx) error {
+		song, err := repo.GetSongByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(song, version); err != nil {
+			return err
+		}
+
+		if err := replaceSongFields(song, req); err != nil {
+			return err
+		}
+		song.UpdatedAt = time.Now()
+
+		updated, err = repo.UpdateSong(ctx, song)
+		return err
+	})
 	if err != nil {
-		return nil, errors.NewNotFound("song not found", err)
+		return nil, err
 	}
+	return updated, nil
+}
+
+// PatchSong частично изменяет песню с помощью JSON Merge Patch (RFC 7396) или JSON Patch (RFC 6902).
+// Изменения применяются к документу models.SongDocument, значение null очищает необязательные поля.
+// Если version больше нуля, изменение выполняется только при совпадении версии песни.
+func (s *SongService) PatchSong(ctx context.Context, id int, patchType string, patchDoc []byte, version int) (*models.Song, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.PatchSong")
+	defer span.End()
+
+	s.log(ctx).Info("Patching song",
+		zap.Int("id", id),
+		zap.String("patchType", patchType),
+		zap.Int("version", version))
 
-	// Обновляем только предоставленные поля
-	updateSongFields(song, req)
-	song.UpdatedAt = time.Now()
+	var updated *models.Song
+	err := s.repo.WithTx(ctx, func(repo repository.Tx) error {
+		song, err := repo.GetSongByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(song, version); err != nil {
+			return err
+		}
+		if err := patchSong(song, patchType, patchDoc); err != nil {
+			return err
+		}
+		song.UpdatedAt = time.Now()
 
-	return s.repo.UpdateSong(ctx, song)
+		updated, err = repo.UpdateSong(ctx, song)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
 }
 
-// DeleteSong удаляет существующую песню.
-func (s *SongService) DeleteSong(ctx context.Context, id int) error {
-	s.logger.Info("Deleting song", zap.Int("id", id))
-	return s.repo.DeleteSong(ctx, id)
+// patchSong применяет документ изменений patchDoc типа patchType к изменяемым полям песни.
+func patchSong(song *models.Song, patchType string, patchDoc []byte) error {
+	doc, err := json.Marshal(songDocument(song))
+	if err != nil {
+		return errors.NewInternal("failed to encode song document", err)
+	}
+
+	var patched []byte
+	switch patchType {
+	case patch.MergePatchType:
+		patched, err = patch.MergePatch(doc, patchDoc)
+	case patch.JSONPatchType:
+		patched, err = patch.ApplyJSONPatch(doc, patchDoc)
+	default:
+		return errors.NewUnsupportedMediaType(fmt.Sprintf("unsupported patch type %q", patchType), nil)
+	}
+	if err != nil {
+		if stderrors.Is(err, patch.ErrMalformedPatch) {
+			return errors.NewBadRequest("invalid patch document", err)
+		}
+		return errors.NewValidation("failed to apply patch", err)
+	}
+
+	return applySongDocument(song, patched)
+}
+
+// DeleteSong перемещает существующую песню в корзину.
+// Если version больше нуля, удаление выполняется только при совпадении версии песни.
+func (s *SongService) DeleteSong(ctx context.Context, id, version int) error {
+	ctx, span := s.tracer.Start(ctx, "SongService.DeleteSong")
+	defer span.End()
+
+	s.log(ctx).Info("Deleting song", zap.Int("id", id), zap.Int("version", version))
+	return s.repo.DeleteSong(ctx, id, version)
+}
+
+// GetTrash получает список песен из корзины.
+func (s *SongService) GetTrash(ctx context.Context, page, pageSize int) (*models.SongsResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.GetTrash")
+	defer span.End()
+
+	s.log(ctx).Info("Getting trash", zap.Int("page", page), zap.Int("pageSize", pageSize))
+
+	v := validation.New()
+	validatePagination(v, page, pageSize)
+	if err := v.Err("invalid pagination"); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDeletedSongs(ctx, page, pageSize)
 }
 
-// validateFilter выполняет проверку валидации фильтра песен.
+// RestoreSong восстанавливает песню из корзины.
+func (s *SongService) RestoreSong(ctx context.Context, id int) (*models.Song, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.RestoreSong")
+	defer span.End()
+
+	s.log(ctx).Info("Restoring song", zap.Int("id", id))
+	return s.repo.RestoreSong(ctx, id)
+}
+
+// PurgeSong окончательно удаляет песню из корзины.
+func (s *SongService) PurgeSong(ctx context.Context, id int) error {
+	ctx, span := s.tracer.Start(ctx, "SongService.PurgeSong")
+	defer span.End()
+
+	s.log(ctx).Info("Purging song", zap.Int("id", id))
+	return s.repo.PurgeSong(ctx, id)
+}
+
+// GetRevisions получает историю ревизий песни.
+func (s *SongService) GetRevisions(ctx context.Context, id int) ([]models.SongRevision, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.GetRevisions")
+	defer span.End()
+
+	s.log(ctx).Info("Getting song revisions", zap.Int("songId", id))
+
+	if _, err := s.repo.GetSongByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetRevisions(ctx, id)
+}
+
+// DiffRevisions сравнивает две ревизии песни.
+// Если to не указан, используется последняя ревизия, если не указан from - предшествующая ей.
+func (s *SongService) DiffRevisions(ctx context.Context, id, from, to int) (*models.SongDiff, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.DiffRevisions")
+	defer span.End()
+
+	s.log(ctx).Info("Diffing song revisions",
+		zap.Int("songId", id),
+		zap.Int("from", from),
+		zap.Int("to", to))
+
+	revisions, err := s.GetRevisions(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, errors.NewNotFound("song has no revisions", nil)
+	}
+
+	if to == 0 {
+		to = revisions[len(revisions)-1].Revision
+	}
+	if from == 0 {
+		from = max(to-1, 1)
+	}
+
+	fromRev, err := findRevision(revisions, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := findRevision(revisions, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SongDiff{
+		SongID: id,
+		From:   from,
+		To:     to,
+		Fields: diffRevisionFields(fromRev, toRev),
+		LyricsDiff: diff.Unified(fromRev.Text, toRev.Text,
+			fmt.Sprintf("revision %d", from), fmt.Sprintf("revision %d", to), diff.DefaultContext),
+	}, nil
+}
+
+// RevertSong восстанавливает состояние песни из указанной ревизии, сохраняя его как новую ревизию.
+// Если version больше нуля, откат выполняется только при совпадении версии песни.
+func (s *SongService) RevertSong(ctx context.Context, id, revision, version int) (*models.Song, error) {
+	ctx, span := s.tracer.Start(ctx, "SongService.RevertSong")
+	defer span.End()
+
+	s.log(ctx).Info("Reverting song",
+		zap.Int("id", id),
+		zap.Int("revision", revision),
+		zap.Int("version", version))
+
+	var updated *models.Song
+	err := s.repo.WithTx(ctx, func(repo repository.Tx) error {
+		song, err := repo.GetSongByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(song, version); err != nil {
+			return err
+		}
+
+		rev, err := repo.GetRevision(ctx, id, revision)
+		if err != nil {
+			return err
+		}
+
+		song.GroupName = rev.GroupName
+		song.SongName = rev.SongName
+		song.ReleaseDate = rev.ReleaseDate
+		song.Text = rev.Text
+		song.Link = rev.Link
+		song.UpdatedAt = time.Now()
+
+		updated, err = repo.UpdateSong(ctx, song)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// checkVersion проверяет, что версия песни совпадает с ожидаемой клиентом.
+// Окончательная проверка выполняется атомарно в запросе обновления.
+func checkVersion(song *models.Song, version int) error {
+	if version > 0 && song.Version != version {
+		return errors.NewPreconditionFailed(
+			fmt.Sprintf("song version mismatch: expected %d, current %d", version, song.Version), nil)
+	}
+	return nil
+}
+
+// findRevision ищет ревизию по номеру в списке ревизий.
+func findRevision(revisions []models.SongRevision, revision int) (*models.SongRevision, error) {
+	for i := range revisions {
+		if revisions[i].Revision == revision {
+			return &revisions[i], nil
+		}
+	}
+	return nil, errors.NewNotFound(fmt.Sprintf("revision %d not found", revision), nil)
+}
+
+// diffRevisionFields возвращает список изменившихся полей метаданных между ревизиями.
+func diffRevisionFields(from, to *models.SongRevision) []models.FieldChange {
+	changes := []models.FieldChange{}
+	compareField := func(field, a, b string) {
+		if a != b {
+			changes = append(changes, models.FieldChange{Field: field, From: a, To: b})
+		}
+	}
+
+	compareField("group_name", from.GroupName, to.GroupName)
+	compareField("song_name", from.SongName, to.SongName)
+	compareField("release_date", formatDate(from.ReleaseDate), formatDate(to.ReleaseDate))
+	compareField("link", from.Link, to.Link)
+	return changes
+}
+
+// formatDate форматирует необязательную дату выпуска, отсутствующая дата представляется пустой строкой.
+func formatDate(date *models.ReleaseDate) string {
+	if date == nil {
+		return ""
+	}
+	return date.String()
+}
+
+// Ограничения входных данных.
+const (
+	maxFieldLength = 255 // Длина колонок VARCHAR(255): group_name, song_name, link
+	maxPageSize    = 100 // Максимальный размер страницы списка песен
+)
+
+// releaseDateRule проверяет, что дата выпуска задана в одном из поддерживаемых форматов.
+func releaseDateRule(value string) string {
+	if value == "" {
+		return ""
+	}
+	if _, err := models.ParseReleaseDate(value); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// validateFilter выполняет проверку валидации фильтра песен и нормализует названия.
 func validateFilter(filter *models.SongFilter) error {
-	// Здесь можно добавить логику валидации
-	return nil // Замените на реальную логику валидации
+	filter.GroupName = validation.NormalizeName(filter.GroupName)
+	filter.SongName = validation.NormalizeName(filter.SongName)
+
+	v := validation.New()
+	v.Field("group_name", filter.GroupName, validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Field("song_name", filter.SongName, validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Field("text", filter.Text, validation.NoControlChars('\n', '\r', '\t'))
+	v.Field("link", filter.Link, validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Check("album", filter.AlbumID >= 0, "must not be negative")
+	validatePagination(v, filter.Page, filter.PageSize)
+	v.Check("count", filter.Count == "" || filter.Count == models.CountExact ||
+		filter.Count == models.CountEstimate || filter.Count == models.CountNone,
+		"must be one of exact, estimate, none")
+	if filter.FromDate != nil && filter.ToDate != nil {
+		v.Check("to_date", !filter.ToDate.Before(*filter.FromDate), "must not be before from_date")
+	}
+	return v.Err("invalid song filter")
+}
+
+// validatePagination проверяет параметры постраничной навигации. Значения по умолчанию для
+// отсутствующих параметров подставляют обработчики.
+func validatePagination(v *validation.Validator, page, pageSize int) {
+	v.Check("page", page >= 1, "must be at least 1")
+	v.Check("page_size", pageSize >= 1 && pageSize <= maxPageSize,
+		fmt.Sprintf("must be between 1 and %d", maxPageSize))
 }
 
 // validateSongRequest выполняет проверку валидности запроса на создание или обновление песни.
+// Названия группы и песни предварительно нормализуются, все нарушения возвращаются одной ошибкой.
 func validateSongRequest(req *models.SongRequest) error {
-	if req.SongName == "" {
-		return errors.NewValidation("song name cannot be empty", nil)
+	req.GroupName = validation.NormalizeName(req.GroupName)
+	req.SongName = validation.NormalizeName(req.SongName)
+	req.ReleaseDate = strings.TrimSpace(req.ReleaseDate)
+	req.Link = strings.TrimSpace(req.Link)
+
+	v := validation.New()
+	v.Field("group", req.GroupName,
+		validation.Required(), validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Field("song", req.SongName,
+		validation.Required(), validation.MaxLength(maxFieldLength), validation.NoControlChars())
+	v.Field("release_date", req.ReleaseDate, releaseDateRule)
+	v.Field("text", req.Text, validation.NoControlChars('\n', '\r', '\t'))
+	v.Field("link", req.Link,
+		validation.MaxLength(maxFieldLength), validation.NoControlChars(), validation.URL())
+	v.Check("album_id", req.AlbumID >= 0, "must not be negative")
+	v.Check("track_number", req.TrackNumber >= 0, "must not be negative")
+	v.Check("track_number", req.TrackNumber == 0 || req.AlbumID != 0, "requires album_id")
+	return v.Err("invalid song request")
+}
+
+// replaceSongFields заменяет изменяемые поля песни значениями из запроса.
+// Пустая дата выпуска означает, что дата неизвестна.
+func replaceSongFields(song *models.Song, req *models.SongRequest) error {
+	var releaseDate *models.ReleaseDate
+	if req.ReleaseDate != "" {
+		parsed, err := models.ParseReleaseDate(req.ReleaseDate)
+		if err != nil {
+			return errors.NewValidation("invalid release_date", err)
+		}
+		releaseDate = parsed
 	}
-	// Добавить дополнительные проверки при необходимости
+
+	song.GroupName = req.GroupName
+	song.SongName = req.SongName
+	song.ReleaseDate = releaseDate
+	song.Text = req.Text
+	song.Link = req.Link
 	return nil
 }
 
-// updateSongFields обновляет поля песни на основании запроса.
-func updateSongFields(song *models.Song, req *models.SongRequest) {
-	if req.GroupName != "" {
-		song.GroupName = req.GroupName
+// songDocument формирует документ изменяемых полей песни.
+func songDocument(song *models.Song) *models.SongDocument {
+	doc := &models.SongDocument{
+		GroupName: song.GroupName,
+		SongName:  song.SongName,
+		Text:      song.Text,
+		Link:      song.Link,
+	}
+	if song.ReleaseDate != nil {
+		releaseDate := song.ReleaseDate.String()
+		doc.ReleaseDate = &releaseDate
+	}
+	return doc
+}
+
+// applySongDocument проверяет измененный документ и переносит его значения в песню.
+func applySongDocument(song *models.Song, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var doc models.SongDocument
+	if err := dec.Decode(&doc); err != nil {
+		return errors.NewValidation("patched song is invalid", err)
 	}
-	if req.SongName != "" {
-		song.SongName = req.SongName
+
+	req := &models.SongRequest{
+		GroupName: doc.GroupName,
+		SongName:  doc.SongName,
+		Text:      doc.Text,
+		Link:      doc.Link,
 	}
-	if req.Text != "" {
-		song.Text = req.Text
+	if doc.ReleaseDate != nil {
+		req.ReleaseDate = *doc.ReleaseDate
 	}
-	if req.Link != "" {
-		song.Link = req.Link
+	if err := validateSongRequest(req); err != nil {
+		return err
 	}
+	return replaceSongFields(song, req)
 }
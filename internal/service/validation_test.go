@@ -0,0 +1,96 @@
+package service
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"github.com/ZnNr/songs-library/internal/models"
+	"github.com/ZnNr/songs-library/internal/validation"
+)
+
+func TestValidateSongRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        models.SongRequest
+		wantFields []string
+	}{
+		{
+			name: "valid request",
+			req:  models.SongRequest{GroupName: "Muse", SongName: "Uprising", ReleaseDate: "2009", Text: "line 1\nline 2", Link: "https://example.com"},
+		},
+		{
+			name:       "all violations are reported at once",
+			req:        models.SongRequest{GroupName: " ", SongName: strings.Repeat("x", maxFieldLength+1), ReleaseDate: "someday", Text: "a\x07", Link: "ftp://example.com"},
+			wantFields: []string{"group", "song", "release_date", "text", "link"},
+		},
+		{
+			name:       "control characters in names",
+			req:        models.SongRequest{GroupName: "Mu\tse", SongName: "Uprising\n"},
+			wantFields: []string{"group"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSongRequest(&tt.req)
+			var fields []string
+			var appErr *errors.Error
+			if stderrors.As(err, &appErr) {
+				for _, field := range appErr.Fields {
+					fields = append(fields, field.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error type: %v", err)
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.wantFields, ",") {
+				t.Errorf("invalid fields = %v, want %v (%v)", fields, tt.wantFields, err)
+			}
+		})
+	}
+}
+
+func TestValidateSongRequestNormalizes(t *testing.T) {
+	req := models.SongRequest{GroupName: "  Beyonce\u0301 ", SongName: " Halo ", ReleaseDate: " 2008 ", Link: " https://example.com "}
+	if err := validateSongRequest(&req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := models.SongRequest{GroupName: "Beyonc\u00e9", SongName: "Halo", ReleaseDate: "2008", Link: "https://example.com"}
+	if req != want {
+		t.Errorf("request = %+v, want %+v", req, want)
+	}
+}
+
+func TestValidatePagination(t *testing.T) {
+	for _, page := range [][2]int{{1, 1}, {7, maxPageSize}} {
+		v := validation.New()
+		validatePagination(v, page[0], page[1])
+		if err := v.Err("invalid pagination"); err != nil {
+			t.Errorf("page %d, size %d: unexpected error %v", page[0], page[1], err)
+		}
+	}
+
+	v := validation.New()
+	validatePagination(v, 0, 0)
+	var appErr *errors.Error
+	if !stderrors.As(v.Err("invalid pagination"), &appErr) || len(appErr.Fields) != 2 {
+		t.Fatalf("error = %v, want violations for page and page_size", v.Err("invalid pagination"))
+	}
+	want := []errors.FieldError{
+		{Field: "page", Message: "must be at least 1"},
+		{Field: "page_size", Message: fmt.Sprintf("must be between 1 and %d", maxPageSize)},
+	}
+	for i, field := range appErr.Fields {
+		if field != want[i] {
+			t.Errorf("field %d = %+v, want %+v", i, field, want[i])
+		}
+	}
+
+	v = validation.New()
+	validatePagination(v, 1, maxPageSize+1)
+	if v.Err("invalid pagination") == nil {
+		t.Errorf("page size %d accepted", maxPageSize+1)
+	}
+}
@@ -0,0 +1,92 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName - имя сервиса в трассировках.
+const ServiceName = "songs-library"
+
+// Поддерживаемые экспортеры трассировок.
+const (
+	ExporterNone   = "none"   // Спаны создаются (trace_id попадает в логи), но никуда не отправляются
+	ExporterStdout = "stdout" // Спаны выводятся в стандартный вывод
+	ExporterOTLP   = "otlp"   // Спаны отправляются в коллектор по OTLP/HTTP
+)
+
+// Config содержит настройки трассировки.
+type Config struct {
+	Exporter     string // Экспортер: none, stdout или otlp
+	OTLPEndpoint string // Адрес коллектора OTLP/HTTP (host:port)
+	OTLPInsecure bool   // Отправлять спаны в коллектор без TLS
+}
+
+// Setup настраивает глобальный TracerProvider и распространение контекста W3C Trace Context.
+// Возвращает функцию, которая отправляет оставшиеся спаны и освобождает ресурсы.
+func Setup(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	switch cfg.Exporter {
+	case ExporterNone, "":
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	case ExporterOTLP:
+		clientOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+		if cfg.OTLPInsecure {
+			clientOpts = append(clientOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	provider := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// Tracer возвращает трассировщик для компонента приложения.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(ServiceName + "/" + name)
+}
+
+// End завершает спан, отмечая его ошибкой, если err не nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Rule проверяет значение поля и возвращает описание нарушения или пустую строку, если значение корректно.
+// Все правила, кроме Required, пропускают пустые значения.
+type Rule func(value string) string
+
+// Validator накапливает нарушения по всем проверяемым полям.
+type Validator struct {
+	fields []errors.FieldError
+}
+
+// New создает новый валидатор без нарушений.
+func New() *Validator {
+	return &Validator{}
+}
+
+// Field проверяет значение поля набором правил. Для каждого поля фиксируется первое нарушение.
+func (v *Validator) Field(name, value string, rules ...Rule) {
+	for _, rule := range rules {
+		if message := rule(value); message != "" {
+			v.AddError(name, message)
+			return
+		}
+	}
+}
+
+// Check фиксирует нарушение для поля, если условие ok не выполняется.
+func (v *Validator) Check(name string, ok bool, message string) {
+	if !ok {
+		v.AddError(name, message)
+	}
+}
+
+// AddError фиксирует нарушение для поля.
+func (v *Validator) AddError(name, message string) {
+	v.fields = append(v.fields, errors.FieldError{Field: name, Message: message})
+}
+
+// Err возвращает ошибку типа Validation со всеми нарушениями или nil, если нарушений нет.
+func (v *Validator) Err(message string) error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return errors.NewValidationFields(message, v.fields)
+}
+
+// NormalizeName обрезает пробельные символы по краям и приводит строку к Unicode NFC.
+func NormalizeName(value string) string {
+	return norm.NFC.String(strings.TrimSpace(value))
+}
+
+// Required проверяет, что значение не пустое.
+func Required() Rule {
+	return func(value string) string {
+		if strings.TrimSpace(value) == "" {
+			return "is required"
+		}
+		return ""
+	}
+}
+
+// MaxLength проверяет, что значение содержит не больше max символов.
+func MaxLength(max int) Rule {
+	return func(value string) string {
+		if utf8.RuneCountInString(value) > max {
+			return fmt.Sprintf("must be at most %d characters long", max)
+		}
+		return ""
+	}
+}
+
+// NoControlChars проверяет, что значение является корректной UTF-8 строкой без управляющих символов.
+// Символы из allowed (например, перевод строки в тексте песни) допускаются.
+func NoControlChars(allowed ...rune) Rule {
+	return func(value string) string {
+		if !utf8.ValidString(value) {
+			return "must be valid UTF-8"
+		}
+		for _, r := range value {
+			if unicode.IsControl(r) && !containsRune(allowed, r) {
+				return fmt.Sprintf("must not contain control character %U", r)
+			}
+		}
+		return ""
+	}
+}
+
+// URL проверяет, что значение является абсолютным http или https адресом.
+func URL() Rule {
+	return func(value string) string {
+		if value == "" {
+			return ""
+		}
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "must be an absolute http or https URL"
+		}
+		return ""
+	}
+}
+
+// containsRune проверяет наличие символа в списке.
+func containsRune(runes []rune, r rune) bool {
+	for _, candidate := range runes {
+		if candidate == r {
+			return true
+		}
+	}
+	return false
+}
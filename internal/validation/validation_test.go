@@ -0,0 +1,80 @@
+package validation
+
+import (
+	stderrors "errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZnNr/songs-library/internal/errors"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  Rule
+		value string
+		want  string
+	}{
+		{name: "required accepts value", rule: Required(), value: "Muse"},
+		{name: "required rejects blank", rule: Required(), value: " \t", want: "is required"},
+		{name: "max length counts characters, not bytes", rule: MaxLength(5), value: "Тролль"[:len("Тролл")]},
+		{name: "max length rejects longer value", rule: MaxLength(5), value: "Тролль", want: "must be at most 5 characters long"},
+		{name: "control characters", rule: NoControlChars(), value: "a\x00b", want: "must not contain control character U+0000"},
+		{name: "allowed control characters", rule: NoControlChars('\n'), value: "line 1\nline 2"},
+		{name: "newline not allowed by default", rule: NoControlChars(), value: "line 1\nline 2", want: "must not contain control character U+000A"},
+		{name: "invalid UTF-8", rule: NoControlChars(), value: "\xff", want: "must be valid UTF-8"},
+		{name: "https URL", rule: URL(), value: "https://example.com/song"},
+		{name: "empty URL is skipped", rule: URL(), value: ""},
+		{name: "relative URL", rule: URL(), value: "/songs/1", want: "must be an absolute http or https URL"},
+		{name: "unsupported scheme", rule: URL(), value: "javascript:alert(1)", want: "must be an absolute http or https URL"},
+		{name: "URL without host", rule: URL(), value: "http://", want: "must be an absolute http or https URL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule(tt.value); got != tt.want {
+				t.Errorf("rule(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatorAggregatesFields(t *testing.T) {
+	v := New()
+	v.Field("group", "", Required(), MaxLength(3))
+	v.Field("song", strings.Repeat("x", 10), Required(), MaxLength(3), NoControlChars())
+	v.Field("link", "https://example.com", URL())
+	v.Check("page", false, "must not be negative")
+
+	err := v.Err("invalid song request")
+	var appErr *errors.Error
+	if !stderrors.As(err, &appErr) || appErr.Type != errors.Validation {
+		t.Fatalf("Err() = %v, want a validation error", err)
+	}
+	// По каждому полю фиксируется только первое нарушение, порядок полей сохраняется
+	want := []errors.FieldError{
+		{Field: "group", Message: "is required"},
+		{Field: "song", Message: "must be at most 3 characters long"},
+		{Field: "page", Message: "must not be negative"},
+	}
+	if !reflect.DeepEqual(appErr.Fields, want) {
+		t.Errorf("Fields = %+v, want %+v", appErr.Fields, want)
+	}
+}
+
+func TestValidatorWithoutViolations(t *testing.T) {
+	v := New()
+	v.Field("group", "Muse", Required())
+	v.Check("page", true, "must not be negative")
+	if err := v.Err("invalid song request"); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestNormalizeName(t *testing.T) {
+	// "é" в виде e и комбинируемого акута приводится к одному символу
+	if got, want := NormalizeName("  Beyonce\u0301 \n"), "Beyonc\u00e9"; got != want {
+		t.Errorf("NormalizeName() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,5 @@
+ALTER TABLE songs ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS songs_id_seq;
+ALTER TABLE songs ALTER COLUMN id TYPE VARCHAR(255) USING id::text;
+
+ALTER TABLE songs RENAME COLUMN text TO lyrics;
@@ -0,0 +1,19 @@
+ALTER TABLE songs RENAME COLUMN lyrics TO text;
+
+-- Нечисловые id (и числа с ведущими нулями или вне диапазона INTEGER) получают новые номера
+-- после максимального числового id в порядке создания; числовые id сохраняются как есть
+UPDATE songs s
+SET id = renumbered.new_id::text
+FROM (
+    SELECT songs.id, numeric_ids.max_id + ROW_NUMBER() OVER (ORDER BY songs.created_at, songs.id) AS new_id
+    FROM songs,
+         (SELECT COALESCE(MAX(id::integer), 0) AS max_id FROM songs WHERE id ~ '^[1-9][0-9]{0,8}$') numeric_ids
+    WHERE songs.id !~ '^[1-9][0-9]{0,8}$'
+) renumbered
+WHERE s.id = renumbered.id;
+
+ALTER TABLE songs ALTER COLUMN id TYPE INTEGER USING id::integer;
+
+CREATE SEQUENCE IF NOT EXISTS songs_id_seq OWNED BY songs.id;
+ALTER TABLE songs ALTER COLUMN id SET DEFAULT nextval('songs_id_seq');
+SELECT setval('songs_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM songs;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS song_revisions (
+                       id SERIAL PRIMARY KEY,
+                       song_id INTEGER NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+                       revision INTEGER NOT NULL,
+                       group_name VARCHAR(255) NOT NULL,
+                       song_name VARCHAR(255) NOT NULL,
+                       release_date DATE,
+                       text TEXT,
+                       link VARCHAR(255),
+                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                       UNIQUE (song_id, revision)
+);
+
+-- Существующие песни получают первую ревизию с текущим состоянием
+INSERT INTO song_revisions (song_id, revision, group_name, song_name, release_date, text, link, created_at)
+SELECT id, 1, group_name, song_name, release_date, text, link, updated_at
+FROM songs;
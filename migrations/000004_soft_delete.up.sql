@@ -0,0 +1,3 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_songs_deleted_at ON songs (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,7 @@
+ALTER TABLE songs
+    ADD COLUMN IF NOT EXISTS release_date_precision VARCHAR(5) NOT NULL DEFAULT 'day'
+        CHECK (release_date_precision IN ('day', 'month', 'year'));
+
+ALTER TABLE song_revisions
+    ADD COLUMN IF NOT EXISTS release_date_precision VARCHAR(5) NOT NULL DEFAULT 'day'
+        CHECK (release_date_precision IN ('day', 'month', 'year'));
@@ -0,0 +1,17 @@
+-- Дубликаты песни одной группы, кроме самой ранней, перемещаются в корзину,
+-- чтобы их можно было просмотреть и удалить вручную
+UPDATE songs
+SET deleted_at = NOW(),
+    version = version + 1
+WHERE deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM songs earlier
+    WHERE earlier.group_name = songs.group_name
+      AND earlier.song_name = songs.song_name
+      AND earlier.deleted_at IS NULL
+      AND (earlier.created_at, earlier.id) < (songs.created_at, songs.id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_group_song_unique
+    ON songs (group_name, song_name)
+    WHERE deleted_at IS NULL;
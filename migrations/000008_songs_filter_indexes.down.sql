@@ -0,0 +1,6 @@
+-- Расширение pg_trgm не удаляется: им могут пользоваться другие объекты базы данных
+DROP INDEX IF EXISTS idx_songs_created_at;
+DROP INDEX IF EXISTS idx_songs_release_date;
+DROP INDEX IF EXISTS idx_songs_text_trgm;
+DROP INDEX IF EXISTS idx_songs_song_name_trgm;
+DROP INDEX IF EXISTS idx_songs_group_name_trgm;
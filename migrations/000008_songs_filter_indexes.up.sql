@@ -0,0 +1,22 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Поиск подстроки ILIKE '%...%' по названию группы, песни и тексту
+CREATE INDEX IF NOT EXISTS idx_songs_group_name_trgm
+    ON songs USING gin (group_name gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_song_name_trgm
+    ON songs USING gin (song_name gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_text_trgm
+    ON songs USING gin (text gin_trgm_ops)
+    WHERE deleted_at IS NULL;
+
+-- Фильтр по периоду выпуска
+CREATE INDEX IF NOT EXISTS idx_songs_release_date
+    ON songs (release_date)
+    WHERE deleted_at IS NULL;
+
+-- Постраничный вывод в порядке создания
+CREATE INDEX IF NOT EXISTS idx_songs_created_at
+    ON songs (created_at DESC)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,20 @@
+-- Песни, перемещенные в корзину при объединении написаний группы, остаются в корзине
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS group_name VARCHAR(255);
+
+UPDATE songs
+SET group_name = artists.name
+FROM artists
+WHERE artists.id = songs.artist_id;
+
+ALTER TABLE songs ALTER COLUMN group_name SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_songs_artist_song_unique;
+ALTER TABLE songs DROP COLUMN IF EXISTS artist_id;
+DROP TABLE IF EXISTS artists;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_group_song_unique
+    ON songs (group_name, song_name)
+    WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_songs_group_name_trgm
+    ON songs USING gin (group_name gin_trgm_ops)
+    WHERE deleted_at IS NULL;
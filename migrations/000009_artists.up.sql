@@ -0,0 +1,46 @@
+CREATE TABLE IF NOT EXISTS artists (
+                       id SERIAL PRIMARY KEY,
+                       name VARCHAR(255) NOT NULL,
+                       created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Имена исполнителей уникальны без учета регистра
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artists_name_unique ON artists (lower(name));
+CREATE INDEX IF NOT EXISTS idx_artists_name_trgm ON artists USING gin (name gin_trgm_ops);
+
+-- Исполнители создаются из названий групп без учета регистра и пробелов по краям,
+-- каноническим становится написание самой ранней песни
+INSERT INTO artists (name)
+SELECT DISTINCT ON (lower(btrim(group_name))) btrim(group_name)
+FROM songs
+ORDER BY lower(btrim(group_name)), created_at, id;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS artist_id INTEGER REFERENCES artists(id);
+
+UPDATE songs
+SET artist_id = artists.id
+FROM artists
+WHERE lower(artists.name) = lower(btrim(songs.group_name));
+
+ALTER TABLE songs ALTER COLUMN artist_id SET NOT NULL;
+
+-- Песни, совпавшие по названию после объединения написаний группы, кроме самой ранней,
+-- перемещаются в корзину, чтобы их можно было просмотреть и удалить вручную
+UPDATE songs
+SET deleted_at = NOW(),
+    version = version + 1
+WHERE deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM songs earlier
+    WHERE earlier.artist_id = songs.artist_id
+      AND earlier.song_name = songs.song_name
+      AND earlier.deleted_at IS NULL
+      AND (earlier.created_at, earlier.id) < (songs.created_at, songs.id)
+);
+
+ALTER TABLE songs DROP COLUMN group_name;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_artist_song_unique
+    ON songs (artist_id, song_name)
+    WHERE deleted_at IS NULL;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS artist_aliases (
+                       id SERIAL PRIMARY KEY,
+                       artist_id INTEGER NOT NULL REFERENCES artists(id) ON DELETE CASCADE,
+                       name VARCHAR(255) NOT NULL,
+                       created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Псевдоним однозначно указывает на одного исполнителя без учета регистра
+CREATE UNIQUE INDEX IF NOT EXISTS idx_artist_aliases_name_unique ON artist_aliases (lower(name));
+CREATE INDEX IF NOT EXISTS idx_artist_aliases_artist_id ON artist_aliases (artist_id);
+CREATE INDEX IF NOT EXISTS idx_artist_aliases_name_trgm ON artist_aliases USING gin (name gin_trgm_ops);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS albums (
+                       id SERIAL PRIMARY KEY,
+                       artist_id INTEGER NOT NULL REFERENCES artists(id),
+                       title VARCHAR(255) NOT NULL,
+                       release_date DATE,
+                       release_date_precision VARCHAR(5) NOT NULL DEFAULT 'day'
+                           CHECK (release_date_precision IN ('day', 'month', 'year')),
+                       cover_url VARCHAR(255) NOT NULL DEFAULT '',
+                       created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Названия альбомов уникальны в пределах исполнителя без учета регистра
+CREATE UNIQUE INDEX IF NOT EXISTS idx_albums_artist_title_unique ON albums (artist_id, lower(title));
+CREATE INDEX IF NOT EXISTS idx_albums_title_trgm ON albums USING gin (title gin_trgm_ops);
+
+CREATE TABLE IF NOT EXISTS album_tracks (
+                       album_id INTEGER NOT NULL REFERENCES albums(id) ON DELETE CASCADE,
+                       song_id INTEGER NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+                       track_number INTEGER NOT NULL CHECK (track_number > 0),
+                       PRIMARY KEY (album_id, song_id),
+                       -- Уникальность проверяется в конце оператора, чтобы номера треков можно было сдвинуть одним UPDATE
+                       CONSTRAINT album_tracks_track_number_unique UNIQUE (album_id, track_number) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE INDEX IF NOT EXISTS idx_album_tracks_song_id ON album_tracks (song_id);
@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS playlists (
+                       id SERIAL PRIMARY KEY,
+                       owner VARCHAR(255) NOT NULL,
+                       name VARCHAR(255) NOT NULL,
+                       visibility VARCHAR(8) NOT NULL DEFAULT 'private'
+                           CHECK (visibility IN ('private', 'unlisted', 'public')),
+                       share_token VARCHAR(64),
+                       created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       -- Токен ссылки есть только у плейлистов, доступных по ссылке
+                       CHECK ((visibility = 'unlisted') = (share_token IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlists_owner ON playlists (owner, lower(name), id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_playlists_share_token ON playlists (share_token) WHERE share_token IS NOT NULL;
+
+-- Одна и та же песня может входить в плейлист несколько раз, поэтому элемент плейлиста имеет собственный
+-- идентификатор. Порядок задается разреженным ключом position: перемещение элемента меняет только его ключ
+CREATE TABLE IF NOT EXISTS playlist_entries (
+                       id SERIAL PRIMARY KEY,
+                       playlist_id INTEGER NOT NULL REFERENCES playlists(id) ON DELETE CASCADE,
+                       song_id INTEGER NOT NULL REFERENCES songs(id) ON DELETE CASCADE,
+                       position BIGINT NOT NULL CHECK (position > 0),
+                       added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                       -- Уникальность проверяется в конце оператора, чтобы ключи можно было перенумеровать одним UPDATE
+                       CONSTRAINT playlist_entries_position_unique UNIQUE (playlist_id, position) DEFERRABLE INITIALLY IMMEDIATE
+);
+
+CREATE INDEX IF NOT EXISTS idx_playlist_entries_song_id ON playlist_entries (song_id);